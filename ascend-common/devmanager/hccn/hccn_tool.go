@@ -53,6 +53,7 @@ const (
 	notSupport             = "not supported"
 	unknownStr             = "Unknown!"
 	generalMaxCardNum      = 16
+	hccnTool               = "/usr/local/Ascend/driver/tools/hccn_tool"
 )
 
 var (
@@ -60,8 +61,18 @@ var (
 	getLinkStatusFromHccnToolErrorMapLock = &sync.Mutex{}
 )
 
+// CheckHccnTool check whether the hccn_tool is available
+func CheckHccnTool() error {
+	if _, err := utils.CheckPath(hccnTool); err != nil {
+		return err
+	}
+	if !utils.IsExist(hccnTool) {
+		return fmt.Errorf("%s does not exist", hccnTool)
+	}
+	return nil
+}
+
 func getInfoFromHccnTool(args ...string) (string, error) {
-	const hccnTool = "/usr/local/Ascend/driver/tools/hccn_tool"
 	if _, err := utils.CheckPath(hccnTool); err != nil {
 		return "", err
 	}
@@ -30,9 +30,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/influxdata/telegraf/plugins/common/shim"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/professorshandian/npu-exporter/ascend-common/api"
 	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/hwlog"
 	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/limiter"
 	"github.com/professorshandian/npu-exporter/ascend-common/devmanager"
@@ -41,8 +43,14 @@ import (
 	colcommon "github.com/professorshandian/npu-exporter/collector/common"
 	"github.com/professorshandian/npu-exporter/collector/config"
 	"github.com/professorshandian/npu-exporter/collector/container"
+	"github.com/professorshandian/npu-exporter/collector/metrics"
+	"github.com/professorshandian/npu-exporter/plugins/health"
 	_ "github.com/professorshandian/npu-exporter/plugins/inputs/npu"
+	"github.com/professorshandian/npu-exporter/plugins/otlp"
 	"github.com/professorshandian/npu-exporter/plugins/prom"
+	"github.com/professorshandian/npu-exporter/plugins/remotewrite"
+	"github.com/professorshandian/npu-exporter/plugins/rest"
+	"github.com/professorshandian/npu-exporter/plugins/textfile"
 	"github.com/professorshandian/npu-exporter/utils/logger"
 	"github.com/professorshandian/npu-exporter/versions"
 )
@@ -56,14 +64,47 @@ var (
 	containerMode       = ""
 	containerd          = ""
 	endpoint            = ""
+	podResources        = ""
+	resyncInterval      = container.DefaultResyncInterval
+	cgroupFallback      = true
+	containerdNs        []string
+	criEvents           bool
 	limitIPReq          = ""
 	platform            = "Prometheus"
 	limitIPConn         int
 	limitTotalConn      int
 	cacheSize           int
-	profilingTime       int
-	hccsBWProfilingTime int
-	pollInterval        time.Duration
+	profilingTime       = defaultProfilingTime
+	hccsBWProfilingTime = defaultHccsBwProfilingTime
+	pollInterval        = defaultPollInterval
+
+	remoteWriteURL            = ""
+	remoteWriteInterval       int
+	remoteWriteQueueDir       = ""
+	remoteWriteExternalLabels map[string]string
+
+	otlpEndpoint = ""
+	otlpProtocol = ""
+	otlpInterval int
+
+	textfileDir = ""
+
+	metricsGroups map[string]bool
+
+	sampleIntervalMs int
+
+	jobLabel = metrics.DefaultJobLabelKey
+
+	idleWindow = metrics.DefaultIdleWindow
+
+	metadataLabels []string
+
+	nodeLabels   []string
+	staticLabels map[string]string
+
+	relabelRules []colcommon.RelabelRule
+
+	enableSchemaV2 bool
 )
 
 const (
@@ -78,6 +119,9 @@ const (
 	containerModeDocker     = "docker"
 	containerModeContainerd = "containerd"
 	containerModeIsula      = "isula"
+	containerModeCrio       = "crio"
+	containerModePodman     = "podman"
+	containerModeEngine     = "docker-engine"
 	unixPre                 = "unix://"
 	timeout                 = 10
 	maxHeaderBytes          = 1024
@@ -90,6 +134,8 @@ const (
 	minHccsBWProfilingTime = 1
 	maxHccsBWProfilingTime = 1000
 	defaultShutDownTimeout = 30 * time.Second
+	minSampleIntervalMs    = 10
+	maxSampleIntervalMs    = 1000
 )
 
 const (
@@ -100,6 +146,7 @@ const (
 	hccsBWProfilingTimeStr     = "hccsBWProfilingTime"
 	defaultProfilingTime       = 200
 	defaultHccsBwProfilingTime = 200
+	defaultPollInterval        = time.Second
 )
 
 type NpuConfig struct {
@@ -108,6 +155,73 @@ type NpuConfig struct {
 	NpuLogLevel   int
 	NpuMaxBackups int
 	NpuMaxAge     int
+	// NpuPlatform the data reporting platform, just support Prometheus and Telegraf, default is Prometheus
+	NpuPlatform string
+	// NpuPollInterval interval (seconds) of sending metrics when the platform is Telegraf, default is 1
+	NpuPollInterval int
+	// RemoteWriteURL push metrics to the remote write receiver when set
+	RemoteWriteURL string
+	// RemoteWriteInterval interval (seconds) of pushing metrics
+	RemoteWriteInterval int
+	// RemoteWriteQueueDir pending requests are kept in this dir during outage, in memory when empty
+	RemoteWriteQueueDir string
+	// RemoteWriteExternalLabels labels added to every pushed time series
+	RemoteWriteExternalLabels map[string]string
+	// OtlpEndpoint export metrics to the OTLP receiver when set
+	OtlpEndpoint string
+	// OtlpProtocol http or grpc, default is http
+	OtlpProtocol string
+	// OtlpInterval interval (seconds) of exporting metrics
+	OtlpInterval int
+	// TextfileDir write metrics into this textfile collector directory of node_exporter when set
+	TextfileDir string
+	// MetricsGroups turn metrics groups on or off by name, groups not listed are on
+	MetricsGroups map[string]bool
+	// SampleIntervalMs interval (milliseconds) of high-frequency sampling of utilization and power,
+	// range [10, 1000], disabled when it is 0
+	SampleIntervalMs int
+	// JobLabel the container label whose value is the job name, metrics are aggregated by it,
+	// default is volcano.sh/job-name
+	JobLabel string
+	// IdleWindow interval (seconds) in which the ai core utilization of npu allocated to container keeps zero
+	// before the npu is reported as idle, default is 1800, negative to disable the idle indicator
+	IdleWindow int
+	// MetadataLabels the pod labels and annotations added to metrics as extra labels, such as hccl/rankIndex,
+	// the label name is the key with invalid chars replaced by '_'
+	MetadataLabels []string
+	// NodeLabels the built-in node identity labels added to every metric, the options are node_name,
+	// serial_number, super_pod_id and server_id
+	NodeLabels []string
+	// StaticLabels the user-defined labels added to every metric, which must not be the label of any metric
+	StaticLabels map[string]string
+	// RelabelRules the rules renaming metrics, renaming or dropping labels, scaling units and dropping series
+	// before exposition, applied in order
+	RelabelRules []colcommon.RelabelRule
+	// EnableSchemaV2 serve the v2 metric schema with base units on /npuMetrics/v2 in parallel with v1
+	EnableSchemaV2 bool
+	// ContainerMode the container runtime, the options are docker, containerd, isula, crio, podman and
+	// docker-engine, default is docker
+	ContainerMode string
+	// ContainerEndpoint the socket of CRI server, or of podman or docker engine API in podman or docker-engine
+	// mode, the default of mode when empty
+	ContainerEndpoint string
+	// PodResourcesEndpoint the socket of kubelet pod-resources API, such as
+	// unix:///var/lib/kubelet/pod-resources/kubelet.sock, the npu of containers are got from it when set
+	PodResourcesEndpoint string
+	// ContainerResyncInterval interval (seconds) of parsing all containers when the container events of runtime
+	// are watched, only the started containers are inspected between resyncs, default is 300
+	ContainerResyncInterval int
+	// DisableCgroupFallback stop the exporter when the container runtime is unreachable, instead of finding the
+	// npu of containers by their cgroup, in which case only the container id and pod uid are available
+	DisableCgroupFallback bool
+	// ContainerdNamespaces the other containerd namespaces whose running containers are monitored besides the
+	// namespace of kubernetes or docker, such as default of nerdctl, "*" discovers all namespaces.
+	// only used in docker and containerd mode
+	ContainerdNamespaces []string
+	// WatchCriEvents watch the container events of CRI in containerd mode without containerd API, crio and isula
+	// mode, instead of relying on the resync, enable it only when the runtime broadcasts the events to every
+	// subscriber, otherwise kubelet Evented PLEG may lose them
+	WatchCriEvents bool
 }
 
 func main() {}
@@ -124,6 +238,44 @@ func NpuServer(server *http.Server, npuConfigInfo *NpuConfig) {
 	logger.HwLogConfig.LogLevel = npuConfigInfo.NpuLogLevel
 	logger.HwLogConfig.MaxBackups = npuConfigInfo.NpuMaxBackups
 	logger.HwLogConfig.MaxAge = npuConfigInfo.NpuMaxAge
+	if npuConfigInfo.NpuPlatform != "" {
+		platform = npuConfigInfo.NpuPlatform
+	}
+	if npuConfigInfo.NpuPollInterval != 0 {
+		pollInterval = time.Duration(npuConfigInfo.NpuPollInterval) * time.Second
+	}
+	remoteWriteURL = npuConfigInfo.RemoteWriteURL
+	remoteWriteInterval = npuConfigInfo.RemoteWriteInterval
+	remoteWriteQueueDir = npuConfigInfo.RemoteWriteQueueDir
+	remoteWriteExternalLabels = npuConfigInfo.RemoteWriteExternalLabels
+	otlpEndpoint = npuConfigInfo.OtlpEndpoint
+	otlpProtocol = npuConfigInfo.OtlpProtocol
+	otlpInterval = npuConfigInfo.OtlpInterval
+	textfileDir = npuConfigInfo.TextfileDir
+	metricsGroups = npuConfigInfo.MetricsGroups
+	sampleIntervalMs = npuConfigInfo.SampleIntervalMs
+	metadataLabels = npuConfigInfo.MetadataLabels
+	nodeLabels = npuConfigInfo.NodeLabels
+	staticLabels = npuConfigInfo.StaticLabels
+	relabelRules = npuConfigInfo.RelabelRules
+	enableSchemaV2 = npuConfigInfo.EnableSchemaV2
+	if npuConfigInfo.ContainerMode != "" {
+		containerMode = npuConfigInfo.ContainerMode
+	}
+	endpoint = npuConfigInfo.ContainerEndpoint
+	podResources = npuConfigInfo.PodResourcesEndpoint
+	cgroupFallback = !npuConfigInfo.DisableCgroupFallback
+	containerdNs = npuConfigInfo.ContainerdNamespaces
+	criEvents = npuConfigInfo.WatchCriEvents
+	if npuConfigInfo.ContainerResyncInterval != 0 {
+		resyncInterval = time.Duration(npuConfigInfo.ContainerResyncInterval) * time.Second
+	}
+	if npuConfigInfo.JobLabel != "" {
+		jobLabel = npuConfigInfo.JobLabel
+	}
+	if npuConfigInfo.IdleWindow != 0 {
+		idleWindow = time.Duration(npuConfigInfo.IdleWindow) * time.Second
+	}
 	err := logger.InitLogger(platform)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v", err)
@@ -140,6 +292,7 @@ func NpuServer(server *http.Server, npuConfigInfo *NpuConfig) {
 		return
 	}
 	logger.Infof("npu exporter starting and the version is %s", versions.BuildVersion)
+	colcommon.ResolveNodeLabels(dmgr)
 	deviceParser := container.MakeDevicesParser(readCntMonitoringFlags())
 	defer deviceParser.Close()
 
@@ -147,8 +300,13 @@ func NpuServer(server *http.Server, npuConfigInfo *NpuConfig) {
 		logger.Errorf("failed to init devices parser: %v", err)
 	}
 	deviceParser.Timeout = time.Duration(updateTime) * time.Second
+	deviceParser.ResyncInterval = resyncInterval
 
 	colcommon.Collector = colcommon.NewNpuCollector(cacheTime, time.Duration(updateTime)*time.Second, deviceParser, dmgr)
+	config.SetGroupStates(metricsGroups)
+	metrics.SetSamplerInterval(time.Duration(sampleIntervalMs) * time.Millisecond)
+	metrics.SetJobLabelKey(jobLabel)
+	metrics.SetIdleWindow(idleWindow)
 	config.Register(colcommon.Collector)
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -160,8 +318,8 @@ func NpuServer(server *http.Server, npuConfigInfo *NpuConfig) {
 	switch platform {
 	case prometheusPlatform:
 		prometheusProcss(wg, ctx, cancel, server)
-	// case telegrafPlatform:
-	// 	telegrafProcess()
+	case telegrafPlatform:
+		telegrafProcess(cancel)
 	default:
 		err = fmt.Errorf("err platform input")
 	}
@@ -170,16 +328,96 @@ func NpuServer(server *http.Server, npuConfigInfo *NpuConfig) {
 
 func prometheusProcss(wg *sync.WaitGroup, ctx context.Context, cancel context.CancelFunc, server *http.Server) {
 	c := prom.NewPrometheusCollector(colcommon.Collector)
+	checker := health.NewChecker(colcommon.Collector, time.Duration(updateTime)*time.Second)
 	reg := prometheus.NewRegistry()
-	reg.MustRegister(c)
+	if err := registerWithNodeLabels(reg, c, checker); err != nil {
+		logger.Errorf("register metrics failed: %v", err)
+		cancel()
+		return
+	}
+	var regV2 *prometheus.Registry
+	if enableSchemaV2 {
+		regV2 = prometheus.NewRegistry()
+		err := registerWithNodeLabels(regV2, prom.NewPrometheusCollectorWithSchema(colcommon.Collector,
+			colcommon.SchemaV2))
+		if err != nil {
+			logger.Errorf("register metrics of schema v2 failed: %v", err)
+			cancel()
+			return
+		}
+	}
+	startRemoteWrite(wg, ctx, reg)
+	startOtlpExport(wg, ctx, reg)
+	startTextfileWrite(wg, ctx, reg)
 
 	wg.Add(1)
 	go func() {
-		startServe(ctx, cancel, reg, server)
+		startServe(ctx, cancel, reg, regV2, checker, server)
 		wg.Done()
 	}()
 }
 
+// registerWithNodeLabels register collectors, the node identity labels are added to every metric without
+// rebuilding the descs
+func registerWithNodeLabels(reg *prometheus.Registry, collectors ...prometheus.Collector) error {
+	registerer := prometheus.WrapRegistererWith(colcommon.NodeLabels(), reg)
+	for _, c := range collectors {
+		if err := registerer.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startRemoteWrite(wg *sync.WaitGroup, ctx context.Context, gatherer prometheus.Gatherer) {
+	if remoteWriteURL == "" {
+		return
+	}
+	writer, err := remotewrite.NewWriter(remotewrite.Config{
+		URL:            remoteWriteURL,
+		Interval:       time.Duration(remoteWriteInterval) * time.Second,
+		QueueDir:       remoteWriteQueueDir,
+		ExternalLabels: remoteWriteExternalLabels,
+	}, gatherer)
+	if err != nil {
+		logger.Errorf("init remote write failed, push mode is disabled: %v", err)
+		return
+	}
+	logger.Info("remote write push mode is enabled")
+	writer.Start(ctx, wg)
+}
+
+func startOtlpExport(wg *sync.WaitGroup, ctx context.Context, gatherer prometheus.Gatherer) {
+	if otlpEndpoint == "" {
+		return
+	}
+	exporter, err := otlp.NewExporter(otlp.Config{
+		Endpoint: otlpEndpoint,
+		Protocol: otlpProtocol,
+		Interval: time.Duration(otlpInterval) * time.Second,
+		NodeName: os.Getenv(api.NodeNameEnv),
+	}, gatherer)
+	if err != nil {
+		logger.Errorf("init otlp exporter failed, otlp export is disabled: %v", err)
+		return
+	}
+	logger.Info("otlp export is enabled")
+	exporter.Start(ctx, wg)
+}
+
+func startTextfileWrite(wg *sync.WaitGroup, ctx context.Context, gatherer prometheus.Gatherer) {
+	if textfileDir == "" {
+		return
+	}
+	writer, err := textfile.NewWriter(textfileDir, time.Duration(updateTime)*time.Second, gatherer)
+	if err != nil {
+		logger.Errorf("init textfile writer failed, textfile output is disabled: %v", err)
+		return
+	}
+	logger.Info("textfile output is enabled")
+	writer.Start(ctx, wg)
+}
+
 func initPaprams() {
 	common.SetHccsBWProfilingTime(hccsBWProfilingTime)
 	common.SetExternalParams(profilingTime)
@@ -190,11 +428,23 @@ func paramValid(platform string) error {
 	switch platform {
 	case prometheusPlatform:
 		err = paramValidInPrometheus()
-	// case telegrafPlatform:
-	// 	err = paramValidInTelegraf()
+	case telegrafPlatform:
+		err = paramValidInTelegraf()
 	default:
 		err = fmt.Errorf("err platform input")
 	}
+	if err == nil {
+		err = paramValidForSampler()
+	}
+	if err == nil {
+		err = colcommon.SetMetadataLabels(metadataLabels)
+	}
+	if err == nil {
+		err = colcommon.SetNodeLabels(nodeLabels, staticLabels)
+	}
+	if err == nil {
+		err = colcommon.SetRelabelRules(relabelRules)
+	}
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -202,6 +452,17 @@ func paramValid(platform string) error {
 	return nil
 }
 
+func paramValidForSampler() error {
+	if sampleIntervalMs == 0 {
+		return nil
+	}
+	if sampleIntervalMs < minSampleIntervalMs || sampleIntervalMs > maxSampleIntervalMs {
+		return fmt.Errorf("sampleIntervalMs should be 0 or in range [%d, %d]", minSampleIntervalMs,
+			maxSampleIntervalMs)
+	}
+	return nil
+}
+
 func initConfig() *limiter.HandlerConfig {
 	conf := &limiter.HandlerConfig{
 		PrintLog:         true,
@@ -256,6 +517,15 @@ func readCntMonitoringFlags() container.CntNpuMonitorOpts {
 		opts.EndpointType = container.EndpointTypeIsula
 		opts.OciEndpoint = container.DefaultIsuladAddr
 		opts.CriEndpoint = container.DefaultIsuladAddr
+	case containerModeCrio:
+		opts.EndpointType = container.EndpointTypeCrio
+		opts.CriEndpoint = container.DefaultCrioAddr
+	case containerModePodman:
+		opts.EndpointType = container.EndpointTypePodman
+		opts.CriEndpoint = container.DefaultPodmanAddr
+	case containerModeEngine:
+		opts.EndpointType = container.EndpointTypeDockerEngine
+		opts.CriEndpoint = container.DefaultDockerEngineAddr
 	default:
 		hwlog.RunLog.Error("invalid container mode setting,reset to docker")
 		opts.EndpointType = container.EndpointTypeDockerd
@@ -270,6 +540,10 @@ func readCntMonitoringFlags() container.CntNpuMonitorOpts {
 		opts.CriEndpoint = endpoint
 		opts.UserBackUp = false
 	}
+	opts.PodResourcesEndpoint = podResources
+	opts.CgroupFallback = cgroupFallback
+	opts.ContainerdNamespaces = containerdNs
+	opts.CriEvents = criEvents
 	return opts
 }
 
@@ -325,6 +599,12 @@ func containerSockCheck() error {
 	if containerd != "" && !strings.Contains(containerd, ".sock") {
 		return errors.New("containerd file is not sock address")
 	}
+	if podResources != "" && !strings.Contains(podResources, ".sock") {
+		return errors.New("pod-resources file is not sock address")
+	}
+	if podResources != "" && !strings.Contains(podResources, unixPre) {
+		podResources = unixPre + podResources
+	}
 	if endpoint != "" && !strings.Contains(endpoint, unixPre) {
 		endpoint = unixPre + endpoint
 	}
@@ -399,8 +679,20 @@ func prometheusProcess() {
 
 }
 
-func startServe(ctx context.Context, cancel context.CancelFunc, reg *prometheus.Registry, server *http.Server) {
+func startServe(ctx context.Context, cancel context.CancelFunc, reg, regV2 *prometheus.Registry,
+	checker *health.Checker, server *http.Server) {
 	http.Handle("/npuMetrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError}))
+	if regV2 != nil {
+		http.Handle("/npuMetrics/v2", promhttp.HandlerFor(regV2,
+			promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError}))
+		http.Handle(rest.SchemaMappingPath, rest.SchemaMappingHandler())
+		logger.Info("v2 metric schema is served on /npuMetrics/v2")
+	}
+	http.Handle(health.LivenessPath, checker.LivenessHandler())
+	http.Handle(health.ReadinessPath, checker.ReadinessHandler())
+	deviceHandler := rest.NewDeviceHandler(colcommon.Collector)
+	http.Handle(rest.DevicesPath, deviceHandler)
+	http.Handle(rest.DevicesPath+"/", deviceHandler)
 	http.Handle("/", http.HandlerFunc(indexHandler))
 	// conf := initConfig()
 	// s, limitLs := newServerAndListener(conf)
@@ -459,59 +751,36 @@ func startServe(ctx context.Context, cancel context.CancelFunc, reg *prometheus.
 // 	}
 // }
 
-// func paramValidInTelegraf() error {
-// 	// cmdLine here must contain "-platform=Telegraf", otherwise, it will enter the Prometheus process
-// 	cmdLine := os.Args[1:]
-
-// 	// store the preset parameter names in the map
-// 	presetParamsMap := map[string]bool{
-// 		platformStr:            true,
-// 		pollIntervalStr:        true,
-// 		hccsBWProfilingTimeStr: true,
-// 	}
-
-// 	if len(cmdLine) > len(presetParamsMap) {
-// 		return errors.New("too many parameters")
-// 	}
-
-// 	var paramLen = 2
-// 	// check every input params
-// 	for _, param := range cmdLine {
-// 		param = strings.TrimPrefix(param, "-")
-// 		split := strings.Split(param, "=")
-// 		if len(split) != paramLen {
-// 			return fmt.Errorf("the param [%s] is a wrong format", param)
-// 		}
-// 		paramName := split[0]
-// 		if !presetParamsMap[paramName] {
-// 			return fmt.Errorf("not support [%s] in Telegraf", paramName)
-// 		}
-// 	}
-
-// 	if hccsBWProfilingTime < minHccsBWProfilingTime || hccsBWProfilingTime > maxHccsBWProfilingTime {
-// 		return errors.New("hccsBWProfilingTime range error")
-// 	}
-// 	return nil
-// }
-
-// func telegrafProcess() {
-// 	// create the shim. This is what will run your plugins.
-// 	shim := shim.New()
+func paramValidInTelegraf() error {
+	// the params come from NpuConfig, only the ones used by the Telegraf plugin are checked here
+	if pollInterval <= 0 {
+		return fmt.Errorf("%s must be greater than 0", pollIntervalStr)
+	}
+	if hccsBWProfilingTime < minHccsBWProfilingTime || hccsBWProfilingTime > maxHccsBWProfilingTime {
+		return errors.New("hccsBWProfilingTime range error")
+	}
+	return nil
+}
 
-// 	// If no config is specified, all imported plugins are loaded.
-// 	// otherwise follow what the config asks for.
-// 	// Check for settings from a config toml file,
-// 	// (or just use whatever plugins were imported above)
-// 	configFile := ""
-// 	err := shim.LoadConfig(&configFile)
-// 	if err != nil {
-// 		fmt.Fprintf(os.Stderr, "Err loading input: %s\n", err)
-// 		return
-// 	}
+func telegrafProcess(cancel context.CancelFunc) {
+	// stop the collect goroutines when the shim exits, so that NpuServer can return
+	defer cancel()
+	// create the shim. This is what will run your plugins.
+	s := shim.New()
+
+	// If no config is specified, all imported plugins are loaded.
+	// otherwise follow what the config asks for.
+	// Check for settings from a config toml file,
+	// (or just use whatever plugins were imported above)
+	configFile := ""
+	if err := s.LoadConfig(&configFile); err != nil {
+		logger.Errorf("load telegraf input failed: %v", err)
+		return
+	}
 
-// 	// run the input plugin(s) until stdin closes, or we receive a termination signal
-// 	if err := shim.Run(pollInterval); err != nil {
-// 		fmt.Fprintf(os.Stderr, "Err: %s\n", err)
-// 		return
-// 	}
-// }
+	// run the input plugin(s) until stdin closes, or we receive a termination signal
+	logger.Infof("run telegraf execd shim with poll interval %v", pollInterval)
+	if err := s.Run(pollInterval); err != nil {
+		logger.Errorf("telegraf execd shim stopped with error: %v", err)
+	}
+}
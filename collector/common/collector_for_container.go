@@ -17,6 +17,7 @@ package common
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -43,16 +44,20 @@ func StartContainerInfoCollect(ctx context.Context, cancelFunc context.CancelFun
 					logger.Error(err)
 				}
 				logger.Infof(UpdateCachePattern, containersDevicesCacheKey)
+				recordRuntimeSuccess()
 				retryCount = 0
 			case err := <-n.devicesParser.RecvErr():
 				logger.Errorf("received error from device parser: %v", err)
+				stopped := false
 				if strings.Contains(err.Error(), "connection refused") {
 					retryCount++
 					if retryCount == connectRefusedMaxRetry {
 						logger.Error("connection refused, task shutdown")
+						stopped = true
 						cancelFunc()
 					}
 				}
+				recordRuntimeErr(err, stopped)
 			}
 		}
 		ticker := time.NewTicker(n.updateTime)
@@ -74,6 +79,29 @@ func StartContainerInfoCollect(ctx context.Context, cancelFunc context.CancelFun
 	}()
 }
 
+// GetContainersByID get the containers using npu keyed by container id, which keeps all of the containers sharing
+// one chip, empty when the container info is not cached
+func GetContainersByID(n *NpuCollector) map[string]container.DevicesInfo {
+	if n == nil || n.cache == nil {
+		return nil
+	}
+	obj, err := n.cache.Get(containersDevicesCacheKey)
+	if err != nil {
+		return nil
+	}
+	cntNpuInfos, ok := obj.(container.DevicesInfos)
+	if !ok {
+		return nil
+	}
+	res := make(map[string]container.DevicesInfo, len(cntNpuInfos))
+	for _, v := range cntNpuInfos {
+		if v.ID != "" {
+			res[v.ID] = v
+		}
+	}
+	return res
+}
+
 // GetContainerNPUInfo get container npu info
 func GetContainerNPUInfo(n *NpuCollector) map[int32]container.DevicesInfo {
 	obj, err := n.cache.Get(containersDevicesCacheKey)
@@ -105,5 +133,59 @@ func GetContainerNPUInfo(n *NpuCollector) map[int32]container.DevicesInfo {
 			res[int32(deviceID)] = v
 		}
 	}
+	resolveVDevices(n, res, cntNpuInfos)
 	return res
 }
+
+// resolveVDevices map the vNPU created by runtime to the containers by the template of vNPU on their physical chip.
+// the vNPU already mapped is skipped, and the containers are handled in order of id when they share the template
+func resolveVDevices(n *NpuCollector, res map[int32]container.DevicesInfo, infos container.DevicesInfos) {
+	ids := make([]string, 0, len(infos))
+	for id, v := range infos {
+		if len(v.VDevices) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	sort.Strings(ids)
+	chips := getChipListCache(n)
+	for _, id := range ids {
+		info := infos[id]
+		for _, vDev := range info.VDevices {
+			if vDev.VDevID != container.UnknownID {
+				continue
+			}
+			vDevID, ok := findVDevByTemplate(chips, int32(vDev.PhyID), vDev.Template, res)
+			if !ok {
+				logger.Debugf("no vNPU of template %s found on chip %d for container %s", vDev.Template,
+					vDev.PhyID, id)
+				continue
+			}
+			res[vDevID] = info
+		}
+	}
+}
+
+func findVDevByTemplate(chips []HuaWeiAIChip, phyID int32, template string,
+	used map[int32]container.DevicesInfo) (int32, bool) {
+	for _, chip := range chips {
+		if chip.DeviceID != phyID || chip.VDevInfos == nil {
+			continue
+		}
+		candidates := make([]int32, 0, len(chip.VDevInfos.VDevInfo))
+		for _, vDev := range chip.VDevInfos.VDevInfo {
+			vDevID := int32(vDev.VDevID)
+			if _, ok := used[vDevID]; !ok && vDev.QueryInfo.Name == template {
+				candidates = append(candidates, vDevID)
+			}
+		}
+		if len(candidates) == 0 {
+			return 0, false
+		}
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] })
+		return candidates[0], true
+	}
+	return 0, false
+}
@@ -0,0 +1,62 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package common for general collector
+package common
+
+import (
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/devmanager/common"
+	"github.com/professorshandian/npu-exporter/collector/container"
+)
+
+const (
+	mockTemplate = "vir02"
+	mockVDev100  = 100
+	mockVDev101  = 101
+	mockVDev102  = 102
+)
+
+// TestGetContainerNPUInfoWithVNPU test the vNPU of containers are mapped to the containers
+func TestGetContainerNPUInfoWithVNPU(t *testing.T) {
+	convey.Convey("TestGetContainerNPUInfoWithVNPU", t, func() {
+		n := mockNewNpuCollector()
+		chip := HuaWeiAIChip{DeviceID: 0, VDevInfos: &common.VirtualDevInfo{VDevInfo: []common.CgoVDevQueryStru{
+			{VDevID: mockVDev102, QueryInfo: common.CgoVDevQueryInfo{Name: mockTemplate}},
+			{VDevID: mockVDev100, QueryInfo: common.CgoVDevQueryInfo{Name: "vir04"}},
+			{VDevID: mockVDev101, QueryInfo: common.CgoVDevQueryInfo{Name: mockTemplate}},
+		}}}
+		infos := container.DevicesInfos{
+			"c1": {ID: "c1", VDevices: []container.VDevice{
+				{PhyID: 0, VDevID: container.UnknownID, Template: mockTemplate}}},
+			"c2": {ID: "c2", VDevices: []container.VDevice{
+				{PhyID: 0, VDevID: container.UnknownID, Template: mockTemplate}}},
+			"c3": {ID: "c3", VDevices: []container.VDevice{
+				{PhyID: 0, VDevID: container.UnknownID, Template: mockTemplate}}},
+			"static": {ID: "static", Devices: []int{mockVDev100},
+				VDevices: []container.VDevice{{PhyID: container.UnknownID, VDevID: mockVDev100}}},
+		}
+		convey.So(n.cache.Set(npuListCacheKey, []HuaWeiAIChip{chip}, cacheTime), convey.ShouldBeNil)
+		convey.So(n.cache.Set(containersDevicesCacheKey, infos, cacheTime), convey.ShouldBeNil)
+
+		res := GetContainerNPUInfo(n)
+		convey.So(res, convey.ShouldHaveLength, len(chip.VDevInfos.VDevInfo))
+		convey.So(res[mockVDev100].ID, convey.ShouldEqual, "static")
+		convey.So(res[mockVDev101].ID, convey.ShouldEqual, "c1")
+		convey.So(res[mockVDev102].ID, convey.ShouldEqual, "c2")
+	})
+}
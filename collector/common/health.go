@@ -0,0 +1,78 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package common for general collector
+package common
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	// groupLastSuccess the last time of every metrics group finished a collect cycle, key is the cache key
+	groupLastSuccess sync.Map
+	runtimeStatus    = &ContainerRuntimeStatus{}
+	runtimeLock      sync.RWMutex
+)
+
+// ContainerRuntimeStatus the connection status of the container runtime
+type ContainerRuntimeStatus struct {
+	// LastSuccess the last time container info was parsed successfully
+	LastSuccess time.Time
+	// LastError the last error received from the devices parser
+	LastError string
+	// Stopped the container info collect is cancelled because of connection refused
+	Stopped bool
+}
+
+// GetGroupLastSuccess get the last success time of every metrics group
+func GetGroupLastSuccess() map[string]time.Time {
+	res := make(map[string]time.Time)
+	groupLastSuccess.Range(func(key, value interface{}) bool {
+		name, okKey := key.(string)
+		t, okValue := value.(time.Time)
+		if okKey && okValue {
+			res[name] = t
+		}
+		return true
+	})
+	return res
+}
+
+// GetContainerRuntimeStatus get the status of the container runtime
+func GetContainerRuntimeStatus() ContainerRuntimeStatus {
+	runtimeLock.RLock()
+	defer runtimeLock.RUnlock()
+	return *runtimeStatus
+}
+
+// RecordGroupSuccess record that the metrics group of cacheKey finished a collect cycle with data cached
+func RecordGroupSuccess(cacheKey string) {
+	groupLastSuccess.Store(cacheKey, time.Now())
+}
+
+func recordRuntimeSuccess() {
+	runtimeLock.Lock()
+	defer runtimeLock.Unlock()
+	runtimeStatus.LastSuccess = time.Now()
+	runtimeStatus.LastError = ""
+}
+
+func recordRuntimeErr(err error, stopped bool) {
+	runtimeLock.Lock()
+	defer runtimeLock.Unlock()
+	runtimeStatus.LastError = err.Error()
+	runtimeStatus.Stopped = runtimeStatus.Stopped || stopped
+}
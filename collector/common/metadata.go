@@ -0,0 +1,158 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package common for general collector
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/professorshandian/npu-exporter/collector/container"
+)
+
+var (
+	// invalidLabelCharReg the chars not allowed in label name, such as '/', '.' and '-' in kubernetes keys
+	invalidLabelCharReg = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+	// reservedLabels the label names used by exporter, telegraf tags included
+	reservedLabels = map[string]bool{npuID: true, modelName: true, npuUUID: true, npuPCIEInfo: true,
+		namespace: true, podName: true, cntrName: true, "device": true, "vdev_id": true, "npu_uuid": true}
+
+	// metadataLabels the allow-list of pod labels and annotations added to metrics as extra labels
+	metadataLabels []MetadataLabel
+)
+
+// MetadataLabel the pod label or annotation added to metrics as extra label
+type MetadataLabel struct {
+	// Key the key of label or annotation, such as hccl/rankIndex
+	Key string
+	// Name the name of metric label, the key with invalid chars replaced by '_', such as hccl_rankIndex
+	Name string
+}
+
+// SetMetadataLabels set the allow-list of pod labels and annotations added to metrics, empty to disable.
+// must be called before collecting
+func SetMetadataLabels(keys []string) error {
+	res := make([]MetadataLabel, 0, len(keys))
+	names := make(map[string]string, len(keys))
+	for _, key := range keys {
+		name := metadataLabelName(key)
+		if name == "" {
+			return fmt.Errorf("metadata key [%s] is invalid", key)
+		}
+		if reservedLabels[name] {
+			return fmt.Errorf("label [%s] of metadata key [%s] is used by exporter", name, key)
+		}
+		if other, ok := names[name]; ok {
+			return fmt.Errorf("metadata keys [%s] and [%s] have the same label [%s]", other, key, name)
+		}
+		names[name] = key
+		res = append(res, MetadataLabel{Key: key, Name: name})
+	}
+	metadataLabels = res
+	return nil
+}
+
+// MetadataLabels the allow-list of pod labels and annotations added to metrics
+func MetadataLabels() []MetadataLabel {
+	return metadataLabels
+}
+
+func metadataLabelName(key string) string {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return ""
+	}
+	name := invalidLabelCharReg.ReplaceAllString(key, "_")
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// MetadataValues the values of allow-listed labels and annotations of container, keyed by label name.
+// the label wins when the key is both a label and an annotation, keys not found are omitted
+func MetadataValues(devInfo container.DevicesInfo) map[string]string {
+	res := make(map[string]string, len(metadataLabels))
+	for _, label := range metadataLabels {
+		if value, ok := devInfo.Labels[label.Key]; ok {
+			res[label.Name] = value
+			continue
+		}
+		if value, ok := devInfo.Annotations[label.Key]; ok {
+			res[label.Name] = value
+		}
+	}
+	return res
+}
+
+// EnrichSamples append the allow-listed labels and annotations of pod to the samples carrying container labels.
+// every sample of the same metric gets the same extra labels, the value is empty when it is not found.
+// the samples of pod only get the values shared by all its containers, such as the ones of pod sandbox
+func EnrichSamples(samples []MetricSample, containerMap map[int32]container.DevicesInfo) {
+	if len(metadataLabels) == 0 {
+		return
+	}
+	byContainer := make(map[string]map[string]string, len(containerMap))
+	byPod := make(map[string]map[string]string, len(containerMap))
+	for _, devInfo := range containerMap {
+		names := strings.Split(devInfo.Name, "_")
+		if len(names) != ContainerNameLen {
+			continue
+		}
+		values := MetadataValues(devInfo)
+		byContainer[devInfo.Name] = values
+		podKey := names[NameSpaceIdx] + "_" + names[PodNameIdx]
+		podValues, ok := byPod[podKey]
+		if !ok {
+			podValues = make(map[string]string, len(values))
+			for name, value := range values {
+				podValues[name] = value
+			}
+			byPod[podKey] = podValues
+			continue
+		}
+		for name, value := range podValues {
+			if values[name] != value {
+				delete(podValues, name)
+			}
+		}
+	}
+	for i := range samples {
+		enrichSample(&samples[i], byContainer, byPod)
+	}
+}
+
+func enrichSample(sample *MetricSample, byContainer, byPod map[string]map[string]string) {
+	if labelIndex(sample.LabelNames, podName) < 0 {
+		return
+	}
+	podKey := sample.Label(namespace) + "_" + sample.Label(podName)
+	values, ok := byPod[podKey]
+	if labelIndex(sample.LabelNames, cntrName) >= 0 {
+		values, ok = byContainer[podKey+"_"+sample.Label(cntrName)]
+	}
+	if !ok {
+		values = nil
+	}
+	labelNames := make([]string, len(sample.LabelNames), len(sample.LabelNames)+len(metadataLabels))
+	copy(labelNames, sample.LabelNames)
+	for _, label := range metadataLabels {
+		labelNames = append(labelNames, label.Name)
+		sample.LabelValues = append(sample.LabelValues, values[label.Name])
+	}
+	sample.LabelNames = labelNames
+}
@@ -0,0 +1,113 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package common for general collector
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartystreets/goconvey/convey"
+
+	"github.com/professorshandian/npu-exporter/collector/container"
+)
+
+const (
+	rankIndexAnno = "hccl/rankIndex"
+	taskLabel     = "ring-controller.atlas"
+	mockRankIndex = "3"
+	mockTaskValue = "ascend-910b"
+)
+
+var (
+	metadataTestDesc    = BuildDescWithOpts("test_metadata_gauge", "the test gauge", CardLabel)
+	metadataTestPodDesc = BuildDescWithOpts("test_metadata_pod_gauge", "the test gauge of pod",
+		[]string{namespace, podName})
+)
+
+// TestSetMetadataLabels test the validation of the allow-list
+func TestSetMetadataLabels(t *testing.T) {
+	convey.Convey("TestSetMetadataLabels", t, func() {
+		defer SetMetadataLabels(nil)
+		convey.Convey("the label name is the key with invalid chars replaced", func() {
+			convey.So(SetMetadataLabels([]string{rankIndexAnno, taskLabel, "1key"}), convey.ShouldBeNil)
+			convey.So(MetadataLabels(), convey.ShouldResemble, []MetadataLabel{
+				{Key: rankIndexAnno, Name: "hccl_rankIndex"},
+				{Key: taskLabel, Name: "ring_controller_atlas"},
+				{Key: "1key", Name: "_1key"},
+			})
+		})
+		convey.Convey("failed when the key is empty", func() {
+			convey.So(SetMetadataLabels([]string{" "}), convey.ShouldNotBeNil)
+		})
+		convey.Convey("failed when the label is used by exporter", func() {
+			convey.So(SetMetadataLabels([]string{"pod.name"}), convey.ShouldNotBeNil)
+			convey.So(SetMetadataLabels([]string{"device"}), convey.ShouldNotBeNil)
+		})
+		convey.Convey("failed when two keys have the same label", func() {
+			convey.So(SetMetadataLabels([]string{"a/b", "a.b"}), convey.ShouldNotBeNil)
+		})
+	})
+}
+
+// TestEnrichSamples test the labels and annotations of pod appended to samples
+func TestEnrichSamples(t *testing.T) {
+	convey.Convey("TestEnrichSamples", t, func() {
+		convey.So(SetMetadataLabels([]string{rankIndexAnno, taskLabel}), convey.ShouldBeNil)
+		defer SetMetadataLabels(nil)
+		containerMap := map[int32]container.DevicesInfo{
+			0: {Name: "ns_pod_ctr", Labels: map[string]string{taskLabel: mockTaskValue},
+				Annotations: map[string]string{rankIndexAnno: mockRankIndex}},
+		}
+		used, err := NewSample(metadataTestDesc, 1, time.Time{}, []string{"0", "", "", "", "ns", "pod", "ctr"})
+		convey.So(err, convey.ShouldBeNil)
+		free, err := NewSample(metadataTestDesc, 1, time.Time{}, []string{"1", "", "", "", "", "", ""})
+		convey.So(err, convey.ShouldBeNil)
+		samples := []MetricSample{used, free}
+		EnrichSamples(samples, containerMap)
+
+		convey.So(samples[0].Label("hccl_rankIndex"), convey.ShouldEqual, mockRankIndex)
+		convey.So(samples[0].Label("ring_controller_atlas"), convey.ShouldEqual, mockTaskValue)
+		convey.So(samples[1].LabelNames, convey.ShouldResemble, samples[0].LabelNames)
+		convey.So(samples[1].Label("hccl_rankIndex"), convey.ShouldBeEmpty)
+		convey.So(len(CardLabel), convey.ShouldEqual, len(samples[0].LabelNames)-len(MetadataLabels()))
+
+		_, err = ToPrometheusMetric(samples[0])
+		convey.So(err, convey.ShouldBeNil)
+	})
+}
+
+// TestEnrichPodSamples test the samples of pod only get the values shared by all its containers
+func TestEnrichPodSamples(t *testing.T) {
+	convey.Convey("TestEnrichPodSamples", t, func() {
+		convey.So(SetMetadataLabels([]string{rankIndexAnno, taskLabel}), convey.ShouldBeNil)
+		defer SetMetadataLabels(nil)
+		containerMap := map[int32]container.DevicesInfo{
+			0: {Name: "ns_pod_ctr1", Labels: map[string]string{taskLabel: mockTaskValue},
+				Annotations: map[string]string{rankIndexAnno: mockRankIndex}},
+			1: {Name: "ns_pod_ctr2", Labels: map[string]string{taskLabel: mockTaskValue},
+				Annotations: map[string]string{rankIndexAnno: "0"}},
+			2: {Name: "ns_pod_ctr3", Labels: map[string]string{taskLabel: mockTaskValue}},
+		}
+		for i := 0; i < len(containerMap); i++ {
+			pod, err := NewSample(metadataTestPodDesc, 1, time.Time{}, []string{"ns", "pod"})
+			convey.So(err, convey.ShouldBeNil)
+			samples := []MetricSample{pod}
+			EnrichSamples(samples, containerMap)
+			convey.So(samples[0].Label("ring_controller_atlas"), convey.ShouldEqual, mockTaskValue)
+			convey.So(samples[0].Label("hccl_rankIndex"), convey.ShouldBeEmpty)
+		}
+	})
+}
@@ -16,6 +16,7 @@
 package common
 
 import (
+	"context"
 	"reflect"
 	"strings"
 	"sync"
@@ -45,12 +46,12 @@ func BuildDescSlice(slice *[]*prometheus.Desc, name string, help string) {
 
 // BuildDesc build desc
 func BuildDesc(name string, help string) *prometheus.Desc {
-	return prometheus.NewDesc(name, help, CardLabel, nil)
+	return BuildDescWithOpts(name, help, CardLabel)
 }
 
 // BuildDescWithLabel build desc with label
 func BuildDescWithLabel(name string, help string, label []string) *prometheus.Desc {
-	return prometheus.NewDesc(name, help, label, nil)
+	return BuildDescWithOpts(name, help, label)
 }
 
 // MetricsCollector metrics collector
@@ -61,14 +62,10 @@ type MetricsCollector interface {
 	// CollectToCache collect data to cache
 	CollectToCache(n *NpuCollector, chipList []HuaWeiAIChip)
 
-	// UpdatePrometheus update prometheus
-	UpdatePrometheus(ch chan<- prometheus.Metric, n *NpuCollector, containerMap map[int32]container.DevicesInfo,
+	// UpdateSamples emit samples from cache, every output backend renders from them
+	UpdateSamples(sink SampleSink, n *NpuCollector, containerMap map[int32]container.DevicesInfo,
 		chips []HuaWeiAIChip)
 
-	// UpdateTelegraf update telegraf
-	UpdateTelegraf(fieldsMap map[string]map[string]interface{}, n *NpuCollector,
-		containerMap map[int32]container.DevicesInfo, chips []HuaWeiAIChip) map[string]map[string]interface{}
-
 	// PreCollect pre handle before collect
 	PreCollect(*NpuCollector, []HuaWeiAIChip)
 
@@ -79,6 +76,12 @@ type MetricsCollector interface {
 	IsSupported(*NpuCollector) bool
 }
 
+// BackgroundCollector collector which keeps sampling in its own goroutine besides the periodic collection,
+// the goroutine is started with the collection and stopped with ctx
+type BackgroundCollector interface {
+	StartBackground(group *sync.WaitGroup, ctx context.Context, n *NpuCollector)
+}
+
 // MetricsCollectorAdapter base collector for metrics collector
 type MetricsCollectorAdapter struct {
 	LocalCache   sync.Map
@@ -95,17 +98,11 @@ func (c *MetricsCollectorAdapter) Describe(ch chan<- *prometheus.Desc) {
 func (c *MetricsCollectorAdapter) CollectToCache(n *NpuCollector, chipList []HuaWeiAIChip) {
 }
 
-// UpdatePrometheus update prometheus
-func (c *MetricsCollectorAdapter) UpdatePrometheus(ch chan<- prometheus.Metric, n *NpuCollector,
+// UpdateSamples emit samples from cache
+func (c *MetricsCollectorAdapter) UpdateSamples(sink SampleSink, n *NpuCollector,
 	containerMap map[int32]container.DevicesInfo, chips []HuaWeiAIChip) {
 }
 
-// UpdateTelegraf update telegraf
-func (c *MetricsCollectorAdapter) UpdateTelegraf(fieldsMap map[string]map[string]interface{}, n *NpuCollector,
-	containerMap map[int32]container.DevicesInfo, chips []HuaWeiAIChip) map[string]map[string]interface{} {
-	return fieldsMap
-}
-
 // PreCollect pre handle before collect
 func (c *MetricsCollectorAdapter) PreCollect(n *NpuCollector, chipList []HuaWeiAIChip) {
 	if strings.Contains(n.Dmgr.GetDevType(), common.Ascend910) {
@@ -122,7 +119,7 @@ func (c *MetricsCollectorAdapter) IsSupported(*NpuCollector) bool {
 	return true
 }
 
-// UpdateCache update cache
+// UpdateCache update cache, the group is recorded as succeeded only when its local cache is merged into the cache
 func UpdateCache[T any](n *NpuCollector, cacheKey string, localCache *sync.Map) {
 	var cacheInfo = make(map[int32]T)
 	obj, err := n.cache.Get(cacheKey)
@@ -136,16 +133,21 @@ func UpdateCache[T any](n *NpuCollector, cacheKey string, localCache *sync.Map)
 		}
 	}
 
+	cached := 0
 	localCache.Range(func(key, value interface{}) bool {
 		finalKey, okKey := key.(int32)
 		finalValue, okValue := value.(T)
 		if okKey && okValue {
 			cacheInfo[finalKey] = finalValue
+			cached++
 		}
 		return true
 	})
 
 	err = n.cache.Set(cacheKey, cacheInfo, n.cacheTime)
+	if err == nil && cached > 0 {
+		RecordGroupSuccess(cacheKey)
+	}
 	if noNeedToPrintUpdateLog[cacheKey] {
 		return
 	}
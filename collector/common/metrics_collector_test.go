@@ -169,6 +169,25 @@ func TestUpdateCache(t *testing.T) {
 	})
 }
 
+// TestUpdateCacheRecordGroupSuccess test the group success is recorded only when data is cached
+func TestUpdateCacheRecordGroupSuccess(t *testing.T) {
+	n := mockNewNpuCollector()
+	convey.Convey("TestUpdateCacheRecordGroupSuccess", t, func() {
+		convey.Convey("nothing cached, the group is not recorded", func() {
+			UpdateCache[string](n, "mockEmptyGroup", &sync.Map{})
+			_, ok := GetGroupLastSuccess()["mockEmptyGroup"]
+			convey.So(ok, convey.ShouldBeFalse)
+		})
+		convey.Convey("data cached, the group is recorded", func() {
+			localCache := sync.Map{}
+			localCache.Store(int32(0), "mockValue")
+			UpdateCache[string](n, "mockCachedGroup", &localCache)
+			_, ok := GetGroupLastSuccess()["mockCachedGroup"]
+			convey.So(ok, convey.ShouldBeTrue)
+		})
+	})
+}
+
 func TestGetInfoFromCache(t *testing.T) {
 	const key = int32(0)
 	tests := []struct {
@@ -0,0 +1,156 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package common for general collector
+package common
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/api"
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/utils"
+	"github.com/professorshandian/npu-exporter/ascend-common/devmanager"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+// the built-in node identity labels
+const (
+	// NodeNameLabel the node name from env NODE_NAME
+	NodeNameLabel = "node_name"
+	// SerialNumberLabel the serial number of node from DMI
+	SerialNumberLabel = "serial_number"
+	// SuperPodIDLabel the super pod id of node, only for the device supporting super pod
+	SuperPodIDLabel = "super_pod_id"
+	// ServerIDLabel the server id of node in super pod, only for the device supporting super pod
+	ServerIDLabel = "server_id"
+
+	maxSerialLength = 128
+)
+
+var (
+	// dmiSerialPath the serial number of DMI, /sys/class/dmi/id is not used since it is a symlink, which is
+	// rejected by utils.ReadLimitBytes
+	dmiSerialPath = "/sys/devices/virtual/dmi/id/product_serial"
+
+	builtinNodeLabels = map[string]bool{NodeNameLabel: true, SerialNumberLabel: true, SuperPodIDLabel: true,
+		ServerIDLabel: true}
+
+	// nodeLabelNames the built-in node labels enabled by config
+	nodeLabelNames []string
+	// nodeLabels the constant labels added to every metric, resolved when exporter starts
+	nodeLabels = map[string]string{}
+)
+
+// SetNodeLabels set the built-in node labels and user-defined static labels added to every metric,
+// the values of built-in labels are resolved by ResolveNodeLabels
+func SetNodeLabels(builtins []string, static map[string]string) error {
+	names := make(map[string]bool, len(builtins)+len(static))
+	// the constant label must not be the variable label of any metric
+	inUse := labelNamesInUse()
+	for _, name := range builtins {
+		if !builtinNodeLabels[name] {
+			return fmt.Errorf("node label [%s] is not supported", name)
+		}
+		if names[name] {
+			return fmt.Errorf("node label [%s] is duplicated", name)
+		}
+		if inUse[name] {
+			return fmt.Errorf("node label [%s] is used by metrics", name)
+		}
+		names[name] = true
+	}
+	labels := make(map[string]string, len(static))
+	for name, value := range static {
+		if !model.LabelName(name).IsValid() || strings.HasPrefix(name, model.ReservedLabelPrefix) {
+			return fmt.Errorf("static label [%s] is invalid", name)
+		}
+		if names[name] || reservedLabels[name] || inUse[name] {
+			return fmt.Errorf("static label [%s] is used by exporter", name)
+		}
+		labels[name] = value
+	}
+	for _, label := range metadataLabels {
+		if _, ok := labels[label.Name]; ok || names[label.Name] {
+			return fmt.Errorf("node label [%s] is used by metadata key [%s]", label.Name, label.Key)
+		}
+	}
+	nodeLabelNames = builtins
+	nodeLabels = labels
+	return nil
+}
+
+// ResolveNodeLabels resolve the values of the built-in node labels, the label is omitted when its value is not got
+func ResolveNodeLabels(dmgr devmanager.DeviceInterface) {
+	for _, name := range nodeLabelNames {
+		var value string
+		var err error
+		switch name {
+		case NodeNameLabel:
+			value = os.Getenv(api.NodeNameEnv)
+		case SerialNumberLabel:
+			value, err = getSerialNumber()
+		case SuperPodIDLabel, ServerIDLabel:
+			value, err = getSuperPodLabel(dmgr, name)
+		default:
+		}
+		if err != nil || value == "" {
+			logger.Warnf("node label %s is not added to metrics, value is empty or error: %v", name, err)
+			continue
+		}
+		nodeLabels[name] = value
+	}
+}
+
+// NodeLabels the constant labels added to every metric
+func NodeLabels() map[string]string {
+	res := make(map[string]string, len(nodeLabels))
+	for name, value := range nodeLabels {
+		res[name] = value
+	}
+	return res
+}
+
+func getSerialNumber() (string, error) {
+	data, err := utils.ReadLimitBytes(dmiSerialPath, maxSerialLength)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func getSuperPodLabel(dmgr devmanager.DeviceInterface, name string) (string, error) {
+	if dmgr == nil {
+		return "", fmt.Errorf("device manager is nil")
+	}
+	_, logicIDs, err := dmgr.GetDeviceList()
+	if err != nil {
+		return "", err
+	}
+	if len(logicIDs) == 0 {
+		return "", fmt.Errorf("no npu found")
+	}
+	info, err := dmgr.GetSuperPodInfo(logicIDs[0])
+	if err != nil {
+		return "", err
+	}
+	if name == SuperPodIDLabel {
+		return strconv.FormatUint(uint64(info.SuperPodId), Base), nil
+	}
+	return strconv.FormatUint(uint64(info.ServerId), Base), nil
+}
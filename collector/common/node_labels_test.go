@@ -0,0 +1,90 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package common for general collector
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/api"
+	"github.com/professorshandian/npu-exporter/ascend-common/devmanager"
+	"github.com/professorshandian/npu-exporter/ascend-common/devmanager/common"
+)
+
+const (
+	mockNodeName   = "node1"
+	mockSuperPodID = 2
+	mockServerID   = 5
+	mockSerial     = "2102312XYZ"
+	mockSerialMode = 0400
+)
+
+// TestSetNodeLabels test the validation of node labels
+func TestSetNodeLabels(t *testing.T) {
+	convey.Convey("TestSetNodeLabels", t, func() {
+		defer SetNodeLabels(nil, nil)
+		convey.So(SetNodeLabels([]string{NodeNameLabel, ServerIDLabel}, map[string]string{"zone": "a"}),
+			convey.ShouldBeNil)
+		convey.So(NodeLabels(), convey.ShouldResemble, map[string]string{"zone": "a"})
+
+		convey.So(SetNodeLabels([]string{"unknown"}, nil), convey.ShouldNotBeNil)
+		convey.So(SetNodeLabels([]string{NodeNameLabel, NodeNameLabel}, nil), convey.ShouldNotBeNil)
+		convey.So(SetNodeLabels(nil, map[string]string{"invalid-name": "a"}), convey.ShouldNotBeNil)
+		convey.So(SetNodeLabels(nil, map[string]string{"__reserved": "a"}), convey.ShouldNotBeNil)
+		convey.So(SetNodeLabels(nil, map[string]string{podName: "a"}), convey.ShouldNotBeNil)
+		// the labels of metrics and of their v2 names are used
+		BuildDescWithLabel("test_node_labels_metric", "help", []string{"stat"})
+		RegisterLabelNames("check")
+		convey.So(SetNodeLabels(nil, map[string]string{"stat": "a"}), convey.ShouldNotBeNil)
+		convey.So(SetNodeLabels(nil, map[string]string{"check": "a"}), convey.ShouldNotBeNil)
+		convey.So(SetNodeLabels(nil, map[string]string{"link": "a"}), convey.ShouldNotBeNil)
+		convey.So(SetNodeLabels([]string{NodeNameLabel}, map[string]string{NodeNameLabel: "a"}),
+			convey.ShouldNotBeNil)
+	})
+}
+
+// TestResolveNodeLabels test resolving the values of built-in node labels
+func TestResolveNodeLabels(t *testing.T) {
+	convey.Convey("TestResolveNodeLabels", t, func() {
+		defer SetNodeLabels(nil, nil)
+		t.Setenv(api.NodeNameEnv, mockNodeName)
+		dmgr := &devmanager.DeviceManager{}
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+		patches.ApplyMethodReturn(dmgr, "GetDeviceList", int32(1), []int32{0}, nil)
+		patches.ApplyMethodReturn(dmgr, "GetSuperPodInfo",
+			common.CgoSuperPodInfo{SuperPodId: mockSuperPodID, ServerId: mockServerID}, nil)
+		originPath := dmiSerialPath
+		defer func() { dmiSerialPath = originPath }()
+		dmiSerialPath = filepath.Join(t.TempDir(), "product_serial")
+		convey.So(os.WriteFile(dmiSerialPath, []byte(mockSerial+"\n"), mockSerialMode), convey.ShouldBeNil)
+
+		convey.So(SetNodeLabels([]string{NodeNameLabel, SerialNumberLabel, SuperPodIDLabel, ServerIDLabel},
+			map[string]string{"zone": "a"}), convey.ShouldBeNil)
+		ResolveNodeLabels(dmgr)
+		convey.So(NodeLabels(), convey.ShouldResemble, map[string]string{
+			"zone":            "a",
+			NodeNameLabel:     mockNodeName,
+			SuperPodIDLabel:   "2",
+			ServerIDLabel:     "5",
+			SerialNumberLabel: mockSerial,
+		})
+	})
+}
@@ -70,6 +70,17 @@ func StartCollect(group *sync.WaitGroup, ctx context.Context, n *NpuCollector) {
 	npuChipInfoInitAtFirstTime(n)
 	startCollectSingleGoroutine(group, ctx, n, "dcmi")
 	startCollectForMultiGoroutine(group, ctx, n)
+	startBackgroundCollect(group, ctx, n)
+}
+
+func startBackgroundCollect(group *sync.WaitGroup, ctx context.Context, n *NpuCollector) {
+	for _, chain := range [][]MetricsCollector{ChainForSingleGoroutine, ChainForMultiGoroutine} {
+		for _, c := range chain {
+			if bc, ok := c.(BackgroundCollector); ok {
+				bc.StartBackground(group, ctx, n)
+			}
+		}
+	}
 }
 
 func startCollectForMultiGoroutine(group *sync.WaitGroup, ctx context.Context, n *NpuCollector) {
@@ -312,6 +323,17 @@ func GetChipListWithVNPU(n *NpuCollector) []HuaWeiAIChip {
 	return result
 
 }
+
+// GetUpdateTime get the interval of collecting metrics to cache
+func GetUpdateTime(n *NpuCollector) time.Duration {
+	return n.updateTime
+}
+
+// GetChipList get chip list from cache, vnpu is not expanded
+func GetChipList(n *NpuCollector) []HuaWeiAIChip {
+	return getChipListCache(n)
+}
+
 func getChipListCache(n *NpuCollector) []HuaWeiAIChip {
 	obj, err := n.cache.Get(npuListCacheKey)
 	if err != nil {
@@ -0,0 +1,154 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package common for general collector
+package common
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// RateSuffix suffix of the name of per-second rate derived from counter
+	RateSuffix = "_rate"
+	// rateStaleTime the previous sample of counter not updated in this time is dropped
+	rateStaleTime = 10 * time.Minute
+	rateKeySep    = "\x00"
+	rateUnit      = "1/s"
+)
+
+var (
+	defaultRateTracker = NewRateTracker()
+
+	rateDescLock sync.Mutex
+	rateDescs    []*prometheus.Desc
+)
+
+// BuildCounterDesc build desc of counter with card label, the desc of its per-second rate is built too
+func BuildCounterDesc(name string, help string) *prometheus.Desc {
+	return BuildDescWithOpts(name, help, CardLabel, WithType(CounterType))
+}
+
+// BuildCounterDescSlice build desc of counter and append it to slice
+func BuildCounterDescSlice(slice *[]*prometheus.Desc, name string, help string) {
+	*slice = append(*slice, BuildCounterDesc(name, help))
+}
+
+// RateDescs the descs of per-second rates derived from all counters
+func RateDescs() []*prometheus.Desc {
+	rateDescLock.Lock()
+	defer rateDescLock.Unlock()
+	res := make([]*prometheus.Desc, len(rateDescs))
+	copy(res, rateDescs)
+	return res
+}
+
+func buildRateDesc(counter *MetricMeta) *prometheus.Desc {
+	unit := rateUnit
+	if counter.Unit != "" {
+		unit = counter.Unit + "/s"
+	}
+	opts := []MetaOption{WithUnit(unit), WithSeriesLabels(counter.SeriesLabels...)}
+	desc := BuildDescWithOpts(counter.Name+RateSuffix, "the per-second rate of "+counter.Help, counter.LabelNames,
+		opts...)
+	rateDescLock.Lock()
+	rateDescs = append(rateDescs, desc)
+	rateDescLock.Unlock()
+	return desc
+}
+
+type counterPoint struct {
+	value     float64
+	timestamp time.Time
+	rate      float64
+	hasRate   bool
+}
+
+// RateTracker track the previous sample of every counter on every chip to derive the per-second rate
+type RateTracker struct {
+	lock      sync.Mutex
+	points    map[string]*counterPoint
+	lastPrune time.Time
+}
+
+// NewRateTracker create rate tracker
+func NewRateTracker() *RateTracker {
+	return &RateTracker{points: make(map[string]*counterPoint)}
+}
+
+// Rate get the per-second rate of counter sample, false when there is no previous sample.
+// samples of the same collection get the same rate, so that the rate is stable between scrapes.
+// a decreased value means the counter is reset (such as chip reset), the rate is counted from zero
+func (r *RateTracker) Rate(sample MetricSample) (float64, bool) {
+	// zero timestamp can not tell the collection, negative value means collect failed
+	if sample.Timestamp.IsZero() || sample.Value < 0 {
+		return 0, false
+	}
+	key := sample.Name + rateKeySep + sample.DevKey + rateKeySep + strings.Join(sample.LabelValues, rateKeySep)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.prune(sample.Timestamp)
+	point, ok := r.points[key]
+	if !ok {
+		r.points[key] = &counterPoint{value: sample.Value, timestamp: sample.Timestamp}
+		return 0, false
+	}
+	if !sample.Timestamp.After(point.timestamp) {
+		return point.rate, point.hasRate
+	}
+	delta := sample.Value - point.value
+	if delta < 0 {
+		delta = sample.Value
+	}
+	point.rate = delta / sample.Timestamp.Sub(point.timestamp).Seconds()
+	point.hasRate = true
+	point.value = sample.Value
+	point.timestamp = sample.Timestamp
+	return point.rate, true
+}
+
+// RateSample build the sample of per-second rate derived from counter sample
+func (r *RateTracker) RateSample(sample MetricSample) (MetricSample, bool) {
+	meta, ok := GetMetricMetaByName(sample.Name)
+	if !ok || meta.rateDesc == nil {
+		return MetricSample{}, false
+	}
+	rate, ok := r.Rate(sample)
+	if !ok {
+		return MetricSample{}, false
+	}
+	rateSample, err := NewSample(meta.rateDesc, rate, sample.Timestamp, sample.LabelValues)
+	if err != nil {
+		return MetricSample{}, false
+	}
+	rateSample.DevKey = sample.DevKey
+	return rateSample, true
+}
+
+func (r *RateTracker) prune(now time.Time) {
+	if now.Sub(r.lastPrune) < rateStaleTime {
+		return
+	}
+	for key, point := range r.points {
+		if now.Sub(point.timestamp) > rateStaleTime {
+			delete(r.points, key)
+		}
+	}
+	r.lastPrune = now
+}
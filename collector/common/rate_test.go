@@ -0,0 +1,105 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package common for general collector
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartystreets/goconvey/convey"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/devmanager/common"
+)
+
+const (
+	rateInitValue  = 100
+	rateNextValue  = 300
+	rateResetValue = 50
+	rateInterval   = 10 * time.Second
+)
+
+var testCounterDesc = BuildCounterDesc("test_rate_counter", "the test counter")
+
+func buildCounterSample(t *testing.T, value float64, timestamp time.Time) MetricSample {
+	sample, err := NewSample(testCounterDesc, value, timestamp, make([]string, len(CardLabel)))
+	if err != nil {
+		t.Fatalf("build sample failed: %v", err)
+	}
+	sample.DevKey = ChipDevKey(0)
+	return sample
+}
+
+// TestRateTracker test deriving the per-second rate of counter
+func TestRateTracker(t *testing.T) {
+	convey.Convey("TestRateTracker", t, func() {
+		tracker := NewRateTracker()
+		start := time.Now()
+		_, ok := tracker.Rate(buildCounterSample(t, rateInitValue, start))
+		convey.So(ok, convey.ShouldBeFalse)
+
+		convey.Convey("rate of increased counter", func() {
+			rate, ok := tracker.Rate(buildCounterSample(t, rateNextValue, start.Add(rateInterval)))
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(rate, convey.ShouldEqual, (rateNextValue-rateInitValue)/rateInterval.Seconds())
+
+			convey.Convey("samples of the same collection get the same rate", func() {
+				again, ok := tracker.Rate(buildCounterSample(t, rateNextValue, start.Add(rateInterval)))
+				convey.So(ok, convey.ShouldBeTrue)
+				convey.So(again, convey.ShouldEqual, rate)
+			})
+		})
+		convey.Convey("rate is counted from zero when counter is reset", func() {
+			rate, ok := tracker.Rate(buildCounterSample(t, rateResetValue, start.Add(rateInterval)))
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(rate, convey.ShouldEqual, rateResetValue/rateInterval.Seconds())
+		})
+		convey.Convey("no rate when collect failed or timestamp is zero", func() {
+			_, ok := tracker.Rate(buildCounterSample(t, common.FailedMetricValue, start.Add(rateInterval)))
+			convey.So(ok, convey.ShouldBeFalse)
+			_, ok = tracker.Rate(buildCounterSample(t, rateNextValue, time.Time{}))
+			convey.So(ok, convey.ShouldBeFalse)
+		})
+		convey.Convey("stale counter is dropped", func() {
+			later := start.Add(rateStaleTime + rateInterval)
+			_, ok := tracker.Rate(buildCounterSample(t, rateNextValue, later))
+			convey.So(ok, convey.ShouldBeFalse)
+		})
+	})
+}
+
+// TestSampleSinkWithRate test the rate sample added following counter sample
+func TestSampleSinkWithRate(t *testing.T) {
+	convey.Convey("TestSampleSinkWithRate", t, func() {
+		sink := NewSampleSink()
+		sink.rates = NewRateTracker()
+		start := time.Now()
+		sink.Add(buildCounterSample(t, rateInitValue, start))
+		sink.Add(buildCounterSample(t, rateNextValue, start.Add(rateInterval)))
+
+		samples := sink.Samples()
+		convey.So(len(samples), convey.ShouldEqual, 3)
+		rateSample := samples[2]
+		convey.So(rateSample.Name, convey.ShouldEqual, "test_rate_counter"+RateSuffix)
+		convey.So(rateSample.Type, convey.ShouldEqual, GaugeType)
+		convey.So(rateSample.Unit, convey.ShouldEqual, rateUnit)
+		convey.So(rateSample.DevKey, convey.ShouldEqual, ChipDevKey(0))
+		convey.So(rateSample.Value, convey.ShouldEqual, (rateNextValue-rateInitValue)/rateInterval.Seconds())
+
+		meta, ok := GetMetricMeta(testCounterDesc)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(RateDescs(), convey.ShouldContain, meta.rateDesc)
+	})
+}
@@ -0,0 +1,254 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package common for general collector
+package common
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/prometheus/common/model"
+)
+
+// the actions of relabel rule
+const (
+	// RelabelRenameMetric rename the metric to Replacement, which can refer to the groups of MetricRegex
+	RelabelRenameMetric = "rename_metric"
+	// RelabelRenameLabel rename Label to Replacement
+	RelabelRenameLabel = "rename_label"
+	// RelabelDropLabel remove Label
+	RelabelDropLabel = "drop_label"
+	// RelabelScale multiply the value by Factor, and set the unit to Unit when it is not empty
+	RelabelScale = "scale"
+	// RelabelDrop drop the series, whose value of Label matches ValueRegex when Label is not empty
+	RelabelDrop = "drop"
+)
+
+// relabeler the rules applied to samples before exposition, nil when no rule is configured
+var relabeler *Relabeler
+
+// RelabelRule the rule rewriting samples before exposition, the rules are applied in order
+type RelabelRule struct {
+	// Action one of rename_metric, rename_label, drop_label, scale and drop
+	Action string `json:"action"`
+	// MetricRegex the rule applies to the metrics whose name fully matches it, all metrics when empty
+	MetricRegex string `json:"metric_regex"`
+	// Label the label renamed, dropped, or matched by drop rule
+	Label string `json:"label"`
+	// ValueRegex the regex fully matching the value of Label in drop rule
+	ValueRegex string `json:"value_regex"`
+	// Replacement the new name of metric or label
+	Replacement string `json:"replacement"`
+	// Factor the factor multiplied to the value in scale rule
+	Factor float64 `json:"factor"`
+	// Unit the new unit in scale rule
+	Unit string `json:"unit"`
+}
+
+type compiledRule struct {
+	RelabelRule
+	metricReg *regexp.Regexp
+	valueReg  *regexp.Regexp
+}
+
+// Relabeler apply the relabel rules to samples
+type Relabeler struct {
+	rules []compiledRule
+}
+
+// SetRelabelRules set the rules applied to samples before exposition, empty to disable.
+// must be called before collecting
+func SetRelabelRules(rules []RelabelRule) error {
+	if len(rules) == 0 {
+		relabeler = nil
+		return nil
+	}
+	r, err := NewRelabeler(rules)
+	if err != nil {
+		return err
+	}
+	relabeler = r
+	return nil
+}
+
+// RelabelSamples apply the configured relabel rules to samples, the dropped samples are removed
+func RelabelSamples(samples []MetricSample) []MetricSample {
+	if relabeler == nil {
+		return samples
+	}
+	return relabeler.Apply(samples)
+}
+
+// NewRelabeler validate and compile the relabel rules
+func NewRelabeler(rules []RelabelRule) (*Relabeler, error) {
+	r := &Relabeler{rules: make([]compiledRule, 0, len(rules))}
+	for i, rule := range rules {
+		compiled, err := compileRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("relabel rule %d is invalid: %v", i, err)
+		}
+		r.rules = append(r.rules, compiled)
+	}
+	return r, nil
+}
+
+func compileRule(rule RelabelRule) (compiledRule, error) {
+	compiled := compiledRule{RelabelRule: rule}
+	var err error
+	if rule.MetricRegex != "" {
+		if compiled.metricReg, err = anchoredRegexp(rule.MetricRegex); err != nil {
+			return compiledRule{}, err
+		}
+	}
+	switch rule.Action {
+	case RelabelRenameMetric:
+		if rule.Replacement == "" {
+			return compiledRule{}, errors.New("replacement is required")
+		}
+		// the referred groups are only known when replacing, so only the literal name is checked
+		if compiled.metricReg == nil && !model.IsValidMetricName(model.LabelValue(rule.Replacement)) {
+			return compiledRule{}, fmt.Errorf("metric name %s is invalid", rule.Replacement)
+		}
+	case RelabelRenameLabel:
+		if rule.Label == "" || !model.LabelName(rule.Replacement).IsValid() {
+			return compiledRule{}, errors.New("label and a valid replacement are required")
+		}
+	case RelabelDropLabel:
+		if rule.Label == "" {
+			return compiledRule{}, errors.New("label is required")
+		}
+	case RelabelScale:
+		if rule.Factor == 0 {
+			return compiledRule{}, errors.New("factor is required")
+		}
+	case RelabelDrop:
+		if rule.Label != "" {
+			if compiled.valueReg, err = anchoredRegexp(rule.ValueRegex); err != nil {
+				return compiledRule{}, err
+			}
+		}
+	default:
+		return compiledRule{}, fmt.Errorf("action %s is not supported", rule.Action)
+	}
+	return compiled, nil
+}
+
+func anchoredRegexp(expr string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + expr + ")$")
+}
+
+// Apply apply the rules to samples in order, the dropped samples are removed
+func (r *Relabeler) Apply(samples []MetricSample) []MetricSample {
+	res := samples[:0]
+	for i := range samples {
+		sample := samples[i]
+		if r.applySample(&sample) {
+			res = append(res, sample)
+		}
+	}
+	return res
+}
+
+// applySample apply the rules to sample, false when the sample is dropped
+func (r *Relabeler) applySample(sample *MetricSample) bool {
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if rule.metricReg != nil && !rule.metricReg.MatchString(sample.Name) {
+			continue
+		}
+		switch rule.Action {
+		case RelabelRenameMetric:
+			if rule.metricReg != nil {
+				sample.Name = rule.metricReg.ReplaceAllString(sample.Name, rule.Replacement)
+			} else {
+				sample.Name = rule.Replacement
+			}
+		case RelabelRenameLabel:
+			sample.renameLabel(rule.Label, rule.Replacement)
+		case RelabelDropLabel:
+			sample.dropLabel(rule.Label)
+		case RelabelScale:
+			sample.Value *= rule.Factor
+			// the field value in original type is not scaled
+			sample.FieldValue = nil
+			if rule.Unit != "" {
+				sample.Unit = rule.Unit
+			}
+		case RelabelDrop:
+			if rule.valueReg == nil || rule.valueReg.MatchString(sample.Label(rule.Label)) {
+				return false
+			}
+		default:
+		}
+	}
+	return true
+}
+
+func (s *MetricSample) renameLabel(from, to string) {
+	idx := labelIndex(s.LabelNames, from)
+	if idx < 0 {
+		return
+	}
+	names := make([]string, len(s.LabelNames))
+	copy(names, s.LabelNames)
+	names[idx] = to
+	s.LabelNames = names
+	s.SeriesLabels = replaceLabel(s.SeriesLabels, from, to)
+	if s.InfoLabel == from {
+		s.InfoLabel = to
+	}
+}
+
+func (s *MetricSample) dropLabel(name string) {
+	idx := labelIndex(s.LabelNames, name)
+	if idx < 0 || idx >= len(s.LabelValues) {
+		return
+	}
+	names := make([]string, 0, len(s.LabelNames)-1)
+	names = append(append(names, s.LabelNames[:idx]...), s.LabelNames[idx+1:]...)
+	values := make([]string, 0, len(s.LabelValues)-1)
+	values = append(append(values, s.LabelValues[:idx]...), s.LabelValues[idx+1:]...)
+	s.LabelNames, s.LabelValues = names, values
+	s.SeriesLabels = replaceLabel(s.SeriesLabels, name, "")
+	if s.InfoLabel == name {
+		s.InfoLabel = ""
+	}
+}
+
+func labelIndex(labelNames []string, name string) int {
+	for i, labelName := range labelNames {
+		if labelName == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// replaceLabel replace label from by to in labels, remove it when to is empty
+func replaceLabel(labels []string, from, to string) []string {
+	if labelIndex(labels, from) < 0 {
+		return labels
+	}
+	res := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if label != from {
+			res = append(res, label)
+		} else if to != "" {
+			res = append(res, to)
+		}
+	}
+	return res
+}
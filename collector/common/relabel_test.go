@@ -0,0 +1,106 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package common for general collector
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	relabelValue  = 2
+	relabelFactor = 1024
+)
+
+var relabelSeriesDesc = BuildDescWithOpts("test_relabel_series", "the test series, unit is 'KB'",
+	[]string{"npuID", "type"}, WithSeriesLabels("type"))
+
+func buildRelabelSamples(t *testing.T) []MetricSample {
+	samples := make([]MetricSample, 0)
+	for _, typ := range []string{"rx", "tx"} {
+		sample, err := NewSample(relabelSeriesDesc, relabelValue, time.Time{}, []string{"0", typ})
+		if err != nil {
+			t.Fatalf("build sample failed: %v", err)
+		}
+		sample.DevKey = ChipDevKey(0)
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+// TestNewRelabeler test the validation of relabel rules
+func TestNewRelabeler(t *testing.T) {
+	convey.Convey("TestNewRelabeler", t, func() {
+		invalidRules := []RelabelRule{
+			{Action: "unknown"},
+			{Action: RelabelRenameMetric},
+			{Action: RelabelRenameMetric, Replacement: "invalid-name"},
+			{Action: RelabelRenameLabel, Label: "a", Replacement: "invalid-name"},
+			{Action: RelabelDropLabel},
+			{Action: RelabelScale},
+			{Action: RelabelDrop, Label: "a", ValueRegex: "("},
+			{Action: RelabelDrop, MetricRegex: "("},
+		}
+		for _, rule := range invalidRules {
+			_, err := NewRelabeler([]RelabelRule{rule})
+			convey.So(err, convey.ShouldNotBeNil)
+		}
+	})
+}
+
+// TestRelabelerApply test the rules applied to samples in order
+func TestRelabelerApply(t *testing.T) {
+	convey.Convey("TestRelabelerApply", t, func() {
+		convey.Convey("rename metric and label, and scale unit", func() {
+			r, err := NewRelabeler([]RelabelRule{
+				{Action: RelabelRenameMetric, MetricRegex: "test_relabel_(.*)", Replacement: "test_v2_${1}_bytes"},
+				{Action: RelabelRenameLabel, Label: "npuID", Replacement: "id"},
+				{Action: RelabelRenameLabel, Label: "type", Replacement: "direction"},
+				{Action: RelabelScale, MetricRegex: "test_v2_.*", Factor: relabelFactor, Unit: "bytes"},
+			})
+			convey.So(err, convey.ShouldBeNil)
+			samples := r.Apply(buildRelabelSamples(t))
+			convey.So(len(samples), convey.ShouldEqual, 2)
+			convey.So(samples[0].Name, convey.ShouldEqual, "test_v2_series_bytes")
+			convey.So(samples[0].LabelNames, convey.ShouldResemble, []string{"id", "direction"})
+			convey.So(samples[0].Value, convey.ShouldEqual, relabelValue*relabelFactor)
+			convey.So(samples[0].Unit, convey.ShouldEqual, "bytes")
+
+			fields := ToTelegrafFields(samples, make(map[string]map[string]interface{}))
+			convey.So(fields["0"]["test_v2_series_bytes_tx"], convey.ShouldEqual, relabelValue*relabelFactor)
+			_, err = ToPrometheusMetric(samples[0])
+			convey.So(err, convey.ShouldBeNil)
+		})
+		convey.Convey("drop label and series", func() {
+			r, err := NewRelabeler([]RelabelRule{
+				{Action: RelabelDrop, Label: "type", ValueRegex: "r.*"},
+				{Action: RelabelDropLabel, Label: "npuID"},
+			})
+			convey.So(err, convey.ShouldBeNil)
+			samples := r.Apply(buildRelabelSamples(t))
+			convey.So(len(samples), convey.ShouldEqual, 1)
+			convey.So(samples[0].LabelNames, convey.ShouldResemble, []string{"type"})
+			convey.So(samples[0].LabelValues, convey.ShouldResemble, []string{"tx"})
+		})
+		convey.Convey("the samples are not changed when no rule is configured", func() {
+			convey.So(SetRelabelRules(nil), convey.ShouldBeNil)
+			samples := buildRelabelSamples(t)
+			convey.So(RelabelSamples(samples), convey.ShouldResemble, samples)
+		})
+	})
+}
@@ -0,0 +1,382 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package common for general collector
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/professorshandian/npu-exporter/collector/container"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+// MetricType the type of metric sample
+type MetricType int
+
+const (
+	// GaugeType the value can go up and down
+	GaugeType MetricType = iota
+	// CounterType the value only goes up, unless the chip is reset
+	CounterType
+)
+
+const (
+	// DevKeySep separator between logicID and vdevID in the key of vNPU
+	DevKeySep = "_"
+)
+
+var (
+	// unitReg the unit is declared in the help text, such as "the npu hbm used memory, unit is 'MB'"
+	unitReg = regexp.MustCompile(`unit is '([^']+)'`)
+
+	metaByDesc sync.Map
+	metaByName sync.Map
+	// extraLabelNames the label names of metrics registered by RegisterLabelNames
+	extraLabelNames sync.Map
+)
+
+// String name of metric type
+func (t MetricType) String() string {
+	if t == CounterType {
+		return "counter"
+	}
+	return "gauge"
+}
+
+// MetricMeta the metadata of metric, registered when the desc of metric is built
+type MetricMeta struct {
+	Name       string
+	Help       string
+	Unit       string
+	Type       MetricType
+	LabelNames []string
+	// InfoLabel the label carrying the information of info metric whose value is always 1,
+	// backends which flatten labels into fields (such as Telegraf) use its value as the field value
+	InfoLabel string
+	// SeriesLabels the labels distinguishing series of the metric on one device,
+	// backends which flatten labels into fields append their values to the field name
+	SeriesLabels []string
+	desc         *prometheus.Desc
+	// rateDesc the desc of per-second rate derived from counter, nil for gauge
+	rateDesc *prometheus.Desc
+}
+
+// Desc the prometheus desc of metric
+func (m *MetricMeta) Desc() *prometheus.Desc {
+	return m.desc
+}
+
+// MetaOption option of metric metadata
+type MetaOption func(meta *MetricMeta)
+
+// WithType set the type of metric, default is gauge
+func WithType(t MetricType) MetaOption {
+	return func(meta *MetricMeta) {
+		meta.Type = t
+	}
+}
+
+// WithUnit set the unit of metric, default is parsed from the help text
+func WithUnit(unit string) MetaOption {
+	return func(meta *MetricMeta) {
+		meta.Unit = unit
+	}
+}
+
+// WithInfoLabel mark the metric as info metric and set the label carrying the information
+func WithInfoLabel(label string) MetaOption {
+	return func(meta *MetricMeta) {
+		meta.InfoLabel = label
+	}
+}
+
+// WithSeriesLabels set the labels distinguishing series of the metric on one device
+func WithSeriesLabels(labels ...string) MetaOption {
+	return func(meta *MetricMeta) {
+		meta.SeriesLabels = labels
+	}
+}
+
+// BuildDescWithOpts build desc and register the metadata of metric
+func BuildDescWithOpts(name string, help string, label []string, opts ...MetaOption) *prometheus.Desc {
+	desc := prometheus.NewDesc(name, help, label, nil)
+	meta := &MetricMeta{Name: name, Help: help, Type: GaugeType, LabelNames: label, desc: desc}
+	if match := unitReg.FindStringSubmatch(help); len(match) > 1 {
+		meta.Unit = match[1]
+	}
+	for _, opt := range opts {
+		opt(meta)
+	}
+	if meta.Type == CounterType {
+		meta.rateDesc = buildRateDesc(meta)
+	}
+	metaByDesc.Store(desc, meta)
+	metaByName.Store(name, meta)
+	return desc
+}
+
+// RegisterLabelNames register the label names of metrics whose desc is not built by BuildDescWithOpts, such as
+// the metrics of health check, so that the constant labels are validated against them
+func RegisterLabelNames(names ...string) {
+	for _, name := range names {
+		extraLabelNames.Store(name, true)
+	}
+}
+
+// labelNamesInUse the label names of all metrics, including the labels of metrics in v2 schema
+func labelNamesInUse() map[string]bool {
+	res := make(map[string]bool)
+	metaByName.Range(func(_, value interface{}) bool {
+		if meta, ok := value.(*MetricMeta); ok {
+			for _, name := range meta.LabelNames {
+				res[name] = true
+			}
+		}
+		return true
+	})
+	extraLabelNames.Range(func(key, _ interface{}) bool {
+		if name, ok := key.(string); ok {
+			res[name] = true
+		}
+		return true
+	})
+	for _, mapping := range schemaV2Mappings {
+		if mapping.IndexLabel != "" {
+			res[mapping.IndexLabel] = true
+		}
+		for _, to := range mapping.LabelRenames {
+			res[to] = true
+		}
+	}
+	return res
+}
+
+// GetMetricMeta get the metadata of metric by desc
+func GetMetricMeta(desc *prometheus.Desc) (*MetricMeta, bool) {
+	value, ok := metaByDesc.Load(desc)
+	if !ok {
+		return nil, false
+	}
+	meta, ok := value.(*MetricMeta)
+	return meta, ok
+}
+
+// GetMetricMetaByName get the metadata of metric by name
+func GetMetricMetaByName(name string) (*MetricMeta, bool) {
+	value, ok := metaByName.Load(name)
+	if !ok {
+		return nil, false
+	}
+	meta, ok := value.(*MetricMeta)
+	return meta, ok
+}
+
+// MetricSample a typed sample emitted once by collector, every output backend renders from it
+type MetricSample struct {
+	Name        string
+	Help        string
+	Unit        string
+	Type        MetricType
+	LabelNames  []string
+	LabelValues []string
+	Value       float64
+	// FieldValue the value in the type given by collector, such as integer or string, which is rendered as
+	// telegraf field instead of Value when it is not nil, so that the type of existing fields is kept
+	FieldValue interface{}
+	// Timestamp the time when the value is collected, zero means the time of rendering
+	Timestamp time.Time
+	// DevKey the device which the sample belongs to, logicID for chip, logicID_vdevID for vNPU,
+	// empty for the sample which is not related to any device
+	DevKey string
+	// SeriesLabels and InfoLabel are copied from the metadata, so that they follow the relabeled label names
+	SeriesLabels []string
+	InfoLabel    string
+}
+
+// NewSample build a sample of the registered metric
+func NewSample(desc *prometheus.Desc, value float64, timestamp time.Time, labelValues []string) (MetricSample,
+	error) {
+	meta, ok := GetMetricMeta(desc)
+	if !ok {
+		return MetricSample{}, fmt.Errorf("metric of desc %s is not registered", desc.String())
+	}
+	if len(labelValues) != len(meta.LabelNames) {
+		return MetricSample{}, fmt.Errorf("metric %s has %d labels, but %d values are given", meta.Name,
+			len(meta.LabelNames), len(labelValues))
+	}
+	values := make([]string, len(labelValues))
+	copy(values, labelValues)
+	return MetricSample{
+		Name:         meta.Name,
+		Help:         meta.Help,
+		Unit:         meta.Unit,
+		Type:         meta.Type,
+		LabelNames:   meta.LabelNames,
+		LabelValues:  values,
+		Value:        value,
+		Timestamp:    timestamp,
+		SeriesLabels: meta.SeriesLabels,
+		InfoLabel:    meta.InfoLabel,
+	}, nil
+}
+
+// Label get the value of label, empty when the label does not exist
+func (s *MetricSample) Label(name string) string {
+	for i, labelName := range s.LabelNames {
+		if labelName == name && i < len(s.LabelValues) {
+			return s.LabelValues[i]
+		}
+	}
+	return ""
+}
+
+// SampleSink receives the samples emitted by collectors, sinks derived by WithDevKey share the same samples
+type SampleSink struct {
+	samples *[]MetricSample
+	devKey  string
+	rates   *RateTracker
+}
+
+// NewSampleSink create an empty sample sink, the rates of counters are derived by the default rate tracker
+func NewSampleSink() SampleSink {
+	samples := make([]MetricSample, 0)
+	return SampleSink{samples: &samples, rates: defaultRateTracker}
+}
+
+// WithDevKey derive a sink whose samples belong to the device
+func (s SampleSink) WithDevKey(devKey string) SampleSink {
+	return SampleSink{samples: s.samples, devKey: devKey, rates: s.rates}
+}
+
+// Add add sample into sink, the device key of sink is used when the sample does not have one.
+// the per-second rate is added following the counter sample once it can be derived
+func (s SampleSink) Add(sample MetricSample) {
+	if s.samples == nil {
+		logger.Error("sample sink is not initialized")
+		return
+	}
+	if sample.DevKey == "" {
+		sample.DevKey = s.devKey
+	}
+	*s.samples = append(*s.samples, sample)
+	if sample.Type != CounterType || s.rates == nil {
+		return
+	}
+	if rateSample, ok := s.rates.RateSample(sample); ok {
+		*s.samples = append(*s.samples, rateSample)
+	}
+}
+
+// Samples all samples in sink
+func (s SampleSink) Samples() []MetricSample {
+	if s.samples == nil {
+		return nil
+	}
+	return *s.samples
+}
+
+// ChipDevKey the device key of chip
+func ChipDevKey(logicID int32) string {
+	return strconv.Itoa(int(logicID))
+}
+
+// VDevKey the device key of vNPU
+func VDevKey(logicID int32, vDevID uint32) string {
+	return ChipDevKey(logicID) + DevKeySep + strconv.Itoa(int(vDevID))
+}
+
+// GatherSamples emit samples of every collector in the chains
+func GatherSamples(n *NpuCollector, containerMap map[int32]container.DevicesInfo, chips []HuaWeiAIChip,
+	chains ...[]MetricsCollector) []MetricSample {
+	sink := NewSampleSink()
+	for _, chain := range chains {
+		for _, c := range chain {
+			c.UpdateSamples(sink, n, containerMap, chips)
+		}
+	}
+	return sink.Samples()
+}
+
+// ToPrometheusMetric render sample as prometheus metric
+func ToPrometheusMetric(sample MetricSample) (prometheus.Metric, error) {
+	var desc *prometheus.Desc
+	// the desc is rebuilt when the metric is relabeled or enriched
+	if meta, ok := GetMetricMetaByName(sample.Name); ok && equalLabels(meta.LabelNames, sample.LabelNames) {
+		desc = meta.desc
+	} else {
+		desc = prometheus.NewDesc(sample.Name, sample.Help, sample.LabelNames, nil)
+	}
+	valueType := prometheus.GaugeValue
+	if sample.Type == CounterType {
+		valueType = prometheus.CounterValue
+	}
+	metric, err := prometheus.NewConstMetric(desc, valueType, sample.Value, sample.LabelValues...)
+	if err != nil {
+		return nil, err
+	}
+	if sample.Timestamp.IsZero() {
+		return metric, nil
+	}
+	return prometheus.NewMetricWithTimestamp(sample.Timestamp, metric), nil
+}
+
+// ToTelegrafFields render samples as telegraf fields, keyed by the device key,
+// the samples not related to any device are keyed by GeneralDevTagKey
+func ToTelegrafFields(samples []MetricSample,
+	fieldsMap map[string]map[string]interface{}) map[string]map[string]interface{} {
+	for i := range samples {
+		sample := &samples[i]
+		devKey := sample.DevKey
+		if devKey == "" {
+			devKey = GeneralDevTagKey
+		}
+		if fieldsMap[devKey] == nil {
+			fieldsMap[devKey] = make(map[string]interface{})
+		}
+		fieldName := sample.Name
+		var value interface{} = sample.Value
+		for _, label := range sample.SeriesLabels {
+			if labelValue := sample.Label(label); labelValue != "" {
+				fieldName += "_" + labelValue
+			}
+		}
+		if sample.InfoLabel != "" {
+			value = sample.Label(sample.InfoLabel)
+		}
+		if sample.FieldValue != nil {
+			value = sample.FieldValue
+		}
+		fieldsMap[devKey][fieldName] = value
+	}
+	return fieldsMap
+}
+
+func equalLabels(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,154 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package common for general collector
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	sampleValue  = 42
+	sampleLogic  = 1
+	sampleVDevID = 100
+)
+
+var (
+	sampleGaugeDesc = BuildDescWithOpts("test_sample_gauge", "the test gauge, unit is 'MB'",
+		[]string{"id"})
+	sampleCounterDesc = BuildDescWithOpts("test_sample_counter", "the test counter", []string{"id"},
+		WithType(CounterType))
+	sampleSeriesDesc = BuildDescWithOpts("test_sample_series", "the test series", []string{"id", "type"},
+		WithSeriesLabels("type"))
+	sampleInfoDesc = BuildDescWithOpts("test_sample_info", "the test info", []string{"version"},
+		WithInfoLabel("version"))
+)
+
+// TestBuildDescWithOpts test the metadata registered when building desc
+func TestBuildDescWithOpts(t *testing.T) {
+	convey.Convey("TestBuildDescWithOpts", t, func() {
+		meta, ok := GetMetricMeta(sampleGaugeDesc)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(meta.Unit, convey.ShouldEqual, "MB")
+		convey.So(meta.Type, convey.ShouldEqual, GaugeType)
+		convey.So(meta.Desc(), convey.ShouldEqual, sampleGaugeDesc)
+
+		meta, ok = GetMetricMetaByName("test_sample_counter")
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(meta.Type.String(), convey.ShouldEqual, "counter")
+		convey.So(meta.Unit, convey.ShouldBeEmpty)
+	})
+}
+
+// TestNewSample test building sample of registered metric
+func TestNewSample(t *testing.T) {
+	convey.Convey("TestNewSample", t, func() {
+		convey.Convey("success when the metric is registered", func() {
+			labels := []string{"0"}
+			sample, err := NewSample(sampleGaugeDesc, sampleValue, time.Time{}, labels)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(sample.Name, convey.ShouldEqual, "test_sample_gauge")
+			convey.So(sample.Label("id"), convey.ShouldEqual, "0")
+			labels[0] = "changed"
+			convey.So(sample.Label("id"), convey.ShouldEqual, "0")
+		})
+		convey.Convey("failed when the metric is not registered", func() {
+			desc := prometheus.NewDesc("test_sample_unregistered", "", nil, nil)
+			_, err := NewSample(desc, sampleValue, time.Time{}, nil)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+		convey.Convey("failed when the label values mismatch", func() {
+			_, err := NewSample(sampleGaugeDesc, sampleValue, time.Time{}, nil)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+	})
+}
+
+// TestSampleSink test the device key of samples in sink
+func TestSampleSink(t *testing.T) {
+	convey.Convey("TestSampleSink", t, func() {
+		sink := NewSampleSink()
+		sample, err := NewSample(sampleGaugeDesc, sampleValue, time.Time{}, []string{"0"})
+		convey.So(err, convey.ShouldBeNil)
+		sink.Add(sample)
+		sink.WithDevKey(ChipDevKey(sampleLogic)).Add(sample)
+		sink.WithDevKey(VDevKey(sampleLogic, sampleVDevID)).Add(sample)
+
+		samples := sink.Samples()
+		convey.So(len(samples), convey.ShouldEqual, 3)
+		convey.So(samples[0].DevKey, convey.ShouldBeEmpty)
+		convey.So(samples[1].DevKey, convey.ShouldEqual, "1")
+		convey.So(samples[2].DevKey, convey.ShouldEqual, "1_100")
+
+		convey.So(SampleSink{}.Samples(), convey.ShouldBeNil)
+	})
+}
+
+// TestToPrometheusMetric test rendering sample as prometheus metric
+func TestToPrometheusMetric(t *testing.T) {
+	convey.Convey("TestToPrometheusMetric", t, func() {
+		convey.Convey("gauge without timestamp", func() {
+			sample, err := NewSample(sampleGaugeDesc, sampleValue, time.Time{}, []string{"0"})
+			convey.So(err, convey.ShouldBeNil)
+			metric, err := ToPrometheusMetric(sample)
+			convey.So(err, convey.ShouldBeNil)
+			out := &dto.Metric{}
+			convey.So(metric.Write(out), convey.ShouldBeNil)
+			convey.So(out.GetGauge().GetValue(), convey.ShouldEqual, sampleValue)
+			convey.So(out.TimestampMs, convey.ShouldBeNil)
+		})
+		convey.Convey("counter with timestamp", func() {
+			sample, err := NewSample(sampleCounterDesc, sampleValue, time.Now(), []string{"0"})
+			convey.So(err, convey.ShouldBeNil)
+			metric, err := ToPrometheusMetric(sample)
+			convey.So(err, convey.ShouldBeNil)
+			out := &dto.Metric{}
+			convey.So(metric.Write(out), convey.ShouldBeNil)
+			convey.So(out.GetCounter().GetValue(), convey.ShouldEqual, sampleValue)
+			convey.So(out.TimestampMs, convey.ShouldNotBeNil)
+		})
+	})
+}
+
+// TestToTelegrafFields test rendering samples as telegraf fields
+func TestToTelegrafFields(t *testing.T) {
+	convey.Convey("TestToTelegrafFields", t, func() {
+		sink := NewSampleSink()
+		chipSink := sink.WithDevKey(ChipDevKey(sampleLogic))
+		for _, tp := range []string{"avg", "max"} {
+			sample, err := NewSample(sampleSeriesDesc, sampleValue, time.Time{}, []string{"0", tp})
+			convey.So(err, convey.ShouldBeNil)
+			chipSink.Add(sample)
+		}
+		sample, err := NewSample(sampleInfoDesc, 1, time.Time{}, []string{"7.0.0"})
+		convey.So(err, convey.ShouldBeNil)
+		sink.Add(sample)
+		sample, err = NewSample(sampleSeriesDesc, sampleValue, time.Time{}, []string{"0", "min"})
+		convey.So(err, convey.ShouldBeNil)
+		sample.FieldValue = int32(sampleValue)
+		chipSink.Add(sample)
+
+		fieldsMap := ToTelegrafFields(sink.Samples(), make(map[string]map[string]interface{}))
+		convey.So(fieldsMap["1"]["test_sample_series_min"], convey.ShouldEqual, int32(sampleValue))
+		convey.So(fieldsMap["1"]["test_sample_series_avg"], convey.ShouldEqual, sampleValue)
+		convey.So(fieldsMap["1"]["test_sample_series_max"], convey.ShouldEqual, sampleValue)
+		convey.So(fieldsMap[GeneralDevTagKey]["test_sample_info"], convey.ShouldEqual, "7.0.0")
+	})
+}
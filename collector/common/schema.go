@@ -0,0 +1,316 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package common for general collector
+package common
+
+import (
+	"regexp"
+)
+
+const (
+	// SchemaV1 the original metric schema
+	SchemaV1 = "v1"
+	// SchemaV2 the metric schema with base units and the naming convention of prometheus
+	SchemaV2 = "v2"
+
+	kbToBytes     = 1 << 10
+	mbToBytes     = 1 << 20
+	gbToBytes     = 1 << 30
+	mbPerMsToBps  = mbToBytes * 1000
+	mhzToHz       = 1e6
+	mbitToBit     = 1e6
+	msToSeconds   = 1e-3
+	percentToRate = 1e-2
+
+	unitBytes   = "bytes"
+	unitBps     = "bytes/s"
+	unitRatio   = "ratio"
+	unitCelsius = "celsius"
+	unitWatts   = "watts"
+	unitVolts   = "volts"
+	unitHertz   = "hertz"
+	unitSeconds = "seconds"
+
+	typeCounter = "counter"
+	typeGauge   = "gauge"
+)
+
+// SchemaMapping the mapping from the v1 metrics matched by V1Pattern to the v2 metric
+type SchemaMapping struct {
+	// V1Pattern the regex fully matching the names of v1 metrics
+	V1Pattern string `json:"v1_pattern"`
+	// V2Name the name of v2 metric, empty means the v1 metric is not exported in v2
+	V2Name string `json:"v2_name"`
+	// IndexLabel the label carrying the first group of V1Pattern, such as the link index of hccs,
+	// the value is "0" when the group is empty
+	IndexLabel string `json:"index_label,omitempty"`
+	// Factor the v2 value is the v1 value multiplied by it
+	Factor float64 `json:"factor"`
+	// V1Unit the unit of v1 metric
+	V1Unit string `json:"v1_unit,omitempty"`
+	// V2Unit the unit of v2 metric
+	V2Unit string `json:"v2_unit,omitempty"`
+	// V2Type the type of v2 metric, gauge or counter
+	V2Type string `json:"v2_type,omitempty"`
+	// V2Help the help of v2 metric, it is required when the v1 metrics folded into one v2 metric have different
+	// helps, such as the help naming the link index, since a metric family has only one help
+	V2Help string `json:"v2_help,omitempty"`
+	// LabelRenames the v1 label names renamed in v2
+	LabelRenames map[string]string `json:"label_renames,omitempty"`
+	// Note the remark for migration
+	Note string `json:"note,omitempty"`
+}
+
+type compiledMapping struct {
+	SchemaMapping
+	reg *regexp.Regexp
+}
+
+var (
+	// schemaV2Mappings the v1 to v2 mapping table, the first matched mapping is used
+	schemaV2Mappings = []SchemaMapping{
+		{V1Pattern: ".*" + RateSuffix, Note: "derived rate, use rate() on the _total counter instead"},
+
+		// npu
+		gaugeMapping("machine_npu_nums", "npu_machine_chips", 1, "", ""),
+		ratioMapping("npu_chip_info_utilization", "npu_chip_aicore_utilization_ratio"),
+		ratioMapping("npu_chip_info_overall_utilization", "npu_chip_utilization_ratio"),
+		ratioMapping("npu_chip_info_vector_utilization", "npu_chip_vector_utilization_ratio"),
+		gaugeMapping("npu_chip_info_temperature", "npu_chip_temperature_celsius", 1, unitCelsius, unitCelsius),
+		gaugeMapping("npu_chip_info_power", "npu_chip_power_watts", 1, "W", unitWatts),
+		gaugeMapping("npu_chip_info_voltage", "npu_chip_voltage_volts", 1, "V", unitVolts),
+		gaugeMapping("npu_chip_info_aicore_current_freq", "npu_chip_aicore_frequency_hertz", mhzToHz, "MHz",
+			unitHertz),
+		gaugeMapping("npu_chip_info_health_status", "npu_chip_health_status", 1, "", ""),
+		gaugeMapping("npu_chip_info_network_status", "npu_chip_network_health_status", 1, "", ""),
+		gaugeMapping("npu_chip_info_process_info_num", "npu_chip_processes", 1, "", ""),
+		gaugeMapping("npu_chip_info_process_info", "npu_chip_process_memory_bytes", mbToBytes, "MB", unitBytes),
+		{V1Pattern: "npu_chip_info_error_code(?:_(\\d+))?", V2Name: "npu_chip_error_code", IndexLabel: "index",
+			Factor: 1, V2Type: typeGauge},
+		{V1Pattern: "npu_chip_info_name", V2Name: "npu_chip_info", Factor: 1, V2Type: typeGauge},
+		{V1Pattern: "npu_container_info", V2Name: "npu_container_info", Factor: 1, V2Type: typeGauge,
+			LabelRenames: map[string]string{"npuID": npuID, "containerID": "container_id",
+				"containerName": "container_runtime_name"}},
+		ratioMapping("container_npu_utilization", "npu_container_aicore_utilization_ratio"),
+		gaugeMapping("container_npu_total_memory", "npu_container_memory_total_bytes", mbToBytes, "MB", unitBytes),
+		gaugeMapping("container_npu_used_memory", "npu_container_memory_used_bytes", mbToBytes, "MB", unitBytes),
+
+		// ddr and hbm
+		gaugeMapping("npu_chip_info_total_memory", "npu_chip_memory_total_bytes", mbToBytes, "MB", unitBytes),
+		gaugeMapping("npu_chip_info_used_memory", "npu_chip_memory_used_bytes", mbToBytes, "MB", unitBytes),
+		gaugeMapping("npu_chip_info_hbm_total_memory", "npu_chip_hbm_total_bytes", mbToBytes, "MB", unitBytes),
+		gaugeMapping("npu_chip_info_hbm_used_memory", "npu_chip_hbm_used_bytes", mbToBytes, "MB", unitBytes),
+		ratioMapping("npu_chip_info_hbm_utilization", "npu_chip_hbm_utilization_ratio"),
+		ratioMapping("npu_chip_info_hbm_bandwidth_utilization", "npu_chip_hbm_bandwidth_utilization_ratio"),
+		gaugeMapping("npu_chip_info_hbm_temperature", "npu_chip_hbm_temperature_celsius", 1, unitCelsius,
+			unitCelsius),
+		gaugeMapping("npu_chip_info_hbm_ecc_enable_flag", "npu_chip_hbm_ecc_enabled", 1, "", ""),
+		counterMapping("npu_chip_info_hbm_ecc_single_bit_error_cnt", "npu_chip_hbm_ecc_single_bit_errors_total"),
+		counterMapping("npu_chip_info_hbm_ecc_double_bit_error_cnt", "npu_chip_hbm_ecc_double_bit_errors_total"),
+		counterMapping("npu_chip_info_hbm_ecc_total_single_bit_error_cnt",
+			"npu_chip_hbm_ecc_aggregate_single_bit_errors_total"),
+		counterMapping("npu_chip_info_hbm_ecc_total_double_bit_error_cnt",
+			"npu_chip_hbm_ecc_aggregate_double_bit_errors_total"),
+		gaugeMapping("npu_chip_info_hbm_ecc_single_bit_isolated_pages_cnt",
+			"npu_chip_hbm_ecc_single_bit_isolated_pages", 1, "", ""),
+		gaugeMapping("npu_chip_info_hbm_ecc_double_bit_isolated_pages_cnt",
+			"npu_chip_hbm_ecc_double_bit_isolated_pages", 1, "", ""),
+
+		// hccs and sio
+		linkCounterMapping("npu_chip_info_hccs_statistic_info_tx_cnt_(\\d+)",
+			"npu_chip_hccs_transmitted_messages_total", "transmitted message count of the hccs link"),
+		linkCounterMapping("npu_chip_info_hccs_statistic_info_rx_cnt_(\\d+)", "npu_chip_hccs_received_messages_total",
+			"received message count of the hccs link"),
+		linkCounterMapping("npu_chip_info_hccs_statistic_info_crc_err_cnt_(\\d+)",
+			"npu_chip_hccs_crc_errors_total", "crc error count of the hccs link"),
+		{V1Pattern: "npu_chip_info_hccs_bandwidth_info_tx_(\\d+)", V2Name: "npu_chip_hccs_transmit_bytes_per_second",
+			IndexLabel: "link", Factor: gbToBytes, V1Unit: "GB/s", V2Unit: unitBps, V2Type: typeGauge,
+			V2Help: "single-link transmission data bandwidth of the hccs link, unit is 'bytes/s'"},
+		{V1Pattern: "npu_chip_info_hccs_bandwidth_info_rx_(\\d+)", V2Name: "npu_chip_hccs_receive_bytes_per_second",
+			IndexLabel: "link", Factor: gbToBytes, V1Unit: "GB/s", V2Unit: unitBps, V2Type: typeGauge,
+			V2Help: "single-link receive data bandwidth of the hccs link, unit is 'bytes/s'"},
+		gaugeMapping("npu_chip_info_hccs_bandwidth_info_total_tx", "npu_chip_hccs_transmit_total_bytes_per_second",
+			gbToBytes, "GB/s", unitBps),
+		gaugeMapping("npu_chip_info_hccs_bandwidth_info_total_rx", "npu_chip_hccs_receive_total_bytes_per_second",
+			gbToBytes, "GB/s", unitBps),
+		gaugeMapping("npu_chip_info_hccs_bandwidth_info_profiling_time",
+			"npu_chip_hccs_bandwidth_profiling_seconds", msToSeconds, "ms", unitSeconds),
+		counterMapping("npu_chip_info_sio_crc_tx_err_cnt", "npu_chip_sio_crc_transmit_errors_total"),
+		counterMapping("npu_chip_info_sio_crc_rx_err_cnt", "npu_chip_sio_crc_receive_errors_total"),
+
+		// network, roce and optical
+		gaugeMapping("npu_chip_info_bandwidth_tx", "npu_chip_network_transmit_bytes_per_second", mbToBytes, "MB/s",
+			unitBps),
+		gaugeMapping("npu_chip_info_bandwidth_rx", "npu_chip_network_receive_bytes_per_second", mbToBytes, "MB/s",
+			unitBps),
+		gaugeMapping("npu_chip_link_speed", "npu_chip_link_speed_bits_per_second", mbitToBit, "Mb/s", "bits/s"),
+		gaugeMapping("npu_chip_link_up_num", "npu_chip_link_ups", 1, "", ""),
+		gaugeMapping("npu_chip_info_link_status", "npu_chip_link_status", 1, "", ""),
+		{V1Pattern: "npu_chip_(mac|roce)_(.+)_num", V2Name: "npu_chip_${1}_${2}_total", Factor: 1,
+			V2Type: typeCounter},
+		{V1Pattern: "npu_chip_info_rx_(fcs|ecn)_num", V2Name: "npu_chip_rx_${1}_total", Factor: 1,
+			V2Type: typeCounter},
+		gaugeMapping("npu_chip_optical_temp", "npu_chip_optical_temperature_celsius", 1, unitCelsius, unitCelsius),
+		{V1Pattern: "npu_chip_optical_tx_power_(\\d+)", V2Name: "npu_chip_optical_tx_power", IndexLabel: "lane",
+			Factor: 1, V2Type: typeGauge, V2Help: "npu interface receive optical-tx-power of the lane"},
+		{V1Pattern: "npu_chip_optical_rx_power_(\\d+)", V2Name: "npu_chip_optical_rx_power", IndexLabel: "lane",
+			Factor: 1, V2Type: typeGauge, V2Help: "npu interface receive optical-rx-power of the lane"},
+
+		// pcie
+		gaugeMapping("npu_chip_info_pcie_rx_p_bw", "npu_chip_pcie_rx_posted_bytes_per_second", mbPerMsToBps,
+			"MB/ms", unitBps),
+		gaugeMapping("npu_chip_info_pcie_rx_np_bw", "npu_chip_pcie_rx_non_posted_bytes_per_second", mbPerMsToBps,
+			"MB/ms", unitBps),
+		gaugeMapping("npu_chip_info_pcie_rx_cpl_bw", "npu_chip_pcie_rx_completion_bytes_per_second", mbPerMsToBps,
+			"MB/ms", unitBps),
+		gaugeMapping("npu_chip_info_pcie_tx_p_bw", "npu_chip_pcie_tx_posted_bytes_per_second", mbPerMsToBps,
+			"MB/ms", unitBps),
+		gaugeMapping("npu_chip_info_pcie_tx_np_bw", "npu_chip_pcie_tx_non_posted_bytes_per_second", mbPerMsToBps,
+			"MB/ms", unitBps),
+		gaugeMapping("npu_chip_info_pcie_tx_cpl_bw", "npu_chip_pcie_tx_completion_bytes_per_second", mbPerMsToBps,
+			"MB/ms", unitBps),
+
+		// vnpu
+		ratioMapping("vnpu_pod_aicore_utilization", "npu_vnpu_aicore_utilization_ratio"),
+		gaugeMapping("vnpu_pod_total_memory", "npu_vnpu_memory_total_bytes", kbToBytes, "KB", unitBytes),
+		gaugeMapping("vnpu_pod_used_memory", "npu_vnpu_memory_used_bytes", kbToBytes, "KB", unitBytes),
+
+		// high-frequency sampling
+		ratioMapping("npu_chip_info_utilization_sampled", "npu_chip_aicore_utilization_sampled_ratio"),
+		ratioMapping("npu_chip_info_overall_utilization_sampled", "npu_chip_utilization_sampled_ratio"),
+		ratioMapping("npu_chip_info_vector_utilization_sampled", "npu_chip_vector_utilization_sampled_ratio"),
+		ratioMapping("npu_chip_info_hbm_bandwidth_utilization_sampled",
+			"npu_chip_hbm_bandwidth_utilization_sampled_ratio"),
+		gaugeMapping("npu_chip_info_power_sampled", "npu_chip_power_sampled_watts", 1, "W", unitWatts),
+
+		// container, pod and job aggregation, the job is in label job_name
+		{V1Pattern: "npu_(container|pod|job)_avg_utilization", V2Name: "npu_${1}_avg_aicore_utilization_ratio",
+			Factor: percentToRate, V1Unit: "%", V2Unit: unitRatio, V2Type: typeGauge},
+		{V1Pattern: "npu_(container|pod|job)_hbm_(used|total)_memory", V2Name: "npu_${1}_hbm_${2}_bytes",
+			Factor: mbToBytes, V1Unit: "MB", V2Unit: unitBytes, V2Type: typeGauge},
+		{V1Pattern: "npu_(container|pod|job)_power", V2Name: "npu_${1}_power_watts", Factor: 1, V1Unit: "W",
+			V2Unit: unitWatts, V2Type: typeGauge},
+		{V1Pattern: "npu_(container|pod|job)_chip_num", V2Name: "npu_${1}_chips", Factor: 1, V2Type: typeGauge},
+		{V1Pattern: "npu_(container|pod|job)_error_code_num", V2Name: "npu_${1}_error_codes", Factor: 1,
+			V2Type: typeGauge},
+
+		// container lifecycle
+		gaugeMapping("npu_chip_info_container_start_time", "npu_chip_container_start_time_seconds", 1, "s",
+			unitSeconds),
+		gaugeMapping("npu_chip_info_pod_assigned_seconds", "npu_chip_pod_assigned_seconds", 1, "s", unitSeconds),
+		counterMapping("npu_chip_info_assignment_changes", "npu_chip_assignment_changes_total"),
+		gaugeMapping("npu_chip_info_idle_allocated", "npu_chip_idle_allocated", 1, "", ""),
+	}
+
+	compiledSchemaV2 = compileSchemaMappings(schemaV2Mappings)
+)
+
+func gaugeMapping(v1Name, v2Name string, factor float64, v1Unit, v2Unit string) SchemaMapping {
+	return SchemaMapping{V1Pattern: v1Name, V2Name: v2Name, Factor: factor, V1Unit: v1Unit, V2Unit: v2Unit,
+		V2Type: typeGauge}
+}
+
+func ratioMapping(v1Name, v2Name string) SchemaMapping {
+	return gaugeMapping(v1Name, v2Name, percentToRate, "%", unitRatio)
+}
+
+func counterMapping(v1Name, v2Name string) SchemaMapping {
+	return SchemaMapping{V1Pattern: v1Name, V2Name: v2Name, Factor: 1, V2Type: typeCounter}
+}
+
+func linkCounterMapping(v1Pattern, v2Name, v2Help string) SchemaMapping {
+	mapping := counterMapping(v1Pattern, v2Name)
+	mapping.IndexLabel = "link"
+	mapping.V2Help = v2Help
+	return mapping
+}
+
+func compileSchemaMappings(mappings []SchemaMapping) []compiledMapping {
+	res := make([]compiledMapping, 0, len(mappings))
+	for _, mapping := range mappings {
+		res = append(res, compiledMapping{SchemaMapping: mapping,
+			reg: regexp.MustCompile("^(?:" + mapping.V1Pattern + ")$")})
+	}
+	return res
+}
+
+// SchemaV2Mappings the machine-readable v1 to v2 mapping table, the first matched mapping is used,
+// the v1 metrics not matched are exported in v2 unchanged
+func SchemaV2Mappings() []SchemaMapping {
+	res := make([]SchemaMapping, len(schemaV2Mappings))
+	copy(res, schemaV2Mappings)
+	return res
+}
+
+// ToSchemaV2 convert the v1 samples to the v2 schema, the samples not exported in v2 are removed
+func ToSchemaV2(samples []MetricSample) []MetricSample {
+	res := make([]MetricSample, 0, len(samples))
+	for _, sample := range samples {
+		if converted, ok := toSchemaV2(sample); ok {
+			res = append(res, converted)
+		}
+	}
+	return res
+}
+
+func toSchemaV2(sample MetricSample) (MetricSample, bool) {
+	for i := range compiledSchemaV2 {
+		mapping := &compiledSchemaV2[i]
+		match := mapping.reg.FindStringSubmatchIndex(sample.Name)
+		if match == nil {
+			continue
+		}
+		if mapping.V2Name == "" {
+			return MetricSample{}, false
+		}
+		v1Name := sample.Name
+		sample.Name = string(mapping.reg.ExpandString(nil, mapping.V2Name, v1Name, match))
+		sample.Value *= mapping.Factor
+		if mapping.V2Unit != "" {
+			sample.Unit = mapping.V2Unit
+		}
+		if mapping.V2Type == typeCounter {
+			sample.Type = CounterType
+		} else if mapping.V2Type == typeGauge {
+			sample.Type = GaugeType
+		}
+		if mapping.V2Help != "" {
+			sample.Help = mapping.V2Help
+		}
+		sample.Help = unitReg.ReplaceAllString(sample.Help, "unit is '"+sample.Unit+"'")
+		for from, to := range mapping.LabelRenames {
+			sample.renameLabel(from, to)
+		}
+		if mapping.IndexLabel != "" {
+			sample.appendIndexLabel(mapping.IndexLabel, v1Name, match)
+		}
+		return sample, true
+	}
+	return sample, true
+}
+
+func (s *MetricSample) appendIndexLabel(label, v1Name string, match []int) {
+	const groupStart, groupEnd = 2, 3
+	index := "0"
+	if len(match) > groupEnd && match[groupStart] >= 0 {
+		index = v1Name[match[groupStart]:match[groupEnd]]
+	}
+	names := make([]string, len(s.LabelNames), len(s.LabelNames)+1)
+	copy(names, s.LabelNames)
+	s.LabelNames = append(names, label)
+	values := make([]string, len(s.LabelValues), len(s.LabelValues)+1)
+	copy(values, s.LabelValues)
+	s.LabelValues = append(values, index)
+}
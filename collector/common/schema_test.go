@@ -0,0 +1,98 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package common for general collector
+package common
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	schemaMemoryMB = 2
+	schemaUtil     = 50
+)
+
+var (
+	// schemaGroupReg the groups referred in v2 name, such as ${1}
+	schemaGroupReg = regexp.MustCompile(`\$\{\d+\}`)
+
+	schemaMemoryDesc = BuildDesc("npu_chip_info_hbm_used_memory", "the npu hbm used memory, unit is 'MB'")
+	schemaUtilDesc   = BuildDesc("npu_chip_info_utilization", "the ai core utilization")
+	schemaHccsDesc   = BuildCounterDesc("npu_chip_info_hccs_statistic_info_tx_cnt_3", "hccs tx count")
+	schemaErrorDesc  = BuildDesc("npu_chip_info_error_code", "the npu error code")
+	schemaOtherDesc  = BuildDesc("test_schema_unmapped", "the unmapped metric")
+)
+
+func buildSchemaSample(t *testing.T, desc *prometheus.Desc, value float64) MetricSample {
+	sample, err := NewSample(desc, value, time.Time{}, make([]string, len(CardLabel)))
+	if err != nil {
+		t.Fatalf("build sample failed: %v", err)
+	}
+	return sample
+}
+
+// TestSchemaV2Mappings test every v2 name of the mapping table is valid
+func TestSchemaV2Mappings(t *testing.T) {
+	convey.Convey("TestSchemaV2Mappings", t, func() {
+		for _, mapping := range SchemaV2Mappings() {
+			if mapping.V2Name == "" {
+				continue
+			}
+			// the referred groups are replaced by a valid name
+			name := schemaGroupReg.ReplaceAllString(mapping.V2Name, "x")
+			convey.So(model.IsValidMetricName(model.LabelValue(name)), convey.ShouldBeTrue)
+			convey.So(mapping.Factor, convey.ShouldNotEqual, 0)
+		}
+	})
+}
+
+// TestToSchemaV2 test converting v1 samples to the v2 schema
+func TestToSchemaV2(t *testing.T) {
+	convey.Convey("TestToSchemaV2", t, func() {
+		samples := ToSchemaV2([]MetricSample{
+			buildSchemaSample(t, schemaMemoryDesc, schemaMemoryMB),
+			buildSchemaSample(t, schemaUtilDesc, schemaUtil),
+			buildSchemaSample(t, schemaHccsDesc, 1),
+			buildSchemaSample(t, schemaErrorDesc, 1),
+			buildSchemaSample(t, schemaOtherDesc, 1),
+			{Name: "npu_chip_info_hccs_statistic_info_tx_cnt_3" + RateSuffix},
+		})
+		convey.So(len(samples), convey.ShouldEqual, 5)
+
+		convey.So(samples[0].Name, convey.ShouldEqual, "npu_chip_hbm_used_bytes")
+		convey.So(samples[0].Value, convey.ShouldEqual, schemaMemoryMB*mbToBytes)
+		convey.So(samples[0].Help, convey.ShouldEqual, "the npu hbm used memory, unit is 'bytes'")
+
+		convey.So(samples[1].Name, convey.ShouldEqual, "npu_chip_aicore_utilization_ratio")
+		convey.So(samples[1].Value, convey.ShouldEqual, 0.5)
+
+		convey.So(samples[2].Name, convey.ShouldEqual, "npu_chip_hccs_transmitted_messages_total")
+		convey.So(samples[2].Type, convey.ShouldEqual, CounterType)
+		convey.So(samples[2].Label("link"), convey.ShouldEqual, "3")
+
+		convey.So(samples[3].Name, convey.ShouldEqual, "npu_chip_error_code")
+		convey.So(samples[3].Label("index"), convey.ShouldEqual, "0")
+
+		convey.So(samples[4].Name, convey.ShouldEqual, "test_schema_unmapped")
+		_, err := ToPrometheusMetric(samples[2])
+		convey.So(err, convey.ShouldBeNil)
+	})
+}
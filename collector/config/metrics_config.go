@@ -16,85 +16,142 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
+	"sync"
 
 	"github.com/professorshandian/npu-exporter/collector/common"
 	"github.com/professorshandian/npu-exporter/collector/metrics"
 	"github.com/professorshandian/npu-exporter/utils/logger"
 )
 
+// GroupMode the way the collector of metrics group is scheduled
+type GroupMode int
+
+const (
+	// ModeSingle the collector collects all chips in a single goroutine
+	ModeSingle GroupMode = iota
+	// ModePerChip the collector collects every chip in its own goroutine
+	ModePerChip
+)
+
+type metricsGroupInfo struct {
+	name      string
+	collector common.MetricsCollector
+	mode      GroupMode
+}
+
 var (
+	groupLock sync.Mutex
+	// groups registered metrics groups, in the order of registration
+	groups []metricsGroupInfo
+	// groupStates enablement of metrics groups by config, groups not configured are on
+	groupStates = map[string]bool{}
+)
 
-	// singleGoroutineMap metrics in this map will be collected in single goroutine
-	singleGoroutineMap = map[string]common.MetricsCollector{
-		groupHccs:    &metrics.HccsCollector{},
-		groupNpu:     &metrics.BaseInfoCollector{},
-		groupSio:     &metrics.SioCollector{},
-		groupVersion: &metrics.VersionCollector{},
-		groupHbm:     &metrics.HbmCollector{},
-		groupDDR:     &metrics.DdrCollector{},
-		groupVnpu:    &metrics.VnpuCollector{},
-		groupPcie:    &metrics.PcieCollector{},
+const (
+	groupDDR       = "ddr"
+	groupHccs      = "hccs"
+	groupNpu       = "npu"
+	groupNetwork   = "network"
+	groupPcie      = "pcie"
+	groupRoce      = "roce"
+	groupSio       = "sio"
+	groupVnpu      = "vnpu"
+	groupVersion   = "version"
+	groupOptical   = "optical"
+	groupHbm       = "hbm"
+	groupSampler   = "sampler"
+	groupAggregate = "aggregate"
+	groupLifecycle = "lifecycle"
+)
+
+func init() {
+	builtinGroups := []metricsGroupInfo{
+		{name: groupDDR, collector: &metrics.DdrCollector{}, mode: ModeSingle},
+		{name: groupHccs, collector: &metrics.HccsCollector{}, mode: ModeSingle},
+		{name: groupNpu, collector: &metrics.BaseInfoCollector{}, mode: ModeSingle},
+		{name: groupNetwork, collector: &metrics.NetworkCollector{}, mode: ModePerChip},
+		{name: groupPcie, collector: &metrics.PcieCollector{}, mode: ModeSingle},
+		{name: groupRoce, collector: &metrics.RoceCollector{}, mode: ModePerChip},
+		{name: groupSio, collector: &metrics.SioCollector{}, mode: ModeSingle},
+		{name: groupVnpu, collector: &metrics.VnpuCollector{}, mode: ModeSingle},
+		{name: groupVersion, collector: &metrics.VersionCollector{}, mode: ModeSingle},
+		{name: groupOptical, collector: &metrics.OpticalCollector{}, mode: ModePerChip},
+		{name: groupHbm, collector: &metrics.HbmCollector{}, mode: ModeSingle},
+		{name: groupSampler, collector: &metrics.SamplerCollector{}, mode: ModeSingle},
+		{name: groupAggregate, collector: &metrics.AggregateCollector{}, mode: ModeSingle},
+		{name: groupLifecycle, collector: &metrics.LifecycleCollector{}, mode: ModeSingle},
 	}
-	// multiGoroutineMap metrics in this map will be collected in multi goroutine
-	multiGoroutineMap = map[string]common.MetricsCollector{
-		groupNetwork: &metrics.NetworkCollector{},
-		groupRoce:    &metrics.RoceCollector{},
-		groupOptical: &metrics.OpticalCollector{},
+	for _, group := range builtinGroups {
+		if err := RegisterGroup(group.name, group.collector, group.mode); err != nil {
+			panic(err)
+		}
 	}
-	configs = []map[string]string{
-		{metricsGroup: groupDDR, state: stateOn},
-		{metricsGroup: groupHccs, state: stateOn},
-		{metricsGroup: groupNpu, state: stateOn},
-		{metricsGroup: groupNetwork, state: stateOn},
-		{metricsGroup: groupPcie, state: stateOn},
-		{metricsGroup: groupRoce, state: stateOn},
-		{metricsGroup: groupSio, state: stateOn},
-		{metricsGroup: groupVnpu, state: stateOn},
-		{metricsGroup: groupVersion, state: stateOn},
-		{metricsGroup: groupOptical, state: stateOn},
-		{metricsGroup: groupHbm, state: stateOn},
+}
+
+// RegisterGroup register the collector of metrics group, must be called before Register.
+// The collector shares the chains, caches and output backends with the built-in collectors
+func RegisterGroup(name string, collector common.MetricsCollector, mode GroupMode) error {
+	if name == "" {
+		return errors.New("metrics group name is empty")
 	}
-)
+	if mode != ModeSingle && mode != ModePerChip {
+		return fmt.Errorf("mode %d of metrics group [%s] is invalid", mode, name)
+	}
+	cacheKey := common.GetCacheKey(collector)
+	if cacheKey == "" {
+		return fmt.Errorf("collector of metrics group [%s] must be a non-nil pointer of struct", name)
+	}
+	groupLock.Lock()
+	defer groupLock.Unlock()
+	for _, group := range groups {
+		if group.name == name {
+			return fmt.Errorf("metrics group [%s] is already registered", name)
+		}
+		// the cache of collector is keyed by its type, so one type can only be registered once
+		if common.GetCacheKey(group.collector) == cacheKey {
+			return fmt.Errorf("collector %s of metrics group [%s] is already registered by group [%s]",
+				cacheKey, name, group.name)
+		}
+	}
+	groups = append(groups, metricsGroupInfo{name: name, collector: collector, mode: mode})
+	return nil
+}
 
-const (
-	metricsGroup = "metricsGroup"
-	state        = "state"
-
-	groupDDR     = "ddr"
-	groupHccs    = "hccs"
-	groupNpu     = "npu"
-	groupNetwork = "network"
-	groupPcie    = "pcie"
-	groupRoce    = "roce"
-	groupSio     = "sio"
-	groupVnpu    = "vnpu"
-	groupVersion = "version"
-	groupOptical = "optical"
-	groupHbm     = "hbm"
-
-	stateOn  = "ON"
-	stateOFF = "OFF"
-)
+// SetGroupStates turn metrics groups on or off by name, groups not configured are on
+func SetGroupStates(states map[string]bool) {
+	groupLock.Lock()
+	defer groupLock.Unlock()
+	for name, on := range states {
+		groupStates[name] = on
+	}
+}
 
 // Register register collector to cache
 func Register(n *common.NpuCollector) {
-
-	for _, config := range configs {
-		metricsGroupName := config[metricsGroup]
-
-		if config[state] != stateOn {
-			logger.Infof("metricsGroup [%v] is off", metricsGroupName)
+	groupLock.Lock()
+	defer groupLock.Unlock()
+	registered := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		registered[group.name] = true
+		if on, ok := groupStates[group.name]; ok && !on {
+			logger.Infof("metricsGroup [%v] is off", group.name)
 			continue
 		}
-		collector, exist := singleGoroutineMap[metricsGroupName]
-		if exist && collector.IsSupported(n) {
-			common.ChainForSingleGoroutine = append(common.ChainForSingleGoroutine, collector)
+		if !group.collector.IsSupported(n) {
+			continue
 		}
-
-		collector, exist = multiGoroutineMap[metricsGroupName]
-		if exist && collector.IsSupported(n) {
-			common.ChainForMultiGoroutine = append(common.ChainForMultiGoroutine, collector)
+		if group.mode == ModePerChip {
+			common.ChainForMultiGoroutine = append(common.ChainForMultiGoroutine, group.collector)
+		} else {
+			common.ChainForSingleGoroutine = append(common.ChainForSingleGoroutine, group.collector)
+		}
+	}
+	for name := range groupStates {
+		if !registered[name] {
+			logger.Warnf("metricsGroup [%v] in config is not registered", name)
 		}
 	}
 	logger.Debugf("ChainForSingleGoroutine:%#v", common.ChainForSingleGoroutine)
@@ -58,7 +58,7 @@ func TestRegister(t *testing.T) {
 		patches.ApplyMethodReturn(&metrics.NetworkCollector{}, "IsSupported", true)
 		patches.ApplyMethodReturn(&metrics.RoceCollector{}, "IsSupported", true)
 		patches.ApplyMethodReturn(&metrics.OpticalCollector{}, "IsSupported", true)
-		configs = append(configs, map[string]string{metricsGroup: "mockGroup", state: stateOFF})
+		SetGroupStates(map[string]bool{"mockGroup": false})
 
 		Register(n)
 		convey.Convey("Should add collectors to ChainForSingleGoroutine", func() {
@@ -70,6 +70,52 @@ func TestRegister(t *testing.T) {
 	})
 }
 
+type mockVendorCollector struct {
+	common.MetricsCollectorAdapter
+}
+
+type mockPerChipCollector struct {
+	common.MetricsCollectorAdapter
+}
+
+func TestRegisterGroup(t *testing.T) {
+	convey.Convey("TestRegisterGroup", t, func() {
+		originGroups, originStates := groups, groupStates
+		groups, groupStates = append([]metricsGroupInfo{}, originGroups...), map[string]bool{}
+		defer func() {
+			groups, groupStates = originGroups, originStates
+		}()
+		initChain()
+		defer initChain()
+
+		convey.Convey("invalid registration returns error", func() {
+			convey.So(RegisterGroup("", &mockVendorCollector{}, ModeSingle), convey.ShouldNotBeNil)
+			convey.So(RegisterGroup("vendor", nil, ModeSingle), convey.ShouldNotBeNil)
+			convey.So(RegisterGroup("vendor", (*mockVendorCollector)(nil), ModeSingle), convey.ShouldNotBeNil)
+			convey.So(RegisterGroup("vendor", &mockVendorCollector{}, GroupMode(-1)), convey.ShouldNotBeNil)
+		})
+		convey.Convey("duplicate registration returns error", func() {
+			convey.So(RegisterGroup(groupNpu, &mockVendorCollector{}, ModeSingle), convey.ShouldNotBeNil)
+			convey.So(RegisterGroup("vendor", &metrics.HbmCollector{}, ModeSingle), convey.ShouldNotBeNil)
+		})
+		convey.Convey("registered groups are added to the chain of their mode", func() {
+			convey.So(RegisterGroup("vendor", &mockVendorCollector{}, ModeSingle), convey.ShouldBeNil)
+			convey.So(RegisterGroup("vendorPerChip", &mockPerChipCollector{}, ModePerChip), convey.ShouldBeNil)
+			groups = groups[len(groups)-2:]
+			Register(&common.NpuCollector{})
+			convey.So(common.ChainForSingleGoroutine, convey.ShouldHaveLength, 1)
+			convey.So(common.ChainForMultiGoroutine, convey.ShouldHaveLength, 1)
+		})
+		convey.Convey("groups turned off by config are skipped", func() {
+			convey.So(RegisterGroup("vendor", &mockVendorCollector{}, ModeSingle), convey.ShouldBeNil)
+			groups = groups[len(groups)-1:]
+			SetGroupStates(map[string]bool{"vendor": false})
+			Register(&common.NpuCollector{})
+			convey.So(common.ChainForSingleGoroutine, convey.ShouldBeEmpty)
+		})
+	})
+}
+
 func TestUnRegister(t *testing.T) {
 	convey.Convey("TestUnRegister", t, func() {
 		// Initialize chains with some collectors
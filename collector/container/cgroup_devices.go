@@ -0,0 +1,223 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/utils"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	// DefaultCgroupRoot default mount point of cgroup
+	DefaultCgroupRoot = "/sys/fs/cgroup"
+
+	labelK8sPodUID      = "io.kubernetes.pod.uid"
+	cgroupV1Devices     = "devices"
+	cgroupDevicesList   = "devices.list"
+	cgroupProcs         = "cgroup.procs"
+	cgroupV2Controllers = "cgroup.controllers"
+	maxDevicesListBytes = 64 * 1024
+	maxCgroupProcsBytes = 64 * 1024
+	maxCgroupDepth      = 8
+	devicesEntryFields  = 3
+	devNumFields        = 2
+	allDevices          = "a"
+	anyDevNum           = "*"
+)
+
+var (
+	// podUIDReg the pod uid in kubepods cgroup, such as pod<uid> of cgroupfs driver or
+	// kubepods-besteffort-pod<uid with underscores>.slice of systemd driver
+	podUIDReg = regexp.MustCompile(
+		`pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})(\.slice)?$`)
+	davinciReg = regexp.MustCompile(`^davinci\d+$`)
+)
+
+// CgroupDevicesTool find the npu of containers by their cgroup, which is independent of container runtime,
+// only the id of container and uid of pod are available
+type CgroupDevicesTool struct {
+	// Root the mount point of cgroup, default is DefaultCgroupRoot
+	Root string
+}
+
+// GetDevices get the containers using npu by scanning the cgroup of containers. the entries of npu major in
+// devices.list are used for cgroup v1, the npu device nodes of container process are used for cgroup v2 whose
+// device controller is eBPF program
+func (tool *CgroupDevicesTool) GetDevices() ([]DevicesInfo, error) {
+	root := tool.Root
+	if root == "" {
+		root = DefaultCgroupRoot
+	}
+	majorIDs := npuMajor()
+	if len(majorIDs) == 0 {
+		return nil, errors.New("npu major id not found")
+	}
+	if _, err := os.Stat(filepath.Join(root, cgroupV2Controllers)); err == nil {
+		return scanCgroup(root, func(dir string) []int { return npuDevicesOfCgroupV2(dir, majorIDs) })
+	}
+	return scanCgroup(filepath.Join(root, cgroupV1Devices),
+		func(dir string) []int { return npuDevicesOfCgroupV1(dir, majorIDs) })
+}
+
+// scanCgroup walk the cgroup tree and get the npu of each container cgroup by devicesOf
+func scanCgroup(root string, devicesOf func(dir string) []int) ([]DevicesInfo, error) {
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+	infos := make(map[string]DevicesInfo)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil || strings.Count(rel, string(filepath.Separator)) >= maxCgroupDepth {
+			return filepath.SkipDir
+		}
+		id := containerIDFromPath(d.Name())
+		if id == "" {
+			return nil
+		}
+		if _, ok := infos[id]; !ok && len(infos) < maxContainers {
+			if devices := devicesOf(path); len(devices) > 0 {
+				infos[id] = DevicesInfo{ID: id, Devices: devices, Labels: podLabelsOfCgroup(rel)}
+			}
+		}
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]DevicesInfo, 0, len(infos))
+	for _, info := range infos {
+		res = append(res, info)
+	}
+	return res, nil
+}
+
+// npuDevicesOfCgroupV1 parse the entries like "c 236:0 rwm" in devices.list, the container allowed to access all
+// devices is privileged and skipped
+func npuDevicesOfCgroupV1(dir string, majorIDs []string) []int {
+	data, err := utils.ReadLimitBytes(filepath.Join(dir, cgroupDevicesList), maxDevicesListBytes)
+	if err != nil {
+		logger.Debugf("read devices list of cgroup failed: %v", err)
+		return nil
+	}
+	devIDs := make([]int, 0, sliceLen8)
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != devicesEntryFields {
+			continue
+		}
+		if fields[0] == allDevices {
+			return nil
+		}
+		nums := strings.Split(fields[1], ":")
+		if fields[0] != charDevice || len(nums) != devNumFields || !contains(majorIDs, nums[0]) ||
+			nums[1] == anyDevNum {
+			continue
+		}
+		if minor, err := strconv.Atoi(nums[1]); err == nil {
+			devIDs = append(devIDs, minor)
+		}
+	}
+	return sortedDevices(devIDs)
+}
+
+// npuDevicesOfCgroupV2 find the npu device nodes in the /dev of the first process in the cgroup
+func npuDevicesOfCgroupV2(dir string, majorIDs []string) []int {
+	pid := firstPidOfCgroup(dir)
+	if pid == "" {
+		return nil
+	}
+	devDir := filepath.Join(procRoot, pid, "root", "dev")
+	entries, err := os.ReadDir(devDir)
+	if err != nil {
+		logger.Debugf("read dev of process %s failed: %v", pid, err)
+		return nil
+	}
+	devIDs := make([]int, 0, sliceLen8)
+	for _, entry := range entries {
+		if !davinciReg.MatchString(entry.Name()) {
+			continue
+		}
+		major, minor, err := charDeviceNumber(filepath.Join(devDir, entry.Name()))
+		if err != nil || !contains(majorIDs, strconv.FormatInt(major, decimalBase)) {
+			continue
+		}
+		devIDs = append(devIDs, int(minor))
+	}
+	return sortedDevices(devIDs)
+}
+
+// firstPidOfCgroup get the first process in the cgroup or its direct children, such as libpod-<id>.scope/container
+func firstPidOfCgroup(dir string) string {
+	if pid := firstPid(filepath.Join(dir, cgroupProcs)); pid != "" {
+		return pid
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if pid := firstPid(filepath.Join(dir, entry.Name(), cgroupProcs)); pid != "" {
+			return pid
+		}
+	}
+	return ""
+}
+
+func firstPid(procsFile string) string {
+	data, err := utils.ReadLimitBytes(procsFile, maxCgroupProcsBytes)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// podLabelsOfCgroup get the uid of pod from the kubepods cgroup path, nil for the container not in pod
+func podLabelsOfCgroup(path string) map[string]string {
+	for _, segment := range strings.Split(filepath.ToSlash(path), "/") {
+		if matches := podUIDReg.FindStringSubmatch(segment); len(matches) > 1 {
+			return map[string]string{labelK8sPodUID: strings.ReplaceAll(matches[1], "_", "-")}
+		}
+	}
+	return nil
+}
+
+func sortedDevices(devIDs []int) []int {
+	if len(devIDs) == 0 {
+		return nil
+	}
+	sort.Ints(devIDs)
+	return devIDs
+}
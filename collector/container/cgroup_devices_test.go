@@ -0,0 +1,134 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	mockNpuMajor  = 236
+	mockPodUID    = "1a2b3c4d-0000-1111-2222-333344445555"
+	mockCgroupPid = "4321"
+)
+
+var (
+	mockPrivilegedID = strings.Repeat("cd", 32)
+)
+
+func writeCgroupFile(t *testing.T, path, content string) {
+	if err := os.MkdirAll(filepath.Dir(path), mockDirMode); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), mockCgroupMode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestCgroupDevicesV1 test finding the npu of containers by devices.list of cgroup v1
+func TestCgroupDevicesV1(t *testing.T) {
+	convey.Convey("TestCgroupDevicesV1", t, func() {
+		patches := gomonkey.ApplyFuncReturn(npuMajor, []string{"236"})
+		defer patches.Reset()
+		root := t.TempDir()
+		podDir := filepath.Join(root, cgroupV1Devices, "kubepods", "besteffort", "pod"+mockPodUID)
+		writeCgroupFile(t, filepath.Join(podDir, mockOwnerID, cgroupDevicesList),
+			"c 236:3 rwm\nc 236:0 rw\nc 1:3 rwm\nc 236:* rwm\n")
+		writeCgroupFile(t, filepath.Join(podDir, mockPrivilegedID, cgroupDevicesList), "a *:* rwm\n")
+		writeCgroupFile(t, filepath.Join(root, cgroupV1Devices, "system.slice", "sshd.service", cgroupDevicesList),
+			"c 236:1 rwm\n")
+
+		infos, err := (&CgroupDevicesTool{Root: root}).GetDevices()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(infos, convey.ShouldResemble, []DevicesInfo{{ID: mockOwnerID, Devices: []int{0, 3},
+			Labels: map[string]string{labelK8sPodUID: mockPodUID}}})
+	})
+}
+
+// TestCgroupDevicesV2 test finding the npu of containers by device nodes of process in cgroup v2
+func TestCgroupDevicesV2(t *testing.T) {
+	convey.Convey("TestCgroupDevicesV2", t, func() {
+		patches := gomonkey.ApplyFuncReturn(npuMajor, []string{"236"})
+		defer patches.Reset()
+		patches.ApplyFunc(charDeviceNumber, func(path string) (int64, int64, error) {
+			if filepath.Base(path) == "davinci2" {
+				return mockNpuMajor, 2, nil
+			}
+			return 0, 0, errors.New("not a char device")
+		})
+		originRoot := procRoot
+		procRoot = t.TempDir()
+		defer func() { procRoot = originRoot }()
+		root := t.TempDir()
+		writeCgroupFile(t, filepath.Join(root, cgroupV2Controllers), "cpu memory\n")
+		podDir := filepath.Join(root, "kubepods.slice", "kubepods-besteffort.slice",
+			"kubepods-besteffort-pod"+strings.ReplaceAll(mockPodUID, "-", "_")+".slice")
+		writeCgroupFile(t, filepath.Join(podDir, "cri-containerd-"+mockOwnerID+".scope", cgroupProcs),
+			mockCgroupPid+"\n")
+		devDir := filepath.Join(procRoot, mockCgroupPid, "root", "dev")
+		writeCgroupFile(t, filepath.Join(devDir, "davinci2"), "")
+		writeCgroupFile(t, filepath.Join(devDir, "davinci_manager"), "")
+		writeCgroupFile(t, filepath.Join(devDir, "null"), "")
+
+		infos, err := (&CgroupDevicesTool{Root: root}).GetDevices()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(infos, convey.ShouldResemble, []DevicesInfo{{ID: mockOwnerID, Devices: []int{2},
+			Labels: map[string]string{labelK8sPodUID: mockPodUID}}})
+	})
+}
+
+// TestParseByCgroup test the npu of containers are got from cgroup when the runtime is unreachable
+func TestParseByCgroup(t *testing.T) {
+	convey.Convey("TestParseByCgroup", t, func() {
+		cgroupInfo := DevicesInfo{ID: mockOwnerID, Devices: []int{0}}
+		patches := gomonkey.ApplyMethodReturn(&CgroupDevicesTool{}, "GetDevices", []DevicesInfo{cgroupInfo}, nil)
+		defer patches.Reset()
+		runtimeErr := errors.New("connection refused")
+
+		convey.Convey("the runtime is unreachable on init", func() {
+			parser := MakeDevicesParser(CntNpuMonitorOpts{EndpointType: EndpointTypeContainerd, CgroupFallback: true})
+			parser.RuntimeOperator = &fakeRuntimeOperator{err: runtimeErr}
+			convey.So(parser.Init(), convey.ShouldBeNil)
+			defer parser.Close()
+			resultOut := make(chan DevicesInfos, 1)
+			parser.FetchAndParse(resultOut)
+			convey.So(<-resultOut, convey.ShouldResemble, DevicesInfos{mockOwnerID: cgroupInfo})
+			convey.So(<-parser.RecvResult(), convey.ShouldResemble, DevicesInfos{mockOwnerID: cgroupInfo})
+		})
+		convey.Convey("the runtime becomes unreachable after init", func() {
+			operator := &fakeRuntimeOperator{}
+			parser := MakeDevicesParser(CntNpuMonitorOpts{EndpointType: EndpointTypeContainerd, CgroupFallback: true})
+			parser.RuntimeOperator = operator
+			convey.So(parser.Init(), convey.ShouldBeNil)
+			defer parser.Close()
+			operator.err = runtimeErr
+			parser.FetchAndParse(nil)
+			convey.So(<-parser.RecvResult(), convey.ShouldResemble, DevicesInfos{mockOwnerID: cgroupInfo})
+		})
+		convey.Convey("error when fallback is disabled", func() {
+			parser := MakeDevicesParser(CntNpuMonitorOpts{EndpointType: EndpointTypeContainerd})
+			parser.RuntimeOperator = &fakeRuntimeOperator{err: runtimeErr}
+			convey.So(parser.Init(), convey.ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,225 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	// DefaultResyncInterval the default interval of parsing all containers when the events are watched
+	DefaultResyncInterval = 5 * time.Minute
+	eventRetryInterval    = 5 * time.Second
+	// maxPendingAttempts the times a started container is looked up before it is regarded as not managed by the
+	// runtime, the runtime may list a container later than its start event
+	maxPendingAttempts = 3
+)
+
+// containerIndex the persistent index of the containers using npu, it is kept up to date by the events of runtime,
+// so that only the started containers are inspected. all containers are parsed again when the events are not
+// watched, or the resync interval is reached
+type containerIndex struct {
+	mu sync.Mutex
+	// infos the containers using npu, keyed by container id
+	infos DevicesInfos
+	// pending the started containers not inspected yet, with the times they are not listed by the runtime
+	pending map[string]int
+	// generation increased by every event, the result of a parse only overrides the events before it began
+	generation uint64
+	// removed the generation at which the containers are stopped, kept while an earlier parse is running
+	removed map[string]uint64
+	// parsing the number of running parses by the generation they began at
+	parsing map[uint64]int
+	// watching the events are being watched
+	watching bool
+	// synced all containers are parsed since the events are watched
+	synced   bool
+	lastSync time.Time
+}
+
+func newContainerIndex() *containerIndex {
+	return &containerIndex{infos: make(DevicesInfos), pending: make(map[string]int),
+		removed: make(map[string]uint64), parsing: make(map[uint64]int)}
+}
+
+// parseRound a parse of containers begun at the generation of the index
+type parseRound struct {
+	full       bool
+	pending    map[string]int
+	generation uint64
+}
+
+func (idx *containerIndex) onEvent(event ContainerEvent) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.generation++
+	switch event.Type {
+	case ContainerStarted:
+		idx.pending[event.ID] = 0
+		delete(idx.removed, event.ID)
+	case ContainerStopped:
+		delete(idx.pending, event.ID)
+		delete(idx.infos, event.ID)
+		if len(idx.parsing) > 0 {
+			idx.removed[event.ID] = idx.generation
+		}
+	default:
+	}
+}
+
+// stale the container is stopped after the round began, so the result of the round is out of date for it
+func (idx *containerIndex) stale(round *parseRound, id string) bool {
+	return idx.removed[id] > round.generation
+}
+
+// setWatching a resync is needed when the watching begins or ends, since the events may be missed
+func (idx *containerIndex) setWatching(watching bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.watching = watching
+	idx.synced = false
+}
+
+// invalidate parse all containers next time
+func (idx *containerIndex) invalidate() {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.synced = false
+}
+
+// beginParse decide whether all containers should be parsed, and take the started containers to inspect. the
+// round must be ended by endParse
+func (idx *containerIndex) beginParse(now time.Time, resyncInterval time.Duration) *parseRound {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	round := &parseRound{pending: idx.pending, generation: idx.generation}
+	idx.pending = make(map[string]int)
+	idx.parsing[round.generation]++
+	round.full = !idx.watching || !idx.synced || now.Sub(idx.lastSync) >= resyncInterval
+	return round
+}
+
+// endParse forget the stopped containers no running parse began before
+func (idx *containerIndex) endParse(round *parseRound) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.parsing[round.generation]--; idx.parsing[round.generation] <= 0 {
+		delete(idx.parsing, round.generation)
+	}
+	for id, generation := range idx.removed {
+		needed := false
+		for begun := range idx.parsing {
+			if begun < generation {
+				needed = true
+				break
+			}
+		}
+		if !needed {
+			delete(idx.removed, id)
+		}
+	}
+}
+
+// requeue the started containers of the round not listed by the runtime yet, they are inspected next time
+func (idx *containerIndex) requeue(round *parseRound, containers []*CommonContainer) {
+	listed := make(map[string]bool, len(containers))
+	for _, c := range containers {
+		if c != nil {
+			listed[c.Id] = true
+		}
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for id, attempts := range round.pending {
+		if listed[id] || idx.stale(round, id) {
+			continue
+		}
+		if _, ok := idx.pending[id]; ok {
+			continue
+		}
+		if attempts+1 >= maxPendingAttempts {
+			logger.Debugf("started container %s is not listed by runtime, it is not inspected", id)
+			continue
+		}
+		idx.pending[id] = attempts + 1
+	}
+}
+
+// replace the index by the result of parsing all containers, except the containers stopped since the round began
+func (idx *containerIndex) replace(round *parseRound, infos DevicesInfos, now time.Time) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.infos = make(DevicesInfos, len(infos))
+	for id, info := range infos {
+		if !idx.stale(round, id) {
+			idx.infos[id] = info
+		}
+	}
+	idx.synced = idx.watching
+	idx.lastSync = now
+}
+
+// merge the result of inspecting the started containers, except the containers stopped since the round began
+func (idx *containerIndex) merge(round *parseRound, infos DevicesInfos) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for id, info := range infos {
+		if !idx.stale(round, id) {
+			idx.infos[id] = info
+		}
+	}
+}
+
+func (idx *containerIndex) snapshot() DevicesInfos {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	res := make(DevicesInfos, len(idx.infos))
+	for id, info := range idx.infos {
+		res[id] = info
+	}
+	return res
+}
+
+// watchEvents keep watching the events of runtime until ctx is done, it is retried when the subscription is broken
+func (dp *DevicesParser) watchEvents(ctx context.Context, watcher ContainerEventWatcher) {
+	for {
+		dp.index.setWatching(true)
+		err := watcher.WatchContainerEvents(ctx, dp.index.onEvent)
+		dp.index.setWatching(false)
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, ErrEventsNotSupported) || status.Code(err) == codes.Unimplemented {
+			logger.Infof("container events are not watched, all containers are parsed every time: %v", err)
+			return
+		}
+		logger.Warnf("watch container events failed, all containers are parsed every time until it is "+
+			"recovered: %v", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventRetryInterval):
+		}
+	}
+}
@@ -0,0 +1,260 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	v1 "github.com/professorshandian/npu-exporter/collector/container/v1"
+)
+
+const waitWatching = 2 * time.Second
+
+// fakeEventsServer the containerd events server sending the given envelopes and then ending the stream
+type fakeEventsServer struct {
+	v1.UnimplementedEventsServer
+	envelopes []*v1.Envelope
+	filters   []string
+}
+
+func (s *fakeEventsServer) Subscribe(req *v1.SubscribeRequest, stream v1.Events_SubscribeServer) error {
+	s.filters = req.Filters
+	for _, envelope := range s.envelopes {
+		if err := stream.Send(envelope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newEnvelope(t *testing.T, namespace, topic string, event proto.Message) *v1.Envelope {
+	data, err := proto.Marshal(event)
+	if err != nil {
+		t.Fatalf("marshal event failed: %v", err)
+	}
+	return &v1.Envelope{Namespace: namespace, Topic: topic, Event: &any.Any{Value: data}}
+}
+
+// TestWatchContainerdEvents test watching the task and container events of containerd
+func TestWatchContainerdEvents(t *testing.T) {
+	server := &fakeEventsServer{envelopes: []*v1.Envelope{
+		newEnvelope(t, namespaceK8s, v1.TopicTaskStart, &v1.TaskStart{ContainerId: "c1", Pid: 1}),
+		newEnvelope(t, namespaceMoby, v1.TopicTaskStart, &v1.TaskStart{ContainerId: "other"}),
+		newEnvelope(t, namespaceK8s, v1.TopicTaskExit, &v1.TaskExit{ContainerId: "c1", Id: "exec-1"}),
+		newEnvelope(t, namespaceK8s, v1.TopicTaskExit, &v1.TaskExit{ContainerId: "c1", Id: "c1"}),
+		newEnvelope(t, namespaceK8s, v1.TopicContainerDelete, &v1.ContainerDelete{Id: "c2"}),
+	}}
+	endpoint := startFakeCriServer(t, func(s *grpc.Server) {
+		v1.RegisterEventsServer(s, server)
+	})
+	convey.Convey("TestWatchContainerdEvents", t, func() {
+		conn, err := GetConnection(endpoint)
+		convey.So(err, convey.ShouldBeNil)
+		defer conn.Close()
+		operator := &RuntimeOperatorTool{conn: conn, client: v1.NewContainersClient(conn), Namespace: namespaceK8s}
+		var events []ContainerEvent
+		err = operator.WatchContainerEvents(context.Background(), func(event ContainerEvent) {
+			events = append(events, event)
+		})
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(server.filters, convey.ShouldHaveLength, len(containerdEventFilters([]string{namespaceK8s})))
+		convey.So(events, convey.ShouldResemble, []ContainerEvent{
+			{ID: "c1", Type: ContainerStarted},
+			{ID: "c1", Type: ContainerStopped},
+			{ID: "c2", Type: ContainerStopped},
+		})
+	})
+}
+
+// TestWatchCriEventsOptIn test the events of CRI are not watched unless CriEvents is set
+func TestWatchCriEventsOptIn(t *testing.T) {
+	convey.Convey("TestWatchCriEventsOptIn", t, func() {
+		operator := &RuntimeOperatorTool{criClient: criv1.NewRuntimeServiceClient(nil)}
+		err := operator.WatchContainerEvents(context.Background(), func(ContainerEvent) {})
+		convey.So(err, convey.ShouldEqual, ErrEventsNotSupported)
+	})
+}
+
+// eventRuntimeOperator the runtime giving events from channel and counting the inspection of containers
+type eventRuntimeOperator struct {
+	fakeRuntimeOperator
+	mu        sync.Mutex
+	inspected int32
+	events    chan ContainerEvent
+	handled   chan struct{}
+}
+
+func (f *eventRuntimeOperator) GetContainers(context.Context) ([]*CommonContainer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*CommonContainer{}, f.containers...), nil
+}
+
+func (f *eventRuntimeOperator) addContainer(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.containers = append(f.containers, &CommonContainer{Id: id, Labels: map[string]string{
+		labelK8sPodNamespace: mockNamespace, labelK8sPodName: mockPodName, labelContainerName: id}})
+}
+
+func (f *eventRuntimeOperator) GetContainerInfoByID(_ context.Context, id string) (v1.Spec, error) {
+	atomic.AddInt32(&f.inspected, 1)
+	env := []string{ascendDeviceInfo + "=" + id[len(id)-1:]}
+	return v1.Spec{Process: &v1.Process{Env: env}, Linux: &v1.Linux{Resources: &v1.LinuxResources{}}}, nil
+}
+
+func (f *eventRuntimeOperator) WatchContainerEvents(ctx context.Context, handle func(ContainerEvent)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event := <-f.events:
+			handle(event)
+			f.handled <- struct{}{}
+		}
+	}
+}
+
+func (f *eventRuntimeOperator) send(event ContainerEvent) {
+	f.events <- event
+	<-f.handled
+}
+
+func parseOnce(parser *DevicesParser) DevicesInfos {
+	parser.FetchAndParse(nil)
+	select {
+	case result := <-parser.RecvResult():
+		return result
+	case err := <-parser.RecvErr():
+		convey.So(err, convey.ShouldBeNil)
+	}
+	return nil
+}
+
+func waitForWatching(parser *DevicesParser) {
+	deadline := time.Now().Add(waitWatching)
+	for time.Now().Before(deadline) {
+		parser.index.mu.Lock()
+		watching := parser.index.watching
+		parser.index.mu.Unlock()
+		if watching {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestParseByEvents test only the started containers are inspected between resyncs
+func TestParseByEvents(t *testing.T) {
+	convey.Convey("TestParseByEvents", t, func() {
+		operator := &eventRuntimeOperator{events: make(chan ContainerEvent), handled: make(chan struct{})}
+		operator.addContainer("ctr-0")
+		operator.addContainer("ctr-1")
+		parser := &DevicesParser{RuntimeOperator: operator}
+		convey.So(parser.Init(), convey.ShouldBeNil)
+		defer parser.Close()
+		waitForWatching(parser)
+
+		result := parseOnce(parser)
+		convey.So(result, convey.ShouldHaveLength, 2)
+		convey.So(atomic.LoadInt32(&operator.inspected), convey.ShouldEqual, 2)
+
+		convey.So(parseOnce(parser), convey.ShouldResemble, result)
+		convey.So(atomic.LoadInt32(&operator.inspected), convey.ShouldEqual, 2)
+
+		operator.addContainer("ctr-2")
+		operator.send(ContainerEvent{ID: "ctr-2", Type: ContainerStarted})
+		result = parseOnce(parser)
+		convey.So(result, convey.ShouldHaveLength, 3)
+		convey.So(result["ctr-2"].Devices, convey.ShouldResemble, []int{2})
+		convey.So(atomic.LoadInt32(&operator.inspected), convey.ShouldEqual, 3)
+
+		operator.send(ContainerEvent{ID: "ctr-0", Type: ContainerStopped})
+		result = parseOnce(parser)
+		convey.So(result, convey.ShouldHaveLength, 2)
+		convey.So(atomic.LoadInt32(&operator.inspected), convey.ShouldEqual, 3)
+
+		parser.ResyncInterval = time.Nanosecond
+		convey.So(parseOnce(parser), convey.ShouldHaveLength, 3)
+		convey.So(atomic.LoadInt32(&operator.inspected), convey.ShouldEqual, 6)
+	})
+}
+
+// TestIndexStaleResult test the result of a parse does not override the events happened during it
+func TestIndexStaleResult(t *testing.T) {
+	convey.Convey("TestIndexStaleResult", t, func() {
+		idx := newContainerIndex()
+		idx.setWatching(true)
+		infos := DevicesInfos{"c1": {ID: "c1", Devices: []int{1}}, "c2": {ID: "c2", Devices: []int{2}}}
+
+		convey.Convey("containers stopped during a full parse are not restored", func() {
+			round := idx.beginParse(time.Now(), DefaultResyncInterval)
+			idx.onEvent(ContainerEvent{ID: "c1", Type: ContainerStopped})
+			idx.replace(round, infos, time.Now())
+			idx.endParse(round)
+			convey.So(idx.snapshot(), convey.ShouldHaveLength, 1)
+			convey.So(idx.snapshot(), convey.ShouldContainKey, "c2")
+			convey.So(idx.removed, convey.ShouldBeEmpty)
+
+			next := idx.beginParse(time.Now(), time.Hour)
+			idx.replace(next, infos, time.Now())
+			idx.endParse(next)
+			convey.So(idx.snapshot(), convey.ShouldHaveLength, len(infos))
+		})
+		convey.Convey("containers stopped during an incremental parse are not merged", func() {
+			idx.replace(&parseRound{}, DevicesInfos{}, time.Now())
+			idx.onEvent(ContainerEvent{ID: "c1", Type: ContainerStarted})
+			round := idx.beginParse(time.Now(), time.Hour)
+			convey.So(round.full, convey.ShouldBeFalse)
+			idx.onEvent(ContainerEvent{ID: "c1", Type: ContainerStopped})
+			idx.merge(round, infos)
+			idx.endParse(round)
+			convey.So(idx.snapshot(), convey.ShouldNotContainKey, "c1")
+		})
+	})
+}
+
+// TestIndexRequeue test the started containers not listed yet are looked up again for limited times
+func TestIndexRequeue(t *testing.T) {
+	convey.Convey("TestIndexRequeue", t, func() {
+		idx := newContainerIndex()
+		idx.setWatching(true)
+		idx.replace(&parseRound{}, DevicesInfos{}, time.Now())
+		idx.onEvent(ContainerEvent{ID: "c1", Type: ContainerStarted})
+		idx.onEvent(ContainerEvent{ID: "c2", Type: ContainerStarted})
+		for attempt := 1; attempt < maxPendingAttempts; attempt++ {
+			round := idx.beginParse(time.Now(), time.Hour)
+			convey.So(round.pending, convey.ShouldContainKey, "c1")
+			idx.requeue(round, []*CommonContainer{{Id: "c2"}})
+			idx.endParse(round)
+			convey.So(idx.pending, convey.ShouldResemble, map[string]int{"c1": attempt})
+		}
+		round := idx.beginParse(time.Now(), time.Hour)
+		idx.requeue(round, nil)
+		idx.endParse(round)
+		convey.So(idx.pending, convey.ShouldBeEmpty)
+	})
+}
@@ -58,6 +58,13 @@ const (
 	EndpointTypeDockerd
 	// EndpointTypeIsula K8S + isula
 	EndpointTypeIsula = 2
+	// EndpointTypeCrio K8S + CRI-O, the OCI spec is got from the verbose container status of CRI
+	EndpointTypeCrio = 3
+	// EndpointTypePodman podman, the containers and OCI spec are got from podman REST API
+	EndpointTypePodman = 4
+	// EndpointTypeDockerEngine docker without containerd socket or CRI, the containers and OCI spec are got from
+	// docker engine API
+	EndpointTypeDockerEngine = 5
 )
 
 var (
@@ -86,25 +93,36 @@ var (
 
 // CntNpuMonitorOpts contains setting options for monitoring containers
 type CntNpuMonitorOpts struct {
-	CriEndpoint  string // CRI server address
-	EndpointType int    // containerd or docker
-	OciEndpoint  string // OCI server, now is containerd address
+	CriEndpoint  string // CRI server address, or podman or docker engine API address
+	EndpointType int    // containerd, docker, isula, crio, podman or docker engine
+	OciEndpoint  string // OCI server, now is containerd address, not used by crio, podman and docker engine
 	UserBackUp   bool   // whether try to use backup address
+	// PodResourcesEndpoint kubelet pod-resources server address, the npu of containers are got from it when set
+	PodResourcesEndpoint string
+	// CgroupFallback whether find the npu of containers by their cgroup when the runtime is unreachable
+	CgroupFallback bool
+	// ContainerdNamespaces the other containerd namespaces scanned besides the namespace of CRI, AllNamespaces
+	// discovers all of them, only used by containerd and docker
+	ContainerdNamespaces []string
+	// CriEvents whether watch the container events of CRI, only used by containerd, docker, isula and crio
+	CriEvents bool
 }
 
 // MakeDevicesParser evaluates option settings and make an instance according to it
 func MakeDevicesParser(opts CntNpuMonitorOpts) *DevicesParser {
-	runtimeOperator := &RuntimeOperatorTool{UseBackup: opts.UserBackUp}
+	runtimeOperator := &RuntimeOperatorTool{UseBackup: opts.UserBackUp, CriEvents: opts.CriEvents}
 	parser := &DevicesParser{}
 
 	switch opts.EndpointType {
 	case EndpointTypeContainerd:
 		runtimeOperator.Namespace = namespaceK8s
+		runtimeOperator.Namespaces = opts.ContainerdNamespaces
 		runtimeOperator.CriEndpoint = opts.CriEndpoint
 		runtimeOperator.OciEndpoint = opts.OciEndpoint
 		parser.RuntimeOperator = runtimeOperator
 	case EndpointTypeDockerd:
 		runtimeOperator.Namespace = namespaceMoby
+		runtimeOperator.Namespaces = opts.ContainerdNamespaces
 		parser.RuntimeOperator = runtimeOperator
 		runtimeOperator.CriEndpoint = opts.CriEndpoint
 		runtimeOperator.OciEndpoint = opts.OciEndpoint
@@ -113,9 +131,22 @@ func MakeDevicesParser(opts CntNpuMonitorOpts) *DevicesParser {
 		parser.RuntimeOperator = runtimeOperator
 		runtimeOperator.CriEndpoint = opts.CriEndpoint
 		runtimeOperator.OciEndpoint = opts.OciEndpoint
+	case EndpointTypeCrio:
+		parser.RuntimeOperator = &CrioOperatorTool{RuntimeOperatorTool: RuntimeOperatorTool{
+			UseBackup: opts.UserBackUp, CriEndpoint: opts.CriEndpoint, CriEvents: opts.CriEvents}}
+	case EndpointTypePodman:
+		parser.RuntimeOperator = &PodmanOperatorTool{Endpoint: opts.CriEndpoint}
+	case EndpointTypeDockerEngine:
+		parser.RuntimeOperator = &DockerEngineOperatorTool{Endpoint: opts.CriEndpoint}
 	default:
 		logger.Errorf("invalid type value %d", opts.EndpointType)
 	}
+	if opts.PodResourcesEndpoint != "" {
+		parser.PodResources = &PodResourcesTool{Endpoint: opts.PodResourcesEndpoint}
+	}
+	if opts.CgroupFallback {
+		parser.CgroupDevices = &CgroupDevicesTool{}
+	}
 
 	return parser
 }
@@ -127,6 +158,16 @@ type DevicesInfo struct {
 	// container name, the format is: PodNameSpace_PodName_ContainerName
 	Name    string
 	Devices []int
+	// Labels labels of the container given by the runtime, including the labels of its pod
+	Labels map[string]string
+	// Annotations annotations of the container given by the runtime, including the annotations of its pod
+	Annotations map[string]string
+	// VDevices the vNPU assigned to the container, whose vdev ids are also in Devices when they are known
+	VDevices []VDevice
+	// StartedAt the start time of container given by runtime, see CommonContainer, zero when unknown
+	StartedAt time.Time
+	// PodStartedAt the creation time of the pod sandbox of container, zero when unknown
+	PodStartedAt time.Time
 }
 
 // DevicesInfos the device information storage map
@@ -139,16 +180,51 @@ type DevicesParser struct {
 	err    chan error
 	// configuration
 	RuntimeOperator RuntimeOperator
-	Timeout         time.Duration
+	// PodResources the npu of containers are got from kubelet pod-resources API instead of container spec when set,
+	// the runtime is only used to get the id, labels and annotations of containers
+	PodResources *PodResourcesTool
+	// CgroupDevices the npu of containers are got from their cgroup when set and the runtime is unreachable,
+	// so that the exporter keeps running with the container id and pod uid only
+	CgroupDevices *CgroupDevicesTool
+	Timeout       time.Duration
+	// ResyncInterval the interval of parsing all containers when the events of runtime are watched,
+	// default is DefaultResyncInterval
+	ResyncInterval time.Duration
+
+	runtimeReady bool
+	index        *containerIndex
+	cancelWatch  context.CancelFunc
 }
 
 // Init initializes connection to containerd daemon and to CRI server or dockerd daemon based on name fetcher setting
 func (dp *DevicesParser) Init() error {
+	if dp.PodResources != nil {
+		if err := dp.PodResources.Init(); err != nil {
+			return contactError(err, "connecting to kubelet pod-resources failed")
+		}
+	}
 	if err := dp.RuntimeOperator.Init(); err != nil {
-		return contactError(err, "connecting to container runtime failed")
+		if dp.PodResources == nil && dp.CgroupDevices == nil {
+			return contactError(err, "connecting to container runtime failed")
+		}
+		if dp.PodResources != nil {
+			logger.Warnf("connecting to container runtime failed, the id, labels and annotations of containers "+
+				"are not available: %v", err)
+		} else {
+			logger.Warnf("connecting to container runtime failed, the npu of containers are got from cgroup: %v",
+				err)
+		}
+	} else {
+		dp.runtimeReady = true
 	}
 	dp.result = make(chan DevicesInfos, 1)
 	dp.err = make(chan error, 1)
+	dp.index = newContainerIndex()
+	if watcher, ok := dp.RuntimeOperator.(ContainerEventWatcher); ok && dp.runtimeReady && dp.PodResources == nil {
+		var ctx context.Context
+		ctx, dp.cancelWatch = context.WithCancel(context.Background())
+		go dp.watchEvents(ctx, watcher)
+	}
 	return nil
 }
 
@@ -164,6 +240,15 @@ func (dp *DevicesParser) RecvErr() <-chan error {
 
 // Close closes all connections and channels established during initializing
 func (dp *DevicesParser) Close() {
+	if dp.cancelWatch != nil {
+		dp.cancelWatch()
+	}
+	if dp.PodResources != nil {
+		_ = dp.PodResources.Close()
+	}
+	if !dp.runtimeReady {
+		return
+	}
 	_ = dp.RuntimeOperator.Close()
 }
 
@@ -199,15 +284,12 @@ func (dp *DevicesParser) parseDevicesInContainerd(ctx context.Context, c *Common
 	}
 
 	envs := spec.Process.Env
-	for i := len(envs) - 1; i >= 0; i-- {
-		e := envs[i]
-		if strings.Contains(e, ascendDeviceInfo) {
-			deviceInfo, err = dp.getDevicesWithAscendRuntime(e, c)
-			return err
-		}
+	if e, ok := findAscendDeviceEnv(envs); ok {
+		deviceInfo, err = dp.getDevicesWithAscendRuntime(e, c)
+	} else {
+		deviceInfo, err = dp.getDevicesWithoutAscendRuntime(spec, c)
 	}
-
-	deviceInfo, err = dp.getDevicesWithoutAscendRuntime(spec, c)
+	applyVisibleDevicesEnv(&deviceInfo, envs)
 	return err
 }
 
@@ -393,15 +475,12 @@ func (dp *DevicesParser) parseDeviceInIsula(ctx context.Context, c *CommonContai
 	}
 
 	envs := containerInfo.Config.Env
-	for i := len(envs) - 1; i >= 0; i-- {
-		e := envs[i]
-		if strings.Contains(e, ascendDeviceInfo) {
-			deviceInfo, err = dp.getDevicesWithAscendRuntime(e, c)
-			return err
-		}
+	if e, ok := findAscendDeviceEnv(envs); ok {
+		deviceInfo, err = dp.getDevicesWithAscendRuntime(e, c)
+	} else {
+		deviceInfo, err = dp.getDevWithoutAscendRuntimeInIsula(containerInfo, c)
 	}
-
-	deviceInfo, err = dp.getDevWithoutAscendRuntimeInIsula(containerInfo, c)
+	applyVisibleDevicesEnv(&deviceInfo, envs)
 	return err
 }
 
@@ -444,15 +523,39 @@ func (dp *DevicesParser) doParse(resultOut chan<- DevicesInfos) {
 	}(result)
 
 	ctx := context.Background()
+	now := time.Now()
+	round := dp.index.beginParse(now, withDefault(dp.ResyncInterval, DefaultResyncInterval))
+	defer dp.index.endParse(round)
+	if !round.full && len(round.pending) == 0 {
+		dp.result <- dp.index.snapshot()
+		return
+	}
 	containers, err := dp.RuntimeOperator.GetContainers(ctx)
+	// the runtime may list the started containers later than their events, they are looked up again next time
+	dp.index.requeue(round, containers)
 	if err != nil {
+		dp.index.invalidate()
+		if dp.CgroupDevices != nil {
+			logger.Warnf("get containers from runtime failed, the npu of containers are got from cgroup: %v", err)
+			dp.parseByCgroup()
+			return
+		}
 		dp.err <- err
 		return
 	}
+	if !round.full {
+		containers = filterContainers(containers, round.pending)
+		if len(containers) == 0 {
+			dp.result <- dp.index.snapshot()
+			return
+		}
+		logger.Debugf("inspect %d started containers", len(containers))
+	}
 
 	l := len(containers)
 	if l == 0 || l > maxContainers {
 		logger.Debugf("get %d containers from cri interface, return empty data", l)
+		dp.index.replace(round, make(DevicesInfos), now)
 		dp.result <- make(DevicesInfos)
 		return
 	}
@@ -478,11 +581,110 @@ func (dp *DevicesParser) doParse(resultOut chan<- DevicesInfos) {
 	}
 
 	if result != nil {
-		dp.result <- result
+		if round.full {
+			dp.index.replace(round, result, now)
+		} else {
+			dp.index.merge(round, result)
+		}
+		dp.result <- dp.index.snapshot()
+	} else {
+		dp.index.invalidate()
 	}
 	wg.Wait()
 }
 
+func filterContainers(containers []*CommonContainer, ids map[string]int) []*CommonContainer {
+	res := make([]*CommonContainer, 0, len(ids))
+	for _, c := range containers {
+		if c == nil {
+			continue
+		}
+		if _, ok := ids[c.Id]; ok {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// doParseByPodResources get the npu of containers from kubelet pod-resources API, and fill the id, labels and
+// annotations of containers by listing containers of runtime once
+func (dp *DevicesParser) doParseByPodResources(resultOut chan<- DevicesInfos) {
+	var result DevicesInfos
+	defer func() {
+		if resultOut != nil {
+			resultOut <- result
+			close(resultOut)
+		}
+	}()
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), withDefault(dp.Timeout, parsingNpuDefaultTimeout))
+	defer cancelFn()
+	infos, err := dp.PodResources.GetDevices(ctx)
+	if err != nil {
+		dp.err <- err
+		return
+	}
+	containers := dp.containersByName(ctx)
+	result = make(DevicesInfos, len(infos))
+	for _, info := range infos {
+		if c, ok := containers[info.Name]; ok {
+			info.ID = c.Id
+			info.Labels = c.Labels
+			info.Annotations = c.Annotations
+			info.StartedAt = c.StartedAt
+			info.PodStartedAt = c.PodStartedAt
+		}
+		key := info.ID
+		if key == "" {
+			key = info.Name
+		}
+		result[key] = info
+	}
+	dp.result <- result
+}
+
+// doParseByCgroup get the npu of containers from their cgroup when the runtime is unreachable
+func (dp *DevicesParser) doParseByCgroup(resultOut chan<- DevicesInfos) {
+	result := dp.parseByCgroup()
+	if resultOut != nil {
+		resultOut <- result
+		close(resultOut)
+	}
+}
+
+func (dp *DevicesParser) parseByCgroup() DevicesInfos {
+	infos, err := dp.CgroupDevices.GetDevices()
+	if err != nil {
+		dp.err <- contactError(err, "getting npu of containers from cgroup failed")
+		return nil
+	}
+	result := make(DevicesInfos, len(infos))
+	for _, info := range infos {
+		result[info.ID] = info
+	}
+	dp.result <- result
+	return result
+}
+
+// containersByName list the containers of runtime keyed by PodNameSpace_PodName_ContainerName
+func (dp *DevicesParser) containersByName(ctx context.Context) map[string]*CommonContainer {
+	res := make(map[string]*CommonContainer)
+	if !dp.runtimeReady {
+		return res
+	}
+	containers, err := dp.RuntimeOperator.GetContainers(ctx)
+	if err != nil {
+		logger.Warnf("get containers failed, the id, labels and annotations of containers are not available: %v",
+			err)
+		return res
+	}
+	for _, c := range containers {
+		name := c.Labels[labelK8sPodNamespace] + "_" + c.Labels[labelK8sPodName] + "_" + c.Labels[labelContainerName]
+		res[name] = c
+	}
+	return res
+}
+
 // FetchAndParse triggers the asynchronous process of querying and analyzing all containers
 // resultOut channel is for fetching the current result
 func (dp *DevicesParser) FetchAndParse(resultOut chan<- DevicesInfos) {
@@ -490,6 +692,14 @@ func (dp *DevicesParser) FetchAndParse(resultOut chan<- DevicesInfos) {
 		logger.Debug("device paster is not initialized")
 		return
 	}
+	if dp.PodResources != nil {
+		go dp.doParseByPodResources(resultOut)
+		return
+	}
+	if !dp.runtimeReady && dp.CgroupDevices != nil {
+		go dp.doParseByCgroup(resultOut)
+		return
+	}
 	go dp.doParse(resultOut)
 }
 
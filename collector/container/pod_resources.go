@@ -0,0 +1,172 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	podresourcesv1 "k8s.io/kubelet/pkg/apis/podresources/v1"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/api"
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/hwlog"
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/utils"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+// DefaultPodResourcesAddr default kubelet pod-resources sock address
+const DefaultPodResourcesAddr = "unix:///var/lib/kubelet/pod-resources/kubelet.sock"
+
+// PodResourcesTool queries the npu allocated to containers from the kubelet pod-resources API,
+// which is the authoritative mapping given by the device plugin
+type PodResourcesTool struct {
+	conn   *grpc.ClientConn
+	client podresourcesv1.PodResourcesListerClient
+	// Endpoint kubelet pod-resources server endpoint
+	Endpoint string
+}
+
+// Init initializes the connection to kubelet pod-resources server
+func (p *PodResourcesTool) Init() error {
+	return runAsRoot(func() error {
+		if _, err := utils.CheckPath(strings.TrimPrefix(p.Endpoint, unixPre)); err != nil {
+			hwlog.RunLog.Error("check socket path failed")
+			return err
+		}
+		conn, err := GetConnection(p.Endpoint)
+		if err != nil {
+			return fmt.Errorf("connecting to kubelet pod-resources server failed: %v", err)
+		}
+		p.conn = conn
+		p.client = podresourcesv1.NewPodResourcesListerClient(conn)
+		return nil
+	})
+}
+
+// Close closes the connection to kubelet pod-resources server
+func (p *PodResourcesTool) Close() error {
+	if p.conn == nil {
+		return nil
+	}
+	return p.conn.Close()
+}
+
+// GetDevices returns the npu allocated to containers, the ID, labels and annotations are not filled,
+// since pod-resources API only gives the names of pod and container
+func (p *PodResourcesTool) GetDevices(ctx context.Context) ([]DevicesInfo, error) {
+	if p.client == nil {
+		return nil, errors.New("pod-resources client is empty")
+	}
+	allocatable := p.getAllocatable(ctx)
+	resp, err := p.client.List(ctx, &podresourcesv1.ListPodResourcesRequest{})
+	if err != nil {
+		hwlog.RunLog.Error(err)
+		return nil, err
+	}
+	var infos []DevicesInfo
+	for _, pod := range resp.GetPodResources() {
+		for _, ctr := range pod.GetContainers() {
+			devices := npuOfContainer(ctr.GetDevices(), allocatable)
+			if len(devices) == 0 {
+				continue
+			}
+			names := []string{pod.GetNamespace(), pod.GetName(), ctr.GetName()}
+			valid := true
+			for _, name := range names {
+				valid = valid && validDNSRe(name) == nil
+			}
+			if !valid {
+				logger.Errorf("invalid name of container %v in pod-resources", names)
+				continue
+			}
+			infos = append(infos, DevicesInfo{Name: strings.Join(names, "_"), Devices: devices})
+		}
+	}
+	return infos, nil
+}
+
+// getAllocatable get the npu allocatable on node keyed by device id, nil when kubelet does not support it
+func (p *PodResourcesTool) getAllocatable(ctx context.Context) map[string]int {
+	resp, err := p.client.GetAllocatableResources(ctx, &podresourcesv1.AllocatableResourcesRequest{})
+	if err != nil {
+		logger.Debugf("get allocatable resources failed, device ids are not checked: %v", err)
+		return nil
+	}
+	allocatable := make(map[string]int, len(resp.GetDevices()))
+	for _, dev := range resp.GetDevices() {
+		if !strings.HasPrefix(dev.GetResourceName(), api.ResourceNamePrefix) {
+			continue
+		}
+		for _, id := range dev.GetDeviceIds() {
+			phyID, err := podResourceDeviceID(id)
+			if err != nil {
+				logger.Debugf("skip allocatable device %s: %v", id, err)
+				continue
+			}
+			allocatable[id] = phyID
+		}
+	}
+	return allocatable
+}
+
+// npuOfContainer map the device ids of npu resources to chips, the ids not allocatable on node are skipped
+func npuOfContainer(devices []*podresourcesv1.ContainerDevices, allocatable map[string]int) []int {
+	var res []int
+	for _, dev := range devices {
+		if !strings.HasPrefix(dev.GetResourceName(), api.ResourceNamePrefix) {
+			continue
+		}
+		for _, id := range dev.GetDeviceIds() {
+			if allocatable != nil {
+				phyID, ok := allocatable[id]
+				if !ok {
+					logger.Warnf("device %s of %s is not allocatable on node, skip it", id, dev.GetResourceName())
+					continue
+				}
+				res = append(res, phyID)
+				continue
+			}
+			phyID, err := podResourceDeviceID(id)
+			if err != nil {
+				logger.Warnf("skip device %s of %s: %v", id, dev.GetResourceName(), err)
+				continue
+			}
+			res = append(res, phyID)
+		}
+	}
+	return res
+}
+
+// podResourceDeviceID parse the id given by device plugin, such as Ascend910-0 or 0, to the id of chip
+func podResourceDeviceID(id string) (int, error) {
+	idStr := id
+	if ascendStyle(id) {
+		parts := strings.Split(id, minus)
+		if len(parts) != ascendEnvPart {
+			return -1, fmt.Errorf("unexpected device id (%s)", id)
+		}
+		idStr = parts[1]
+	}
+	phyID, err := strconv.Atoi(idStr)
+	if err != nil || phyID < 0 {
+		return -1, fmt.Errorf("unexpected device id (%s)", id)
+	}
+	return phyID, nil
+}
@@ -0,0 +1,161 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+	podresourcesv1 "k8s.io/kubelet/pkg/apis/podresources/v1"
+
+	"github.com/professorshandian/npu-exporter/collector/container/isula"
+	v1 "github.com/professorshandian/npu-exporter/collector/container/v1"
+)
+
+const (
+	mockNamespace    = "default"
+	mockNpuResource  = "huawei.com/Ascend910"
+	mockNpuContainer = "c1"
+)
+
+// fakePodResourcesServer the kubelet pod-resources server
+type fakePodResourcesServer struct {
+	podresourcesv1.UnimplementedPodResourcesListerServer
+	allocatable bool
+}
+
+func (s *fakePodResourcesServer) List(context.Context,
+	*podresourcesv1.ListPodResourcesRequest) (*podresourcesv1.ListPodResourcesResponse, error) {
+	return &podresourcesv1.ListPodResourcesResponse{PodResources: []*podresourcesv1.PodResources{{
+		Name:      mockPodName,
+		Namespace: mockNamespace,
+		Containers: []*podresourcesv1.ContainerResources{{
+			Name: mockNpuContainer,
+			Devices: []*podresourcesv1.ContainerDevices{
+				// the npu not allocatable on node is skipped when allocatable resources are supported
+				{ResourceName: mockNpuResource, DeviceIds: []string{"Ascend910-0", "Ascend910-1", "Ascend910-7"}},
+				{ResourceName: "nvidia.com/gpu", DeviceIds: []string{"2"}},
+			},
+		}, {
+			Name:    "sidecar",
+			Devices: []*podresourcesv1.ContainerDevices{{ResourceName: "example.com/foo", DeviceIds: []string{"3"}}},
+		}},
+	}}}, nil
+}
+
+func (s *fakePodResourcesServer) GetAllocatableResources(context.Context,
+	*podresourcesv1.AllocatableResourcesRequest) (*podresourcesv1.AllocatableResourcesResponse, error) {
+	if !s.allocatable {
+		return nil, errors.New("not supported")
+	}
+	return &podresourcesv1.AllocatableResourcesResponse{Devices: []*podresourcesv1.ContainerDevices{
+		{ResourceName: mockNpuResource, DeviceIds: []string{"Ascend910-0", "Ascend910-1", "Ascend910-2"}},
+	}}, nil
+}
+
+// fakeRuntimeOperator the runtime only used to list containers, err is returned when the runtime is unreachable
+type fakeRuntimeOperator struct {
+	containers []*CommonContainer
+	err        error
+}
+
+func (f *fakeRuntimeOperator) Init() error {
+	return f.err
+}
+
+func (f *fakeRuntimeOperator) Close() error {
+	return nil
+}
+
+func (f *fakeRuntimeOperator) GetContainers(context.Context) ([]*CommonContainer, error) {
+	return f.containers, f.err
+}
+
+func (f *fakeRuntimeOperator) GetContainerInfoByID(context.Context, string) (v1.Spec, error) {
+	return v1.Spec{}, errors.New("not expected")
+}
+
+func (f *fakeRuntimeOperator) GetIsulaContainerInfoByID(context.Context, string) (isula.ContainerJson, error) {
+	return isula.ContainerJson{}, errors.New("not expected")
+}
+
+func (f *fakeRuntimeOperator) GetContainerType() string {
+	return DefaultContainer
+}
+
+func newPodResourcesParser(t *testing.T, allocatable bool) *DevicesParser {
+	endpoint := startFakeCriServer(t, func(s *grpc.Server) {
+		podresourcesv1.RegisterPodResourcesListerServer(s, &fakePodResourcesServer{allocatable: allocatable})
+	})
+	parser := MakeDevicesParser(CntNpuMonitorOpts{EndpointType: EndpointTypeContainerd,
+		PodResourcesEndpoint: endpoint})
+	parser.RuntimeOperator = &fakeRuntimeOperator{containers: []*CommonContainer{{
+		Id: mockContainerID,
+		Labels: map[string]string{labelK8sPodNamespace: mockNamespace, labelK8sPodName: mockPodName,
+			labelContainerName: mockNpuContainer, mockJobKey: mockJobName},
+		Annotations: map[string]string{mockRankAnno: mockRankIndex},
+	}}}
+	return parser
+}
+
+// TestParseByPodResources test getting the npu of containers from kubelet pod-resources
+func TestParseByPodResources(t *testing.T) {
+	convey.Convey("TestParseByPodResources", t, func() {
+		convey.Convey("the npu are mapped to the containers listed by runtime", func() {
+			parser := newPodResourcesParser(t, true)
+			convey.So(parser.Init(), convey.ShouldBeNil)
+			defer parser.Close()
+			resultOut := make(chan DevicesInfos, 1)
+			parser.FetchAndParse(resultOut)
+			result := <-resultOut
+			convey.So(result, convey.ShouldHaveLength, 1)
+			info := result[mockContainerID]
+			convey.So(info.Name, convey.ShouldEqual, mockNamespace+"_"+mockPodName+"_"+mockNpuContainer)
+			convey.So(info.Devices, convey.ShouldResemble, []int{0, 1})
+			convey.So(info.Labels[mockJobKey], convey.ShouldEqual, mockJobName)
+			convey.So(info.Annotations[mockRankAnno], convey.ShouldEqual, mockRankIndex)
+			convey.So(<-parser.RecvResult(), convey.ShouldResemble, result)
+		})
+		convey.Convey("the device ids are parsed when allocatable resources are not supported", func() {
+			parser := newPodResourcesParser(t, false)
+			convey.So(parser.Init(), convey.ShouldBeNil)
+			defer parser.Close()
+			infos, err := parser.PodResources.GetDevices(context.Background())
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(infos, convey.ShouldHaveLength, 1)
+			convey.So(infos[0].Devices, convey.ShouldResemble, []int{0, 1, 7})
+		})
+	})
+}
+
+// TestPodResourceDeviceID test parsing the device id given by device plugin
+func TestPodResourceDeviceID(t *testing.T) {
+	convey.Convey("TestPodResourceDeviceID", t, func() {
+		id, err := podResourceDeviceID("Ascend910-3")
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(id, convey.ShouldEqual, 3)
+		id, err = podResourceDeviceID("5")
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(id, convey.ShouldEqual, 5)
+		_, err = podResourceDeviceID("Ascend910-2c-100-0")
+		convey.So(err, convey.ShouldNotBeNil)
+		_, err = podResourceDeviceID("-1")
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
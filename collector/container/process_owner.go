@@ -0,0 +1,229 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/utils"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	maxCgroupFileBytes = 64 * 1024
+	maxCommBytes       = 64
+	cgroupFieldNum     = 3
+	scopeSuffix        = ".scope"
+	decimalBase        = 10
+	maxStatusBytes     = 16 * 1024
+	nsPidField         = "NSpid:"
+	maxStatBytes       = 4 * 1024
+	maxProcStatBytes   = 1024 * 1024
+	bootTimeField      = "btime"
+	bootTimeFieldNum   = 2
+	// startTimeIdx the index of starttime in the fields of /proc/<pid>/stat following the command
+	startTimeIdx = 19
+	// userHz the clock ticks per second of the times in proc, which is fixed by the kernel ABI
+	userHz = 100
+)
+
+var (
+	// procRoot the mount point of proc of host, the exporter should run in host pid namespace
+	procRoot = "/proc"
+
+	containerIDReg = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+	// cgroupScopePrefixes the prefixes of the systemd scope of container, such as cri-containerd-<id>.scope
+	cgroupScopePrefixes = []string{"docker-", "cri-containerd-", "crio-", "isulad-", "libpod-"}
+
+	// ErrNotHostPidNamespace the pids given by driver are of host, they are other processes or not visible when
+	// the exporter is not running in host pid namespace
+	ErrNotHostPidNamespace = errors.New("the exporter is not running in host pid namespace")
+
+	hostPidNamespace = &pidNamespaceCheck{}
+)
+
+// pidNamespaceCheck the pid namespace of exporter is checked once, since it never changes
+type pidNamespaceCheck struct {
+	once sync.Once
+	host bool
+}
+
+// ProcessOwner the owner of process using npu
+type ProcessOwner struct {
+	// ContainerID the id of container the process running in, empty for the process running on host
+	ContainerID string
+	// Command the command name of process
+	Command string
+}
+
+// InHostPidNamespace whether the exporter is running in host pid namespace, the processes of host pids can be
+// resolved only in it
+func InHostPidNamespace() bool {
+	hostPidNamespace.once.Do(func() {
+		hostPidNamespace.host = inHostPidNamespace()
+		if !hostPidNamespace.host {
+			logger.Warnf("%v, the container of processes using npu is not resolved by cgroup",
+				ErrNotHostPidNamespace)
+		}
+	})
+	return hostPidNamespace.host
+}
+
+// inHostPidNamespace the NSpid field of /proc/<pid>/status lists the pid in each nested pid namespace, there is
+// only one in host pid namespace. /proc/1/ns/pid is the same as the one of exporter in a container with its own pid
+// namespace, so it is only compared when NSpid is not supported by kernel. the pid of exporter is used instead of
+// /proc/self, since the symlink is rejected by utils.ReadLimitBytes
+func inHostPidNamespace() bool {
+	self := filepath.Join(procRoot, strconv.Itoa(os.Getpid()))
+	status, err := utils.ReadLimitBytes(filepath.Join(self, "status"), maxStatusBytes)
+	if err != nil {
+		logger.Debugf("read status of exporter failed: %v", err)
+		return false
+	}
+	for _, line := range strings.Split(string(status), "\n") {
+		if strings.HasPrefix(line, nsPidField) {
+			return len(strings.Fields(strings.TrimPrefix(line, nsPidField))) == 1
+		}
+	}
+	selfNs, err := os.Readlink(filepath.Join(self, "ns", "pid"))
+	if err != nil {
+		logger.Debugf("read pid namespace of exporter failed: %v", err)
+		return false
+	}
+	initNs, err := os.Readlink(filepath.Join(procRoot, "1", "ns", "pid"))
+	if err != nil {
+		logger.Debugf("read pid namespace of init process failed: %v", err)
+		return false
+	}
+	return selfNs == initNs
+}
+
+// GetProcessOwner resolve the container of process by its cgroup, error when the process is not visible, or the
+// exporter is not running in host pid namespace
+func GetProcessOwner(pid int32) (ProcessOwner, error) {
+	if !InHostPidNamespace() {
+		return ProcessOwner{}, ErrNotHostPidNamespace
+	}
+	dir := filepath.Join(procRoot, strconv.FormatInt(int64(pid), decimalBase))
+	data, err := utils.ReadLimitBytes(filepath.Join(dir, "cgroup"), maxCgroupFileBytes)
+	if err != nil {
+		return ProcessOwner{}, err
+	}
+	owner := ProcessOwner{ContainerID: containerIDFromCgroup(string(data))}
+	if comm, err := utils.ReadLimitBytes(filepath.Join(dir, "comm"), maxCommBytes); err == nil {
+		owner.Command = strings.TrimSpace(string(comm))
+	}
+	return owner, nil
+}
+
+// ProcessStartTime the start time of process of host pid, given by the ticks it is started after boot in
+// /proc/<pid>/stat and the boot time in /proc/stat
+func ProcessStartTime(pid uint32) (time.Time, error) {
+	if !InHostPidNamespace() {
+		return time.Time{}, ErrNotHostPidNamespace
+	}
+	path := filepath.Join(procRoot, strconv.FormatUint(uint64(pid), decimalBase), "stat")
+	data, err := utils.ReadLimitBytes(path, maxStatBytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// the command in parentheses may contain spaces and parentheses
+	stat := string(data)
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return time.Time{}, errors.New("invalid stat of process")
+	}
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) <= startTimeIdx {
+		return time.Time{}, errors.New("start time is not found in stat of process")
+	}
+	ticks, err := strconv.ParseUint(fields[startTimeIdx], decimalBase, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	boot, err := bootTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return boot.Add(time.Duration(ticks) * time.Second / userHz), nil
+}
+
+func bootTime() (time.Time, error) {
+	data, err := utils.ReadLimitBytes(filepath.Join(procRoot, "stat"), maxProcStatBytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != bootTimeFieldNum || fields[0] != bootTimeField {
+			continue
+		}
+		seconds, err := strconv.ParseInt(fields[1], decimalBase, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Time{}, errors.New("boot time is not found in stat")
+}
+
+// containerIDFromCgroup find the container id in the content of /proc/<pid>/cgroup, the formats are:
+//
+//	cgroup v1 cgroupfs: 4:memory:/docker/<id>, 4:memory:/kubepods/besteffort/pod<uid>/<id>, 4:memory:/isulad/<id>
+//	cgroup v1 systemd:  1:name=systemd:/system.slice/docker-<id>.scope
+//	cgroup v2:          0::/kubepods.slice/.../cri-containerd-<id>.scope, 0::/.../crio-<id>.scope
+//
+// the innermost container is used for nested containers, empty when the process is running on host
+func containerIDFromCgroup(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), ":", cgroupFieldNum)
+		if len(fields) != cgroupFieldNum {
+			continue
+		}
+		if id := containerIDFromPath(fields[cgroupFieldNum-1]); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+func containerIDFromPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		segment := segments[i]
+		if strings.HasSuffix(segment, scopeSuffix) {
+			segment = strings.TrimSuffix(segment, scopeSuffix)
+			for _, prefix := range cgroupScopePrefixes {
+				if strings.HasPrefix(segment, prefix) {
+					segment = strings.TrimPrefix(segment, prefix)
+					break
+				}
+			}
+		}
+		if containerIDReg.MatchString(segment) {
+			return segment
+		}
+	}
+	return ""
+}
@@ -0,0 +1,181 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	mockPid        = 1234
+	mockCommand    = "python3"
+	mockCgroupMode = 0600
+	mockDirMode    = 0700
+	mockBootTime   = 1700000000
+	// mockStartTicks the process is started 2.5 seconds after boot
+	mockStartTicks = "250"
+	mockStartNano  = 500000000
+)
+
+var mockOwnerID = strings.Repeat("ab", 32)
+
+// mockPidNamespace write the status of exporter with the pids in nested pid namespaces, and check it again
+func mockPidNamespace(nsPid string) {
+	dir := filepath.Join(procRoot, strconv.Itoa(os.Getpid()))
+	convey.So(os.MkdirAll(dir, mockDirMode), convey.ShouldBeNil)
+	convey.So(os.WriteFile(filepath.Join(dir, "status"), []byte("Name:\tnpu-exporter\nNSpid:\t"+nsPid+"\n"),
+		mockCgroupMode), convey.ShouldBeNil)
+	hostPidNamespace = &pidNamespaceCheck{}
+}
+
+// TestContainerIDFromCgroup test finding the container id in the cgroup of process
+func TestContainerIDFromCgroup(t *testing.T) {
+	convey.Convey("TestContainerIDFromCgroup", t, func() {
+		cases := map[string]string{
+			"cgroup v1 docker":         "12:devices:/docker/" + mockOwnerID + "\n",
+			"cgroup v1 kubepods":       "4:memory:/kubepods/besteffort/pod1a2b/" + mockOwnerID + "\n",
+			"cgroup v1 isula":          "3:cpu,cpuacct:/isulad/" + mockOwnerID + "\n",
+			"cgroup v1 systemd docker": "1:name=systemd:/system.slice/docker-" + mockOwnerID + ".scope\n",
+			"cgroup v2 containerd": "0::/kubepods.slice/kubepods-pod1a2b.slice/cri-containerd-" +
+				mockOwnerID + ".scope\n",
+			"cgroup v2 crio":   "0::/kubepods.slice/kubepods-pod1a2b.slice/crio-" + mockOwnerID + ".scope\n",
+			"cgroup v2 podman": "0::/machine.slice/libpod-" + mockOwnerID + ".scope/container\n",
+		}
+		for name, content := range cases {
+			convey.Convey(name, func() {
+				convey.So(containerIDFromCgroup(content), convey.ShouldEqual, mockOwnerID)
+			})
+		}
+		convey.Convey("empty when process running on host", func() {
+			content := "0::/user.slice/user-0.slice/session-1.scope\n1:name=systemd:/system.slice/sshd.service\n"
+			convey.So(containerIDFromCgroup(content), convey.ShouldBeEmpty)
+		})
+	})
+}
+
+// TestGetProcessOwner test resolving the owner of process from proc
+func TestGetProcessOwner(t *testing.T) {
+	convey.Convey("TestGetProcessOwner", t, func() {
+		originRoot := procRoot
+		procRoot = t.TempDir()
+		defer func() { procRoot = originRoot }()
+		dir := filepath.Join(procRoot, "1234")
+		convey.So(os.MkdirAll(dir, mockDirMode), convey.ShouldBeNil)
+		convey.So(os.WriteFile(filepath.Join(dir, "cgroup"),
+			[]byte("0::/system.slice/docker-"+mockOwnerID+".scope\n"), mockCgroupMode), convey.ShouldBeNil)
+		convey.So(os.WriteFile(filepath.Join(dir, "comm"), []byte(mockCommand+"\n"), mockCgroupMode),
+			convey.ShouldBeNil)
+		mockPidNamespace("4321")
+		defer func() { hostPidNamespace = &pidNamespaceCheck{} }()
+
+		convey.Convey("resolve container and command", func() {
+			owner, err := GetProcessOwner(mockPid)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(owner, convey.ShouldResemble, ProcessOwner{ContainerID: mockOwnerID, Command: mockCommand})
+		})
+		convey.Convey("error when process not visible", func() {
+			_, err := GetProcessOwner(mockPid + 1)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+		convey.Convey("error when not running in host pid namespace", func() {
+			mockPidNamespace("4321\t7")
+			_, err := GetProcessOwner(mockPid)
+			convey.So(err, convey.ShouldEqual, ErrNotHostPidNamespace)
+		})
+	})
+}
+
+// TestInHostPidNamespace test checking the pid namespace of exporter
+func TestInHostPidNamespace(t *testing.T) {
+	convey.Convey("TestInHostPidNamespace", t, func() {
+		originRoot := procRoot
+		procRoot = t.TempDir()
+		defer func() {
+			procRoot = originRoot
+			hostPidNamespace = &pidNamespaceCheck{}
+		}()
+		convey.Convey("host pid namespace has only one pid", func() {
+			mockPidNamespace("4321")
+			convey.So(InHostPidNamespace(), convey.ShouldBeTrue)
+		})
+		convey.Convey("nested pid namespace has pids of each level", func() {
+			mockPidNamespace("4321\t7")
+			convey.So(InHostPidNamespace(), convey.ShouldBeFalse)
+		})
+		convey.Convey("not host when status is not readable", func() {
+			hostPidNamespace = &pidNamespaceCheck{}
+			convey.So(InHostPidNamespace(), convey.ShouldBeFalse)
+		})
+		convey.Convey("the status of exporter is read from real proc", func() {
+			procRoot = originRoot
+			status, err := os.ReadFile("/proc/self/status")
+			convey.So(err, convey.ShouldBeNil)
+			for _, line := range strings.Split(string(status), "\n") {
+				if strings.HasPrefix(line, nsPidField) {
+					convey.So(inHostPidNamespace(), convey.ShouldEqual,
+						len(strings.Fields(strings.TrimPrefix(line, nsPidField))) == 1)
+				}
+			}
+		})
+	})
+}
+
+// TestProcessStartTime test getting the start time of process from proc
+func TestProcessStartTime(t *testing.T) {
+	convey.Convey("TestProcessStartTime", t, func() {
+		originRoot := procRoot
+		procRoot = t.TempDir()
+		defer func() {
+			procRoot = originRoot
+			hostPidNamespace = &pidNamespaceCheck{}
+		}()
+		dir := filepath.Join(procRoot, strconv.Itoa(mockPid))
+		convey.So(os.MkdirAll(dir, mockDirMode), convey.ShouldBeNil)
+		fields := append([]string{"S"}, strings.Fields(strings.Repeat("0 ", startTimeIdx-1))...)
+		stat := strconv.Itoa(mockPid) + " (python3 (x) y) " + strings.Join(append(fields, mockStartTicks, "0"), " ")
+		convey.So(os.WriteFile(filepath.Join(dir, "stat"), []byte(stat+"\n"), mockCgroupMode), convey.ShouldBeNil)
+		mockPidNamespace("4321")
+
+		convey.Convey("error when boot time is not found", func() {
+			_, err := ProcessStartTime(mockPid)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+		convey.So(os.WriteFile(filepath.Join(procRoot, "stat"),
+			[]byte("cpu  1 2 3\nbtime "+strconv.Itoa(mockBootTime)+"\nprocesses 10\n"), mockCgroupMode),
+			convey.ShouldBeNil)
+		convey.Convey("start time is the boot time and the ticks after boot", func() {
+			startedAt, err := ProcessStartTime(mockPid)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(startedAt.Equal(time.Unix(mockBootTime+2, mockStartNano)), convey.ShouldBeTrue)
+		})
+		convey.Convey("error when process not visible", func() {
+			_, err := ProcessStartTime(mockPid + 1)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+		convey.Convey("error when not running in host pid namespace", func() {
+			mockPidNamespace("4321\t7")
+			_, err := ProcessStartTime(mockPid)
+			convey.So(err, convey.ShouldEqual, ErrNotHostPidNamespace)
+		})
+	})
+}
@@ -0,0 +1,122 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+	"k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/hwlog"
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/utils"
+	"github.com/professorshandian/npu-exporter/collector/container/isula"
+	v1 "github.com/professorshandian/npu-exporter/collector/container/v1"
+)
+
+// crioInfoKey the key of verbose info in the container status of CRI-O
+const crioInfoKey = "info"
+
+// crioVerboseInfo the verbose info of container given by CRI-O, only the OCI spec is used
+type crioVerboseInfo struct {
+	RuntimeSpec *v1.Spec `json:"runtimeSpec,omitempty"`
+}
+
+// CrioOperatorTool implements RuntimeOperator interface for CRI-O, which has no containerd API,
+// so both containers and their OCI spec are got over CRI
+type CrioOperatorTool struct {
+	RuntimeOperatorTool
+}
+
+// Init initializes CRI client of CRI-O
+func (operator *CrioOperatorTool) Init() error {
+	return runAsRoot(func() error {
+		if _, err := utils.CheckPath(strings.TrimPrefix(operator.CriEndpoint, unixPre)); err != nil {
+			hwlog.RunLog.Error("check socket path failed")
+			return err
+		}
+		if err := operator.initCriClient(); err != nil {
+			return fmt.Errorf("init CRI client failed, %s", err)
+		}
+		return nil
+	})
+}
+
+// Close closes CRI connection
+func (operator *CrioOperatorTool) Close() error {
+	if operator.criConn == nil {
+		return nil
+	}
+	return operator.criConn.Close()
+}
+
+// GetContainerInfoByID get the OCI spec of container from the verbose info of CRI ContainerStatus
+func (operator *CrioOperatorTool) GetContainerInfoByID(ctx context.Context, id string) (v1.Spec, error) {
+	criClient := operator.getCriClient()
+	if utils.IsNil(criClient) || operator.criConn == nil {
+		return v1.Spec{}, errors.New("criClient is empty")
+	}
+	var info map[string]string
+	switch client := criClient.(type) {
+	case criv1.RuntimeServiceClient:
+		resp, err := client.ContainerStatus(ctx, &criv1.ContainerStatusRequest{ContainerId: id, Verbose: true})
+		if err != nil {
+			hwlog.RunLog.Error("call CRI ContainerStatus method failed")
+			return v1.Spec{}, err
+		}
+		info = resp.Info
+	case v1alpha2.RuntimeServiceClient:
+		resp, err := client.ContainerStatus(ctx, &v1alpha2.ContainerStatusRequest{ContainerId: id, Verbose: true})
+		if err != nil {
+			hwlog.RunLog.Error("call CRI ContainerStatus method failed")
+			return v1.Spec{}, err
+		}
+		info = resp.Info
+	default:
+		return v1.Spec{}, errors.New("unexpected CRI client")
+	}
+	return parseCrioSpec(info)
+}
+
+func parseCrioSpec(info map[string]string) (v1.Spec, error) {
+	data, ok := info[crioInfoKey]
+	if !ok {
+		return v1.Spec{}, errors.New("verbose info of container is empty")
+	}
+	verbose := crioVerboseInfo{}
+	if err := json.Unmarshal([]byte(data), &verbose); err != nil {
+		hwlog.RunLog.Error("unmarshal verbose info of container failed")
+		return v1.Spec{}, err
+	}
+	if verbose.RuntimeSpec == nil {
+		return v1.Spec{}, errors.New("runtime spec of container is empty")
+	}
+	return *verbose.RuntimeSpec, nil
+}
+
+// GetIsulaContainerInfoByID not supported by CRI-O
+func (operator *CrioOperatorTool) GetIsulaContainerInfoByID(context.Context, string) (isula.ContainerJson, error) {
+	return isula.ContainerJson{}, errors.New("not supported by CRI-O")
+}
+
+// GetContainerType return container type
+func (operator *CrioOperatorTool) GetContainerType() string {
+	return CrioContainer
+}
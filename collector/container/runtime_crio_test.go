@@ -0,0 +1,98 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+const (
+	mockCrioInfo = `{"sandboxID":"sandbox-1","pid":100,"runtimeSpec":{` +
+		`"process":{"env":["ASCEND_VISIBLE_DEVICES=0,1"]},` +
+		`"linux":{"resources":{"devices":[{"allow":true,"type":"c","major":236,"minor":0,"access":"rw"}]}}}}`
+)
+
+// fakeCrioServer the CRI server of CRI-O, giving the OCI spec in the verbose container status
+type fakeCrioServer struct {
+	fakeCriV1Server
+}
+
+func (s *fakeCrioServer) ContainerStatus(_ context.Context,
+	req *criv1.ContainerStatusRequest) (*criv1.ContainerStatusResponse, error) {
+	resp := &criv1.ContainerStatusResponse{Status: &criv1.ContainerStatus{Id: req.ContainerId}}
+	if req.Verbose {
+		resp.Info = map[string]string{crioInfoKey: mockCrioInfo}
+	}
+	return resp, nil
+}
+
+// TestCrioGetContainerInfoByID test getting the OCI spec of container from CRI-O
+func TestCrioGetContainerInfoByID(t *testing.T) {
+	endpoint := startFakeCriServer(t, func(s *grpc.Server) {
+		criv1.RegisterRuntimeServiceServer(s, &fakeCrioServer{})
+	})
+	convey.Convey("TestCrioGetContainerInfoByID", t, func() {
+		operator := &CrioOperatorTool{RuntimeOperatorTool: RuntimeOperatorTool{CriEndpoint: endpoint}}
+		convey.So(operator.Init(), convey.ShouldBeNil)
+		defer operator.Close()
+		convey.Convey("the containers are listed over CRI", func() {
+			containers, err := operator.GetContainers(context.Background())
+			convey.So(err, convey.ShouldBeNil)
+			shouldHaveMergedMetadata(containers)
+		})
+		convey.Convey("the env and devices are got from the runtime spec", func() {
+			spec, err := operator.GetContainerInfoByID(context.Background(), mockContainerID)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(spec.Process.Env, convey.ShouldResemble, []string{"ASCEND_VISIBLE_DEVICES=0,1"})
+			convey.So(spec.Linux.Resources.Devices, convey.ShouldHaveLength, 1)
+			convey.So(*spec.Linux.Resources.Devices[0].Minor, convey.ShouldEqual, 0)
+		})
+		convey.Convey("the parser gets devices of container from the env", func() {
+			parser := &DevicesParser{RuntimeOperator: operator}
+			rs := make(chan DevicesInfo, 1)
+			err := parser.parseDevices(context.Background(), &CommonContainer{Id: mockContainerID,
+				Labels: map[string]string{labelK8sPodNamespace: "default", labelK8sPodName: mockPodName,
+					labelContainerName: "c1"}}, rs)
+			convey.So(err, convey.ShouldBeNil)
+			info := <-rs
+			convey.So(info.Name, convey.ShouldEqual, "default_"+mockPodName+"_c1")
+			convey.So(info.Devices, convey.ShouldResemble, []int{0, 1})
+		})
+	})
+}
+
+// TestParseCrioSpec test parsing the verbose info of CRI-O
+func TestParseCrioSpec(t *testing.T) {
+	convey.Convey("TestParseCrioSpec", t, func() {
+		convey.Convey("failed when verbose info is empty", func() {
+			_, err := parseCrioSpec(nil)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+		convey.Convey("failed when runtime spec is missing", func() {
+			_, err := parseCrioSpec(map[string]string{crioInfoKey: `{"pid":1}`})
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+		convey.Convey("failed when verbose info is not json", func() {
+			_, err := parseCrioSpec(map[string]string{crioInfoKey: "{"})
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+	})
+}
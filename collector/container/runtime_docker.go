@@ -0,0 +1,163 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/hwlog"
+	"github.com/professorshandian/npu-exporter/collector/container/isula"
+	v1 "github.com/professorshandian/npu-exporter/collector/container/v1"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	// dockerHost the host in url of docker engine API, the request is always sent to the unix socket
+	dockerHost          = "http://docker"
+	dockerPingPath      = "/_ping"
+	dockerListPath      = "/containers/json"
+	dockerInspectPath   = "/containers/%s/json"
+	dockerRunningFilter = `{"status":["running"]}`
+	dockerAPIName       = "docker engine"
+
+	// dockerNamespace the namespace of standalone docker container, which has no kubernetes labels
+	dockerNamespace = "docker"
+)
+
+// dockerContainer the container listed by docker engine API
+type dockerContainer struct {
+	ID     string            `json:"Id"`
+	Names  []string          `json:"Names"`
+	Labels map[string]string `json:"Labels"`
+	// Created the unix seconds the container is created, docker does not list the start time
+	Created int64 `json:"Created"`
+}
+
+// dockerInspect the container inspected by docker engine API, only start time, env and devices are used
+type dockerInspect struct {
+	State *struct {
+		StartedAt time.Time `json:"StartedAt"`
+	} `json:"State"`
+	Config *struct {
+		Env []string `json:"Env"`
+	} `json:"Config"`
+	HostConfig *struct {
+		Devices    []restDevice `json:"Devices"`
+		Privileged bool         `json:"Privileged"`
+	} `json:"HostConfig"`
+}
+
+// DockerEngineOperatorTool implements RuntimeOperator interface for docker without containerd socket or CRI,
+// the containers and their spec are got from the docker engine API over its unix socket
+type DockerEngineOperatorTool struct {
+	client *restClient
+	// Endpoint docker engine API server endpoint
+	Endpoint string
+}
+
+// Init initializes the client of docker engine API and checks it is reachable
+func (operator *DockerEngineOperatorTool) Init() error {
+	return runAsRoot(func() error {
+		client, err := newRestClient(operator.Endpoint, dockerHost, dockerAPIName, dockerPingPath)
+		if err != nil {
+			return err
+		}
+		operator.client = client
+		return nil
+	})
+}
+
+// Close closes the idle connections to docker engine API
+func (operator *DockerEngineOperatorTool) Close() error {
+	operator.client.close()
+	return nil
+}
+
+// GetContainers returns the running containers of docker
+func (operator *DockerEngineOperatorTool) GetContainers(ctx context.Context) ([]*CommonContainer, error) {
+	if operator.client == nil {
+		return nil, errors.New("docker engine client is empty")
+	}
+	var containers []dockerContainer
+	path := dockerListPath + "?filters=" + url.QueryEscape(dockerRunningFilter)
+	if err := operator.client.get(ctx, path, &containers); err != nil {
+		hwlog.RunLog.Error(err)
+		return nil, err
+	}
+	allContainers := make([]*CommonContainer, 0, len(containers))
+	for _, container := range containers {
+		allContainers = append(allContainers, &CommonContainer{
+			Id:        container.ID,
+			Labels:    dockerLabels(container),
+			StartedAt: operator.startedAt(ctx, container),
+		})
+	}
+	return allContainers, nil
+}
+
+// startedAt the start time of container given by inspecting it, which changes when the container is restarted,
+// the creation time is used when it is not got
+func (operator *DockerEngineOperatorTool) startedAt(ctx context.Context, container dockerContainer) time.Time {
+	inspect := dockerInspect{}
+	err := operator.client.get(ctx, fmt.Sprintf(dockerInspectPath, url.PathEscape(container.ID)), &inspect)
+	if err != nil || inspect.State == nil || inspect.State.StartedAt.IsZero() {
+		logger.Debugf("start time of container %s is not got, use the creation time: %v", container.ID, err)
+		return timeOfUnix(container.Created)
+	}
+	return inspect.State.StartedAt
+}
+
+// dockerLabels the labels of docker container, the labels set by dockershim or cri-dockerd are kept for the
+// container of kubernetes, otherwise the namespace is docker and the pod is the container itself
+func dockerLabels(container dockerContainer) map[string]string {
+	name := container.ID
+	if len(container.Names) > 0 {
+		name = strings.TrimPrefix(container.Names[0], "/")
+	}
+	return standaloneLabels(container.Labels, dockerNamespace, "", name)
+}
+
+// GetContainerInfoByID build the OCI spec with env and linux devices from the inspected container
+func (operator *DockerEngineOperatorTool) GetContainerInfoByID(ctx context.Context, id string) (v1.Spec, error) {
+	if operator.client == nil {
+		return v1.Spec{}, errors.New("docker engine client is empty")
+	}
+	inspect := dockerInspect{}
+	if err := operator.client.get(ctx, fmt.Sprintf(dockerInspectPath, url.PathEscape(id)), &inspect); err != nil {
+		hwlog.RunLog.Error("call docker engine inspect API failed")
+		return v1.Spec{}, err
+	}
+	if inspect.Config == nil || inspect.HostConfig == nil {
+		return v1.Spec{}, errors.New("empty container info")
+	}
+	return specOfRestContainer(inspect.Config.Env, inspect.HostConfig.Devices, inspect.HostConfig.Privileged), nil
+}
+
+// GetIsulaContainerInfoByID not supported by docker engine
+func (operator *DockerEngineOperatorTool) GetIsulaContainerInfoByID(context.Context,
+	string) (isula.ContainerJson, error) {
+	return isula.ContainerJson{}, errors.New("not supported by docker engine")
+}
+
+// GetContainerType return container type
+func (operator *DockerEngineOperatorTool) GetContainerType() string {
+	return DockerEngineContainer
+}
@@ -0,0 +1,122 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	mockDockerList = `[{"Id":"docker-1","Names":["/infer"],"Labels":{"app":"infer"},"Created":1700000000},` +
+		`{"Id":"docker-2","Names":["/k8s_worker"],"Labels":{"io.kubernetes.pod.namespace":"default",` +
+		`"io.kubernetes.pod.name":"worker-0","io.kubernetes.container.name":"worker"}}]`
+	mockDockerInspect = `{"State":{"StartedAt":"2023-11-14T22:13:25.5Z"},` +
+		`"Config":{"Env":["ASCEND_VISIBLE_DEVICES=0"]},"HostConfig":{"Privileged":false,` +
+		`"Devices":[{"PathOnHost":"/dev/null","PathInContainer":"/dev/davinci0","CgroupPermissions":"rwm"}]}}`
+	// mockDockerStartDelay the container is started 5 seconds after it is created
+	mockDockerStartDelay = 5
+)
+
+// startFakeDockerServer start a local docker engine API server on unix socket, return its endpoint
+func startFakeDockerServer(t *testing.T) string {
+	sock := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen(unixPrefix, sock)
+	if err != nil {
+		t.Fatalf("listen on %s failed: %v", sock, err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(dockerPingPath, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("OK"))
+	})
+	mux.HandleFunc(dockerListPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("filters") != dockerRunningFilter {
+			_, _ = w.Write([]byte("[]"))
+			return
+		}
+		_, _ = w.Write([]byte(mockDockerList))
+	})
+	mux.HandleFunc("/containers/docker-1/json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(mockDockerInspect))
+	})
+	mux.HandleFunc("/containers/docker-2/json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(mockPrivilegedInspect))
+	})
+	server := &http.Server{Handler: mux}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			t.Logf("fake docker server stopped: %v", err)
+		}
+	}()
+	t.Cleanup(func() { _ = server.Close() })
+	return unixPre + sock
+}
+
+// TestDockerEngineOperator test getting containers and their spec from docker engine API
+func TestDockerEngineOperator(t *testing.T) {
+	endpoint := startFakeDockerServer(t)
+	convey.Convey("TestDockerEngineOperator", t, func() {
+		operator := &DockerEngineOperatorTool{Endpoint: endpoint}
+		convey.So(operator.Init(), convey.ShouldBeNil)
+		defer operator.Close()
+		convey.Convey("the kubernetes labels are filled for standalone containers only", func() {
+			containers, err := operator.GetContainers(context.Background())
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(containers, convey.ShouldHaveLength, 2)
+			convey.So(containers[0].Labels, convey.ShouldResemble, map[string]string{"app": "infer",
+				labelK8sPodNamespace: dockerNamespace, labelK8sPodName: "infer", labelContainerName: "infer"})
+			convey.So(containers[1].Labels[labelK8sPodNamespace], convey.ShouldEqual, "default")
+			convey.So(containers[1].Labels[labelK8sPodName], convey.ShouldEqual, "worker-0")
+			convey.So(containers[1].Labels[labelContainerName], convey.ShouldEqual, "worker")
+			convey.So(containers[0].StartedAt.Unix(), convey.ShouldEqual, mockStartedAt+mockDockerStartDelay)
+			convey.So(containers[1].StartedAt.IsZero(), convey.ShouldBeTrue)
+		})
+		convey.Convey("the env and devices of container are in spec", func() {
+			spec, err := operator.GetContainerInfoByID(context.Background(), "docker-1")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(spec.Process.Env, convey.ShouldResemble, []string{"ASCEND_VISIBLE_DEVICES=0"})
+			devices := spec.Linux.Resources.Devices
+			convey.So(devices, convey.ShouldHaveLength, 1)
+			convey.So(*devices[0].Major, convey.ShouldEqual, devNullMajor)
+			convey.So(*devices[0].Minor, convey.ShouldEqual, devNullMinor)
+		})
+		convey.Convey("the devices of privileged container are not monitored", func() {
+			spec, err := operator.GetContainerInfoByID(context.Background(), "docker-2")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(spec.Linux.Resources.Devices, convey.ShouldBeEmpty)
+		})
+		convey.Convey("failed when the container is not found", func() {
+			_, err := operator.GetContainerInfoByID(context.Background(), "docker-3")
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+	})
+}
+
+// TestDockerEngineOperatorInit test init failed when the socket does not exist
+func TestDockerEngineOperatorInit(t *testing.T) {
+	convey.Convey("TestDockerEngineOperatorInit", t, func() {
+		operator := &DockerEngineOperatorTool{Endpoint: unixPre + filepath.Join(t.TempDir(), "docker.sock")}
+		convey.So(operator.Init(), convey.ShouldNotBeNil)
+		convey.So(operator.Close(), convey.ShouldBeNil)
+		_, err := operator.GetContainers(context.Background())
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,160 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/utils"
+	v1 "github.com/professorshandian/npu-exporter/collector/container/v1"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+// ContainerEventType the type of container lifecycle event
+type ContainerEventType int
+
+const (
+	// ContainerStarted the container is started, it should be inspected
+	ContainerStarted ContainerEventType = iota
+	// ContainerStopped the container is exited or deleted, it is removed from the index
+	ContainerStopped
+)
+
+// ErrEventsNotSupported the runtime does not support watching container events
+var ErrEventsNotSupported = errors.New("container events are not supported by runtime")
+
+// ContainerEvent the lifecycle event of container given by runtime
+type ContainerEvent struct {
+	ID   string
+	Type ContainerEventType
+}
+
+// ContainerEventWatcher the runtime operator able to watch the lifecycle events of containers
+type ContainerEventWatcher interface {
+	// WatchContainerEvents subscribe the events and call handle for each of them, it blocks until ctx is done
+	// or the subscription is broken
+	WatchContainerEvents(ctx context.Context, handle func(ContainerEvent)) error
+}
+
+// WatchContainerEvents watch the events of containerd if its API is connected, otherwise the events of CRI when
+// CriEvents is set
+func (operator *RuntimeOperatorTool) WatchContainerEvents(ctx context.Context, handle func(ContainerEvent)) error {
+	if _, ok := operator.client.(v1.ContainersClient); ok && operator.conn != nil {
+		return watchContainerdEvents(ctx, v1.NewEventsClient(operator.conn), operator.eventNamespaces(), handle)
+	}
+	if !operator.CriEvents {
+		return ErrEventsNotSupported
+	}
+	if client, ok := operator.getCriClient().(criv1.RuntimeServiceClient); ok && !utils.IsNil(client) {
+		return watchCriEvents(ctx, client, handle)
+	}
+	return ErrEventsNotSupported
+}
+
+// containerdEventFilters the filters of events of the namespaces, the events of all namespaces are subscribed
+// when AllNamespaces is given
+func containerdEventFilters(namespaces []string) []string {
+	topics := []string{v1.TopicTaskStart, v1.TopicTaskExit, v1.TopicContainerDelete}
+	filters := make([]string, 0, len(topics)*len(namespaces))
+	for _, topic := range topics {
+		if contains(namespaces, AllNamespaces) {
+			filters = append(filters, fmt.Sprintf(`topic==%q`, topic))
+			continue
+		}
+		for _, namespace := range namespaces {
+			filters = append(filters, fmt.Sprintf(`namespace==%s,topic==%q`, namespace, topic))
+		}
+	}
+	return filters
+}
+
+func watchContainerdEvents(ctx context.Context, client v1.EventsClient, namespaces []string,
+	handle func(ContainerEvent)) error {
+	stream, err := client.Subscribe(ctx, &v1.SubscribeRequest{Filters: containerdEventFilters(namespaces)})
+	if err != nil {
+		return err
+	}
+	logger.Infof("subscribed containerd events of namespaces %v", namespaces)
+	for {
+		envelope, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if envelope.Event == nil || !(contains(namespaces, envelope.Namespace) ||
+			contains(namespaces, AllNamespaces)) {
+			continue
+		}
+		event, err := parseContainerdEvent(envelope)
+		if err != nil {
+			logger.Warnf("parse containerd event of topic %s failed: %v", envelope.Topic, err)
+			continue
+		}
+		if event.ID != "" {
+			handle(event)
+		}
+	}
+}
+
+func parseContainerdEvent(envelope *v1.Envelope) (ContainerEvent, error) {
+	switch envelope.Topic {
+	case v1.TopicTaskStart:
+		start := &v1.TaskStart{}
+		err := proto.Unmarshal(envelope.Event.Value, start)
+		return ContainerEvent{ID: start.GetContainerId(), Type: ContainerStarted}, err
+	case v1.TopicTaskExit:
+		exit := &v1.TaskExit{}
+		if err := proto.Unmarshal(envelope.Event.Value, exit); err != nil {
+			return ContainerEvent{}, err
+		}
+		// the exit of exec process does not stop the container
+		if exit.GetId() != exit.GetContainerId() {
+			return ContainerEvent{}, nil
+		}
+		return ContainerEvent{ID: exit.GetContainerId(), Type: ContainerStopped}, nil
+	case v1.TopicContainerDelete:
+		del := &v1.ContainerDelete{}
+		err := proto.Unmarshal(envelope.Event.Value, del)
+		return ContainerEvent{ID: del.GetId(), Type: ContainerStopped}, err
+	default:
+		return ContainerEvent{}, nil
+	}
+}
+
+func watchCriEvents(ctx context.Context, client criv1.RuntimeServiceClient, handle func(ContainerEvent)) error {
+	stream, err := client.GetContainerEvents(ctx, &criv1.GetEventsRequest{})
+	if err != nil {
+		return err
+	}
+	logger.Info("subscribed CRI container events")
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		switch resp.ContainerEventType {
+		case criv1.ContainerEventType_CONTAINER_STARTED_EVENT:
+			handle(ContainerEvent{ID: resp.ContainerId, Type: ContainerStarted})
+		case criv1.ContainerEventType_CONTAINER_STOPPED_EVENT, criv1.ContainerEventType_CONTAINER_DELETED_EVENT:
+			handle(ContainerEvent{ID: resp.ContainerId, Type: ContainerStopped})
+		default:
+		}
+	}
+}
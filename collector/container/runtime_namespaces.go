@@ -0,0 +1,181 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/professorshandian/npu-exporter/collector/container/v1"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	// AllNamespaces discover all namespaces of containerd by its namespaces API
+	AllNamespaces = "*"
+
+	// labelContainerdNamespace the namespace of containerd the container belongs to, added to the containers
+	// listed by containerd API
+	labelContainerdNamespace = "io.containerd.namespace"
+	// labelNerdctlName the name of container given by nerdctl
+	labelNerdctlName = "nerdctl/name"
+	shortIDLen       = 12
+)
+
+// getNamespacesContainers list the running containers of the other namespaces by containerd API, the namespace
+// failed to list is skipped. the containers without kubernetes labels are named by the namespace and their names,
+// same as podman
+func (operator *RuntimeOperatorTool) getNamespacesContainers(ctx context.Context) []*CommonContainer {
+	if _, ok := operator.client.(v1.ContainersClient); !ok || operator.conn == nil {
+		logger.Warn("containerd client is not available, the other namespaces are not listed")
+		return nil
+	}
+	var res []*CommonContainer
+	namespaces := make(map[string]string)
+	for _, ns := range operator.listNamespaces(ctx) {
+		containers, err := operator.listNamespaceContainers(ctx, ns)
+		if err != nil {
+			logger.Warnf("list containers of containerd namespace %s failed: %v", ns, err)
+			continue
+		}
+		for _, c := range containers {
+			namespaces[c.Id] = ns
+		}
+		res = append(res, containers...)
+	}
+	operator.nsMutex.Lock()
+	operator.containerNamespaces = namespaces
+	operator.nsMutex.Unlock()
+	return res
+}
+
+// listNamespaces the configured namespaces, or all namespaces of containerd, excluding the namespace of CRI
+func (operator *RuntimeOperatorTool) listNamespaces(ctx context.Context) []string {
+	namespaces := operator.Namespaces
+	if contains(namespaces, AllNamespaces) {
+		resp, err := v1.NewNamespacesClient(operator.conn).List(ctx, &v1.ListNamespacesRequest{})
+		if err != nil {
+			logger.Warnf("list containerd namespaces failed: %v", err)
+			return nil
+		}
+		namespaces = make([]string, 0, len(resp.Namespaces))
+		for _, ns := range resp.Namespaces {
+			if ns != nil {
+				namespaces = append(namespaces, ns.Name)
+			}
+		}
+	}
+	res := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		if ns != "" && ns != AllNamespaces && ns != operator.Namespace && !contains(res, ns) {
+			res = append(res, ns)
+		}
+	}
+	return res
+}
+
+// listNamespaceContainers list the containers whose task is running in the namespace
+func (operator *RuntimeOperatorTool) listNamespaceContainers(ctx context.Context,
+	namespace string) ([]*CommonContainer, error) {
+	nsCtx := setGrpcNamespaceHeader(ctx, namespace)
+	tasks, err := v1.NewTasksClient(operator.conn).List(nsCtx, &v1.ListTasksRequest{})
+	if err != nil {
+		return nil, err
+	}
+	// the pid of init process of running tasks keyed by container id
+	running := make(map[string]uint32, len(tasks.Tasks))
+	for _, task := range tasks.Tasks {
+		if task != nil && task.Status == v1.TaskStatusRunning {
+			running[task.GetContainerId()] = task.GetPid()
+		}
+	}
+	if len(running) == 0 {
+		return nil, nil
+	}
+	resp, err := v1.NewContainersClient(operator.conn).List(nsCtx, &v1.ListContainersRequest{})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*CommonContainer, 0, len(running))
+	for _, c := range resp.Containers {
+		if c == nil {
+			continue
+		}
+		pid, ok := running[c.Id]
+		if !ok {
+			continue
+		}
+		res = append(res, &CommonContainer{Id: c.Id, Labels: namespaceContainerLabels(namespace, c),
+			StartedAt: taskStartedAt(c, pid)})
+	}
+	return res, nil
+}
+
+// taskStartedAt the start time of task given by its init process, since containerd does not give it. the creation
+// time of container is used when the process is not visible
+func taskStartedAt(c *v1.Container, pid uint32) time.Time {
+	startedAt, err := ProcessStartTime(pid)
+	if err == nil {
+		return startedAt
+	}
+	logger.Debugf("start time of task %s is not got, use the creation time: %v", c.Id, err)
+	if c.CreatedAt == nil {
+		return time.Time{}
+	}
+	return c.CreatedAt.AsTime()
+}
+
+func namespaceContainerLabels(namespace string, c *v1.Container) map[string]string {
+	labels := make(map[string]string, len(c.Labels))
+	for k, v := range c.Labels {
+		labels[k] = v
+	}
+	labels[labelContainerdNamespace] = namespace
+	if _, ok := labels[labelK8sPodName]; ok {
+		return labels
+	}
+	name := c.Labels[labelNerdctlName]
+	if name == "" {
+		name = shortContainerID(c.Id)
+	}
+	labels[labelK8sPodNamespace] = namespace
+	labels[labelK8sPodName] = name
+	labels[labelContainerName] = name
+	return labels
+}
+
+// namespaceOf the containerd namespace of container, the namespace of CRI when it is not listed by containerd API
+func (operator *RuntimeOperatorTool) namespaceOf(id string) string {
+	operator.nsMutex.RLock()
+	defer operator.nsMutex.RUnlock()
+	if ns, ok := operator.containerNamespaces[id]; ok {
+		return ns
+	}
+	return operator.Namespace
+}
+
+// eventNamespaces the containerd namespaces whose events are watched, the namespace of CRI and the other ones
+func (operator *RuntimeOperatorTool) eventNamespaces() []string {
+	return append([]string{operator.Namespace}, operator.Namespaces...)
+}
+
+// shortContainerID the first 12 characters of container id, as docker and nerdctl show
+func shortContainerID(id string) string {
+	if len(id) > shortIDLen {
+		return id[:shortIDLen]
+	}
+	return id
+}
@@ -0,0 +1,185 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	v1 "github.com/professorshandian/npu-exporter/collector/container/v1"
+)
+
+const (
+	mockNsDefault   = "default"
+	mockNsBuild     = "buildkit"
+	mockNerdctlID   = "nerdctl1"
+	mockNerdctlName = "web"
+	mockStoppedID   = "stopped1"
+	mockBuildID     = "0123456789abcdef0123"
+	nsEnvPrefix     = "NS="
+)
+
+// fakeContainerdServer the containerd serving namespaces, tasks and containers by the namespace of grpc header
+type fakeContainerdServer struct {
+	v1.UnimplementedNamespacesServer
+	containers map[string][]*v1.Container
+	running    map[string]bool
+}
+
+func newFakeContainerdServer() *fakeContainerdServer {
+	return &fakeContainerdServer{
+		containers: map[string][]*v1.Container{
+			namespaceK8s: {{Id: mockContainerID}},
+			mockNsDefault: {{Id: mockNerdctlID, Labels: map[string]string{labelNerdctlName: mockNerdctlName}},
+				{Id: mockStoppedID}},
+			mockNsBuild: {{Id: mockBuildID}},
+		},
+		running: map[string]bool{mockContainerID: true, mockNerdctlID: true, mockBuildID: true},
+	}
+}
+
+func namespaceOfRequest(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(grpcHeader); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (s *fakeContainerdServer) List(context.Context, *v1.ListNamespacesRequest) (*v1.ListNamespacesResponse, error) {
+	return &v1.ListNamespacesResponse{Namespaces: []*v1.Namespace{
+		{Name: namespaceK8s}, {Name: mockNsDefault}, {Name: mockNsBuild}}}, nil
+}
+
+func (s *fakeContainerdServer) Get(ctx context.Context,
+	req *v1.GetContainerRequest) (*v1.GetContainerResponse, error) {
+	spec, err := json.Marshal(v1.Spec{Process: &v1.Process{Env: []string{nsEnvPrefix + namespaceOfRequest(ctx)}}})
+	if err != nil {
+		return nil, err
+	}
+	return &v1.GetContainerResponse{Container: &v1.Container{Id: req.Id, Spec: &any.Any{Value: spec}}}, nil
+}
+
+// fakeContainersServer the containers service of fakeContainerdServer, whose List conflicts with namespaces
+type fakeContainersServer struct {
+	v1.UnimplementedContainersServer
+	*fakeContainerdServer
+}
+
+func (s fakeContainersServer) Get(ctx context.Context, req *v1.GetContainerRequest) (*v1.GetContainerResponse, error) {
+	return s.fakeContainerdServer.Get(ctx, req)
+}
+
+func (s fakeContainersServer) List(ctx context.Context,
+	_ *v1.ListContainersRequest) (*v1.ListContainersResponse, error) {
+	return &v1.ListContainersResponse{Containers: s.containers[namespaceOfRequest(ctx)]}, nil
+}
+
+// fakeTasksServer the tasks service of fakeContainerdServer
+type fakeTasksServer struct {
+	v1.UnimplementedTasksServer
+	*fakeContainerdServer
+}
+
+func (s fakeTasksServer) List(ctx context.Context, _ *v1.ListTasksRequest) (*v1.ListTasksResponse, error) {
+	var tasks []*v1.TaskProcess
+	for _, c := range s.containers[namespaceOfRequest(ctx)] {
+		status := uint32(v1.TaskStatusRunning + 1)
+		if s.running[c.Id] {
+			status = v1.TaskStatusRunning
+		}
+		tasks = append(tasks, &v1.TaskProcess{ContainerId: c.Id, Id: c.Id, Status: status})
+	}
+	return &v1.ListTasksResponse{Tasks: tasks}, nil
+}
+
+func newNamespacesOperator(t *testing.T, namespaces []string) *RuntimeOperatorTool {
+	server := newFakeContainerdServer()
+	endpoint := startFakeCriServer(t, func(s *grpc.Server) {
+		criv1.RegisterRuntimeServiceServer(s, &fakeCriV1Server{})
+		v1.RegisterNamespacesServer(s, server)
+		v1.RegisterContainersServer(s, fakeContainersServer{fakeContainerdServer: server})
+		v1.RegisterTasksServer(s, fakeTasksServer{fakeContainerdServer: server})
+	})
+	operator := &RuntimeOperatorTool{CriEndpoint: endpoint, OciEndpoint: endpoint, Namespace: namespaceK8s,
+		Namespaces: namespaces}
+	if err := operator.initCriClient(); err != nil {
+		t.Fatal(err)
+	}
+	if err := operator.initOciClient(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = operator.Close() })
+	return operator
+}
+
+func containerIDs(containers []*CommonContainer) []string {
+	ids := make([]string, 0, len(containers))
+	for _, c := range containers {
+		ids = append(ids, c.Id)
+	}
+	return ids
+}
+
+// TestGetNamespacesContainers test listing the running containers of the other containerd namespaces
+func TestGetNamespacesContainers(t *testing.T) {
+	convey.Convey("TestGetNamespacesContainers", t, func() {
+		convey.Convey("the configured namespaces are listed besides CRI", func() {
+			operator := newNamespacesOperator(t, []string{mockNsDefault})
+			containers, err := operator.GetContainers(context.Background())
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(containerIDs(containers), convey.ShouldResemble, []string{mockContainerID, mockNerdctlID})
+			convey.So(containers[1].Labels, convey.ShouldResemble, map[string]string{
+				labelNerdctlName: mockNerdctlName, labelContainerdNamespace: mockNsDefault,
+				labelK8sPodNamespace: mockNsDefault, labelK8sPodName: mockNerdctlName,
+				labelContainerName: mockNerdctlName})
+
+			spec, err := operator.GetContainerInfoByID(context.Background(), mockNerdctlID)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(spec.Process.Env, convey.ShouldResemble, []string{nsEnvPrefix + mockNsDefault})
+			spec, err = operator.GetContainerInfoByID(context.Background(), mockContainerID)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(spec.Process.Env, convey.ShouldResemble, []string{nsEnvPrefix + namespaceK8s})
+		})
+		convey.Convey("all namespaces are discovered and the namespace of CRI is skipped", func() {
+			operator := newNamespacesOperator(t, []string{AllNamespaces})
+			containers, err := operator.GetContainers(context.Background())
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(containerIDs(containers), convey.ShouldResemble,
+				[]string{mockContainerID, mockNerdctlID, mockBuildID})
+			convey.So(containers[2].Labels[labelK8sPodName], convey.ShouldEqual, mockBuildID[:shortIDLen])
+			convey.So(operator.namespaceOf(mockBuildID), convey.ShouldEqual, mockNsBuild)
+		})
+	})
+}
+
+// TestContainerdEventFilters test the event filters of multiple namespaces
+func TestContainerdEventFilters(t *testing.T) {
+	convey.Convey("TestContainerdEventFilters", t, func() {
+		filters := containerdEventFilters([]string{namespaceK8s, mockNsDefault})
+		convey.So(filters, convey.ShouldContain, `namespace==default,topic=="/tasks/start"`)
+		convey.So(filters, convey.ShouldHaveLength, 6)
+		convey.So(containerdEventFilters([]string{namespaceK8s, AllNamespaces}), convey.ShouldResemble,
+			[]string{`topic=="/tasks/start"`, `topic=="/tasks/exit"`, `topic=="/containers/delete"`})
+	})
+}
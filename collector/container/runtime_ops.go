@@ -21,12 +21,17 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/hwlog"
 	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/utils"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
 	"k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
 
 	"github.com/professorshandian/npu-exporter/collector/container/isula"
@@ -47,6 +52,12 @@ const (
 	DefaultCRIDockerd = "unix:///run/cri-dockerd.sock"
 	// DefaultContainerdAddr default containerd sock address
 	DefaultContainerdAddr = "unix:///run/containerd/containerd.sock"
+	// DefaultCrioAddr default CRI-O sock address
+	DefaultCrioAddr = "unix:///var/run/crio/crio.sock"
+	// DefaultPodmanAddr default podman REST API sock address
+	DefaultPodmanAddr = "unix:///run/podman/podman.sock"
+	// DefaultDockerEngineAddr default docker engine API sock address
+	DefaultDockerEngineAddr = "unix:///var/run/docker.sock"
 	// DefaultDockerAddr default docker containerd sock address
 	DefaultDockerAddr    = "unix:///run/docker/containerd/docker-containerd.sock"
 	defaultDockerOnEuler = "unix:///run/docker/containerd/containerd.sock"
@@ -55,6 +66,12 @@ const (
 
 	// IsulaContainer represents isula container type
 	IsulaContainer = "isula"
+	// CrioContainer represents CRI-O container type
+	CrioContainer = "crio"
+	// PodmanContainer represents podman container type
+	PodmanContainer = "podman"
+	// DockerEngineContainer represents docker container got from docker engine API
+	DockerEngineContainer = "docker-engine"
 	// DefaultContainer represents default container type
 	DefaultContainer = "docker-containerd"
 )
@@ -63,6 +80,21 @@ const (
 type CommonContainer struct {
 	Id     string
 	Labels map[string]string
+	// Annotations of the container, the labels and annotations of pod are merged in when they can be got
+	Annotations map[string]string
+	// StartedAt the start time of container given by runtime, the creation time is used when the runtime only
+	// gives it, zero when unknown
+	StartedAt time.Time
+	// PodStartedAt the creation time of the pod sandbox of container, zero when unknown
+	PodStartedAt time.Time
+}
+
+// timeOfUnixNano the time of unix nanoseconds given by runtime, zero time when it is not given
+func timeOfUnixNano(nano int64) time.Time {
+	if nano <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nano)
 }
 
 // RuntimeOperator wraps operations against container runtime
@@ -87,12 +119,43 @@ type RuntimeOperatorTool struct {
 	OciEndpoint string
 	// Namespace the namespace of containerd
 	Namespace string
+	// Namespaces the other containerd namespaces whose running containers are listed by containerd API besides
+	// the containers of CRI, AllNamespaces discovers all namespaces of containerd
+	Namespaces []string
 	// UseBackup use back up address or not
 	UseBackup bool
+	// CriEvents watch the container events of CRI when the containerd API is not connected, the runtime may not
+	// broadcast them and the events taken by the exporter are lost by kubelet Evented PLEG
+	CriEvents bool
+
+	// criMutex guards criClient, which falls back to v1alpha2 while the events may be watched
+	criMutex sync.RWMutex
+	nsMutex  sync.RWMutex
+	// containerNamespaces the namespace of containers listed by containerd API, keyed by container id
+	containerNamespaces map[string]string
 }
 
 // Init initializes container runtime operator
 func (operator *RuntimeOperatorTool) Init() error {
+	return runAsRoot(func() error {
+		if err := sockCheck(operator); err != nil {
+			hwlog.RunLog.Error("check socket path failed")
+			return err
+		}
+
+		if err := operator.initCriClient(); err != nil {
+			return fmt.Errorf("init CRI client failed, %s", err)
+		}
+
+		if err := operator.initOciClient(); err != nil {
+			return fmt.Errorf("init OCI client failed, %s", err)
+		}
+		return nil
+	})
+}
+
+// runAsRoot raise uid to root to connect the sockets of runtime, and recover it after fn returns
+func runAsRoot(fn func() error) error {
 	start := syscall.Getuid()
 	logger.Debugf("the init uid is:%d", start)
 	if start != 0 {
@@ -109,19 +172,7 @@ func (operator *RuntimeOperatorTool) Init() error {
 			logger.Debugf("recover uid to:%d", start)
 		}()
 	}
-	if err := sockCheck(operator); err != nil {
-		hwlog.RunLog.Error("check socket path failed")
-		return err
-	}
-
-	if err := operator.initCriClient(); err != nil {
-		return fmt.Errorf("init CRI client failed, %s", err)
-	}
-
-	if err := operator.initOciClient(); err != nil {
-		return fmt.Errorf("init OCI client failed, %s", err)
-	}
-	return nil
+	return fn()
 }
 
 func (operator *RuntimeOperatorTool) initCriClient() error {
@@ -141,12 +192,32 @@ func (operator *RuntimeOperatorTool) initCriClient() error {
 	if operator.CriEndpoint == DefaultIsuladAddr {
 		operator.criClient = isula.NewRuntimeServiceClient(criConn)
 	} else {
-		operator.criClient = v1alpha2.NewRuntimeServiceClient(criConn)
+		operator.criClient = negotiateCriClient(criConn)
 	}
 	operator.criConn = criConn
 	return nil
 }
 
+// negotiateCriClient negotiate the CRI api version with runtime, runtime.v1 first and v1alpha2 as fallback.
+// the v1 client is used when neither version answers, and it falls back when listing containers is unimplemented
+func negotiateCriClient(conn *grpc.ClientConn) interface{} {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	v1Client := criv1.NewRuntimeServiceClient(conn)
+	_, err := v1Client.Version(ctx, &criv1.VersionRequest{})
+	if err == nil {
+		logger.Info("use CRI api runtime.v1")
+		return v1Client
+	}
+	alphaClient := v1alpha2.NewRuntimeServiceClient(conn)
+	if _, alphaErr := alphaClient.Version(ctx, &v1alpha2.VersionRequest{}); alphaErr == nil {
+		logger.Infof("CRI api runtime.v1 is not available (%v), use runtime.v1alpha2", err)
+		return alphaClient
+	}
+	logger.Warnf("negotiate CRI api version failed, use runtime.v1 by default: %v", err)
+	return v1Client
+}
+
 func (operator *RuntimeOperatorTool) initOciClient() error {
 	conn, err := GetConnection(operator.OciEndpoint)
 	if err != nil || conn == nil {
@@ -196,22 +267,56 @@ func (operator *RuntimeOperatorTool) Close() error {
 	return nil
 }
 
-// GetContainers returns all containers' IDs
+// GetContainers returns all containers' IDs, including the running containers of the other containerd namespaces
 func (operator *RuntimeOperatorTool) GetContainers(ctx context.Context) ([]*CommonContainer, error) {
-	if utils.IsNil(operator.criClient) || operator.criConn == nil {
+	containers, err := operator.getCriContainers(ctx)
+	if err != nil || len(operator.Namespaces) == 0 {
+		return containers, err
+	}
+	return append(containers, operator.getNamespacesContainers(ctx)...), nil
+}
+
+func (operator *RuntimeOperatorTool) getCriContainers(ctx context.Context) ([]*CommonContainer, error) {
+	criClient := operator.getCriClient()
+	if utils.IsNil(criClient) || operator.criConn == nil {
 		return nil, errors.New("criClient is empty")
 	}
-	if client, ok := operator.criClient.(v1alpha2.RuntimeServiceClient); ok {
+	if client, ok := criClient.(criv1.RuntimeServiceClient); ok {
+		containers, err := getContainersByCriV1(ctx, client)
+		if status.Code(err) != codes.Unimplemented {
+			return containers, err
+		}
+		criClient = operator.fallbackCriClient(client)
+	}
+	if client, ok := criClient.(v1alpha2.RuntimeServiceClient); ok {
 		return getContainersByContainerd(ctx, client)
 	}
-	if client, ok := operator.criClient.(isula.RuntimeServiceClient); ok {
-		return getContainersByIsulad(ctx, client)
+	if client, ok := criClient.(isula.RuntimeServiceClient); ok {
+		return getContainersByIsulad(ctx, client, v1alpha2.NewRuntimeServiceClient(operator.criConn))
 	}
 
-	logger.Errorf("client %v is unexpected", operator.criClient)
+	logger.Errorf("client %v is unexpected", criClient)
 	return nil, errors.New("unexpected client type")
 }
 
+func (operator *RuntimeOperatorTool) getCriClient() interface{} {
+	operator.criMutex.RLock()
+	defer operator.criMutex.RUnlock()
+	return operator.criClient
+}
+
+// fallbackCriClient replace the runtime.v1 client unimplemented by runtime with runtime.v1alpha2, it is done only
+// once even if several callers find the v1 client unimplemented
+func (operator *RuntimeOperatorTool) fallbackCriClient(unimplemented criv1.RuntimeServiceClient) interface{} {
+	operator.criMutex.Lock()
+	defer operator.criMutex.Unlock()
+	if client, ok := operator.criClient.(criv1.RuntimeServiceClient); ok && client == unimplemented {
+		logger.Warn("CRI api runtime.v1 is unimplemented by runtime, fall back to runtime.v1alpha2")
+		operator.criClient = v1alpha2.NewRuntimeServiceClient(operator.criConn)
+	}
+	return operator.criClient
+}
+
 // GetContainerInfoByID use oci interface to get container
 func (operator *RuntimeOperatorTool) GetContainerInfoByID(ctx context.Context, id string) (v1.Spec, error) {
 	if utils.IsNil(operator.client) || operator.conn == nil {
@@ -220,7 +325,7 @@ func (operator *RuntimeOperatorTool) GetContainerInfoByID(ctx context.Context, i
 
 	s := v1.Spec{}
 	if client, ok := operator.client.(v1.ContainersClient); ok {
-		resp, err := client.Get(setGrpcNamespaceHeader(ctx, operator.Namespace), &v1.GetContainerRequest{
+		resp, err := client.Get(setGrpcNamespaceHeader(ctx, operator.namespaceOf(id)), &v1.GetContainerRequest{
 			Id: id,
 		})
 		if err != nil {
@@ -274,7 +379,7 @@ func (operator *RuntimeOperatorTool) GetContainerType() string {
 type nsKey struct{}
 
 func setGrpcNamespaceHeader(ctx context.Context, namespace string) context.Context {
-	context.WithValue(ctx, nsKey{}, namespace)
+	ctx = context.WithValue(ctx, nsKey{}, namespace)
 	ns := metadata.Pairs(grpcHeader, namespace)
 	md, ok := metadata.FromOutgoingContext(ctx)
 	if !ok {
@@ -285,6 +390,51 @@ func setGrpcNamespaceHeader(ctx context.Context, namespace string) context.Conte
 	return metadata.NewOutgoingContext(ctx, md)
 }
 
+func getContainersByCriV1(ctx context.Context, client criv1.RuntimeServiceClient) ([]*CommonContainer, error) {
+	var allContainers []*CommonContainer
+	r, err := client.ListContainers(ctx, genCriV1ContainerRequest())
+	if err != nil {
+		hwlog.RunLog.Error(err)
+		return nil, err
+	}
+	sandboxes := getCriV1PodSandboxes(ctx, client)
+	for _, container := range r.Containers {
+		commonContainer := &CommonContainer{
+			Id:          container.Id,
+			Labels:      container.Labels,
+			Annotations: container.Annotations,
+			StartedAt:   timeOfUnixNano(container.CreatedAt),
+		}
+		if sandbox, ok := sandboxes[container.PodSandboxId]; ok {
+			commonContainer.Labels = mergeMetadata(sandbox.Labels, container.Labels)
+			commonContainer.Annotations = mergeMetadata(sandbox.Annotations, container.Annotations)
+			commonContainer.PodStartedAt = timeOfUnixNano(sandbox.CreatedAt)
+		}
+		allContainers = append(allContainers, commonContainer)
+	}
+	return allContainers, nil
+}
+
+// getCriV1PodSandboxes get the ready pod sandboxes keyed by id over CRI runtime.v1, same as getPodSandboxes
+func getCriV1PodSandboxes(ctx context.Context, client criv1.RuntimeServiceClient) map[string]*criv1.PodSandbox {
+	sandboxes := make(map[string]*criv1.PodSandbox)
+	r, err := client.ListPodSandbox(ctx, &criv1.ListPodSandboxRequest{
+		Filter: &criv1.PodSandboxFilter{
+			State: &criv1.PodSandboxStateValue{State: criv1.PodSandboxState_SANDBOX_READY},
+		},
+	})
+	if err != nil {
+		logger.Warnf("list pod sandbox failed, the metadata of pod is not available: %v", err)
+		return sandboxes
+	}
+	for _, sandbox := range r.Items {
+		if sandbox != nil {
+			sandboxes[sandbox.Id] = sandbox
+		}
+	}
+	return sandboxes
+}
+
 func getContainersByContainerd(ctx context.Context, client v1alpha2.RuntimeServiceClient) ([]*CommonContainer, error) {
 	var allContainers []*CommonContainer
 	request := genContainerRequest()
@@ -293,16 +443,61 @@ func getContainersByContainerd(ctx context.Context, client v1alpha2.RuntimeServi
 		hwlog.RunLog.Error(err)
 		return nil, err
 	}
+	sandboxes := getPodSandboxes(ctx, client)
 	for _, container := range r.Containers {
-		allContainers = append(allContainers, &CommonContainer{
-			Id:     container.Id,
-			Labels: container.Labels,
-		})
+		commonContainer := &CommonContainer{
+			Id:          container.Id,
+			Labels:      container.Labels,
+			Annotations: container.Annotations,
+			StartedAt:   timeOfUnixNano(container.CreatedAt),
+		}
+		if sandbox, ok := sandboxes[container.PodSandboxId]; ok {
+			commonContainer.Labels = mergeMetadata(sandbox.Labels, container.Labels)
+			commonContainer.Annotations = mergeMetadata(sandbox.Annotations, container.Annotations)
+			commonContainer.PodStartedAt = timeOfUnixNano(sandbox.CreatedAt)
+		}
+		allContainers = append(allContainers, commonContainer)
 	}
 	return allContainers, nil
 }
 
-func getContainersByIsulad(ctx context.Context, client isula.RuntimeServiceClient) ([]*CommonContainer, error) {
+// getPodSandboxes get the ready pod sandboxes keyed by id, the pod labels and annotations are kept in sandbox.
+// the containers are still usable without them, so error is only logged
+func getPodSandboxes(ctx context.Context, client v1alpha2.RuntimeServiceClient) map[string]*v1alpha2.PodSandbox {
+	sandboxes := make(map[string]*v1alpha2.PodSandbox)
+	r, err := client.ListPodSandbox(ctx, &v1alpha2.ListPodSandboxRequest{
+		Filter: &v1alpha2.PodSandboxFilter{
+			State: &v1alpha2.PodSandboxStateValue{State: v1alpha2.PodSandboxState_SANDBOX_READY},
+		},
+	})
+	if err != nil {
+		logger.Warnf("list pod sandbox failed, the metadata of pod is not available: %v", err)
+		return sandboxes
+	}
+	for _, sandbox := range r.Items {
+		if sandbox != nil {
+			sandboxes[sandbox.Id] = sandbox
+		}
+	}
+	return sandboxes
+}
+
+// mergeMetadata merge the metadata of pod and container, the container one wins when the key is the same
+func mergeMetadata(pod, container map[string]string) map[string]string {
+	res := make(map[string]string, len(pod)+len(container))
+	for k, v := range pod {
+		res[k] = v
+	}
+	for k, v := range container {
+		res[k] = v
+	}
+	return res
+}
+
+// getContainersByIsulad list the containers of isulad, the pod sandboxes are listed by sandboxClient because
+// isulad serves them in runtime.v1alpha2 which the isula client does not cover
+func getContainersByIsulad(ctx context.Context, client isula.RuntimeServiceClient,
+	sandboxClient v1alpha2.RuntimeServiceClient) ([]*CommonContainer, error) {
 	var allContainers []*CommonContainer
 	request := genIsulaRequest()
 	r, err := client.ListContainers(ctx, request)
@@ -310,11 +505,20 @@ func getContainersByIsulad(ctx context.Context, client isula.RuntimeServiceClien
 		hwlog.RunLog.Error(err)
 		return nil, err
 	}
+	sandboxes := getPodSandboxes(ctx, sandboxClient)
 	for _, container := range r.Containers {
-		allContainers = append(allContainers, &CommonContainer{
-			Id:     container.Id,
-			Labels: container.Labels,
-		})
+		commonContainer := &CommonContainer{
+			Id:          container.Id,
+			Labels:      container.Labels,
+			Annotations: container.Annotations,
+			StartedAt:   timeOfUnixNano(container.CreatedAt),
+		}
+		if sandbox, ok := sandboxes[container.PodSandboxId]; ok {
+			commonContainer.Labels = mergeMetadata(sandbox.Labels, container.Labels)
+			commonContainer.Annotations = mergeMetadata(sandbox.Annotations, container.Annotations)
+			commonContainer.PodStartedAt = timeOfUnixNano(sandbox.CreatedAt)
+		}
+		allContainers = append(allContainers, commonContainer)
 	}
 	return allContainers, nil
 }
@@ -330,6 +534,14 @@ func genContainerRequest() *v1alpha2.ListContainersRequest {
 	return request
 }
 
+func genCriV1ContainerRequest() *criv1.ListContainersRequest {
+	return &criv1.ListContainersRequest{
+		Filter: &criv1.ContainerFilter{
+			State: &criv1.ContainerStateValue{State: criv1.ContainerState_CONTAINER_RUNNING},
+		},
+	}
+}
+
 func genIsulaRequest() *isula.ListContainersRequest {
 	filter := &isula.ContainerFilter{}
 	st := &isula.ContainerStateValue{}
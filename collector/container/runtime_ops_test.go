@@ -0,0 +1,220 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+	"k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/hwlog"
+	"github.com/professorshandian/npu-exporter/collector/container/isula"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	mockContainerID = "container-1"
+	mockSandboxID   = "sandbox-1"
+	mockPodName     = "pod-1"
+	mockJobKey      = "volcano.sh/job-name"
+	mockJobName     = "job-1"
+	mockRankAnno    = "hccl/rankIndex"
+	mockRankIndex   = "0"
+	mockStartedAt   = 1700000000
+	// concurrentCalls the number of goroutines listing containers at the same time
+	concurrentCalls = 4
+)
+
+func init() {
+	logger.HwLogConfig = &hwlog.LogConfig{
+		OnlyToStdout: true,
+	}
+	logger.InitLogger("Prometheus")
+}
+
+// fakeCriV1Server the CRI runtime.v1 server of runtime such as containerd 2.x
+type fakeCriV1Server struct {
+	criv1.UnimplementedRuntimeServiceServer
+}
+
+func (s *fakeCriV1Server) Version(context.Context, *criv1.VersionRequest) (*criv1.VersionResponse, error) {
+	return &criv1.VersionResponse{RuntimeApiVersion: "v1"}, nil
+}
+
+func (s *fakeCriV1Server) ListContainers(_ context.Context,
+	req *criv1.ListContainersRequest) (*criv1.ListContainersResponse, error) {
+	if req.GetFilter().GetState().GetState() != criv1.ContainerState_CONTAINER_RUNNING {
+		return &criv1.ListContainersResponse{}, nil
+	}
+	return &criv1.ListContainersResponse{Containers: []*criv1.Container{{
+		Id:           mockContainerID,
+		PodSandboxId: mockSandboxID,
+		Labels:       map[string]string{labelK8sPodName: mockPodName},
+		CreatedAt:    time.Unix(mockStartedAt, 0).UnixNano(),
+	}}}, nil
+}
+
+func (s *fakeCriV1Server) ListPodSandbox(context.Context,
+	*criv1.ListPodSandboxRequest) (*criv1.ListPodSandboxResponse, error) {
+	return &criv1.ListPodSandboxResponse{Items: []*criv1.PodSandbox{{
+		Id:          mockSandboxID,
+		Labels:      map[string]string{mockJobKey: mockJobName},
+		Annotations: map[string]string{mockRankAnno: mockRankIndex},
+		CreatedAt:   time.Unix(mockStartedAt-1, 0).UnixNano(),
+	}}}, nil
+}
+
+// fakeCriV1alpha2Server the CRI server of runtime only supporting v1alpha2, such as containerd 1.5
+type fakeCriV1alpha2Server struct {
+	v1alpha2.UnimplementedRuntimeServiceServer
+}
+
+func (s *fakeCriV1alpha2Server) Version(context.Context,
+	*v1alpha2.VersionRequest) (*v1alpha2.VersionResponse, error) {
+	return &v1alpha2.VersionResponse{RuntimeApiVersion: "v1alpha2"}, nil
+}
+
+func (s *fakeCriV1alpha2Server) ListContainers(_ context.Context,
+	req *v1alpha2.ListContainersRequest) (*v1alpha2.ListContainersResponse, error) {
+	if req.GetFilter().GetState().GetState() != v1alpha2.ContainerState_CONTAINER_RUNNING {
+		return &v1alpha2.ListContainersResponse{}, nil
+	}
+	return &v1alpha2.ListContainersResponse{Containers: []*v1alpha2.Container{{
+		Id:           mockContainerID,
+		PodSandboxId: mockSandboxID,
+		Labels:       map[string]string{labelK8sPodName: mockPodName},
+	}}}, nil
+}
+
+func (s *fakeCriV1alpha2Server) ListPodSandbox(context.Context,
+	*v1alpha2.ListPodSandboxRequest) (*v1alpha2.ListPodSandboxResponse, error) {
+	return &v1alpha2.ListPodSandboxResponse{Items: []*v1alpha2.PodSandbox{{
+		Id:          mockSandboxID,
+		Labels:      map[string]string{mockJobKey: mockJobName},
+		Annotations: map[string]string{mockRankAnno: mockRankIndex},
+	}}}, nil
+}
+
+// startFakeCriServer start a local CRI grpc server on unix socket, return its endpoint
+func startFakeCriServer(t *testing.T, register func(s *grpc.Server)) string {
+	sock := filepath.Join(t.TempDir(), "cri.sock")
+	listener, err := net.Listen(unixPrefix, sock)
+	if err != nil {
+		t.Fatalf("listen on %s failed: %v", sock, err)
+	}
+	server := grpc.NewServer()
+	register(server)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			t.Logf("fake CRI server stopped: %v", err)
+		}
+	}()
+	t.Cleanup(server.Stop)
+	return unixPre + sock
+}
+
+func newOperatorForTest(endpoint string) (*RuntimeOperatorTool, error) {
+	operator := &RuntimeOperatorTool{CriEndpoint: endpoint}
+	if err := operator.initCriClient(); err != nil {
+		return nil, err
+	}
+	return operator, nil
+}
+
+func shouldHaveMergedMetadata(containers []*CommonContainer) {
+	convey.So(containers, convey.ShouldHaveLength, 1)
+	convey.So(containers[0].Id, convey.ShouldEqual, mockContainerID)
+	convey.So(containers[0].Labels, convey.ShouldResemble,
+		map[string]string{labelK8sPodName: mockPodName, mockJobKey: mockJobName})
+	convey.So(containers[0].Annotations, convey.ShouldResemble, map[string]string{mockRankAnno: mockRankIndex})
+}
+
+// TestGetContainersOverCriV1 test the runtime supporting CRI runtime.v1
+func TestGetContainersOverCriV1(t *testing.T) {
+	endpoint := startFakeCriServer(t, func(s *grpc.Server) {
+		criv1.RegisterRuntimeServiceServer(s, &fakeCriV1Server{})
+	})
+	convey.Convey("TestGetContainersOverCriV1", t, func() {
+		operator, err := newOperatorForTest(endpoint)
+		convey.So(err, convey.ShouldBeNil)
+		defer operator.criConn.Close()
+		convey.Convey("runtime.v1 is negotiated", func() {
+			_, ok := operator.criClient.(criv1.RuntimeServiceClient)
+			convey.So(ok, convey.ShouldBeTrue)
+		})
+		convey.Convey("the containers are listed with the metadata of pod", func() {
+			containers, err := operator.GetContainers(context.Background())
+			convey.So(err, convey.ShouldBeNil)
+			shouldHaveMergedMetadata(containers)
+			convey.So(containers[0].StartedAt.Unix(), convey.ShouldEqual, mockStartedAt)
+			convey.So(containers[0].PodStartedAt.Unix(), convey.ShouldEqual, mockStartedAt-1)
+		})
+	})
+}
+
+// TestGetContainersOverCriV1alpha2 test the runtime only supporting CRI runtime.v1alpha2
+func TestGetContainersOverCriV1alpha2(t *testing.T) {
+	endpoint := startFakeCriServer(t, func(s *grpc.Server) {
+		v1alpha2.RegisterRuntimeServiceServer(s, &fakeCriV1alpha2Server{})
+	})
+	convey.Convey("TestGetContainersOverCriV1alpha2", t, func() {
+		operator, err := newOperatorForTest(endpoint)
+		convey.So(err, convey.ShouldBeNil)
+		defer operator.criConn.Close()
+		convey.Convey("fall back to v1alpha2 when runtime.v1 is unimplemented", func() {
+			_, ok := operator.criClient.(v1alpha2.RuntimeServiceClient)
+			convey.So(ok, convey.ShouldBeTrue)
+		})
+		convey.Convey("the containers are listed with the metadata of pod", func() {
+			containers, err := operator.GetContainers(context.Background())
+			convey.So(err, convey.ShouldBeNil)
+			shouldHaveMergedMetadata(containers)
+		})
+		convey.Convey("the containers of isulad are listed with the metadata of pod", func() {
+			containers, err := getContainersByIsulad(context.Background(),
+				isula.NewRuntimeServiceClient(operator.criConn), v1alpha2.NewRuntimeServiceClient(operator.criConn))
+			convey.So(err, convey.ShouldBeNil)
+			shouldHaveMergedMetadata(containers)
+		})
+		convey.Convey("fall back to v1alpha2 when listing containers over runtime.v1 is unimplemented", func() {
+			operator.criClient = criv1.NewRuntimeServiceClient(operator.criConn)
+			var wg sync.WaitGroup
+			errs := make([]error, concurrentCalls)
+			for i := range errs {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					_, errs[i] = operator.GetContainers(context.Background())
+				}(i)
+			}
+			wg.Wait()
+			convey.So(errs, convey.ShouldResemble, make([]error, concurrentCalls))
+			containers, err := operator.GetContainers(context.Background())
+			convey.So(err, convey.ShouldBeNil)
+			shouldHaveMergedMetadata(containers)
+			_, ok := operator.getCriClient().(v1alpha2.RuntimeServiceClient)
+			convey.So(ok, convey.ShouldBeTrue)
+		})
+	})
+}
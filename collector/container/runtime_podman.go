@@ -0,0 +1,147 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/hwlog"
+	"github.com/professorshandian/npu-exporter/collector/container/isula"
+	v1 "github.com/professorshandian/npu-exporter/collector/container/v1"
+)
+
+const (
+	// podmanHost the host in url of podman API, the request is always sent to the unix socket
+	podmanHost          = "http://podman"
+	podmanPingPath      = "/libpod/_ping"
+	podmanListPath      = "/libpod/containers/json"
+	podmanInspectPath   = "/libpod/containers/%s/json"
+	podmanRunningFilter = `{"status":["running"]}`
+	podmanAPIName       = "podman"
+
+	// podmanNamespace the namespace of standalone podman container, which has no kubernetes labels
+	podmanNamespace = "podman"
+)
+
+// podmanContainer the container listed by podman API
+type podmanContainer struct {
+	ID      string            `json:"Id"`
+	Names   []string          `json:"Names"`
+	Labels  map[string]string `json:"Labels"`
+	PodName string            `json:"PodName"`
+	// StartedAt the unix seconds the container is started
+	StartedAt int64 `json:"StartedAt"`
+}
+
+// podmanInspect the container inspected by podman API, only env and devices are used
+type podmanInspect struct {
+	Config *struct {
+		Env         []string          `json:"Env"`
+		Annotations map[string]string `json:"Annotations"`
+	} `json:"Config"`
+	HostConfig *struct {
+		Devices    []restDevice `json:"Devices"`
+		Privileged bool         `json:"Privileged"`
+	} `json:"HostConfig"`
+}
+
+// PodmanOperatorTool implements RuntimeOperator interface for podman, the containers and their spec are got
+// from the REST API of podman over its unix socket
+type PodmanOperatorTool struct {
+	client *restClient
+	// Endpoint podman API server endpoint
+	Endpoint string
+}
+
+// Init initializes the client of podman API and checks it is reachable
+func (operator *PodmanOperatorTool) Init() error {
+	return runAsRoot(func() error {
+		client, err := newRestClient(operator.Endpoint, podmanHost, podmanAPIName, podmanPingPath)
+		if err != nil {
+			return err
+		}
+		operator.client = client
+		return nil
+	})
+}
+
+// Close closes the idle connections to podman API
+func (operator *PodmanOperatorTool) Close() error {
+	operator.client.close()
+	return nil
+}
+
+// GetContainers returns the running containers of podman
+func (operator *PodmanOperatorTool) GetContainers(ctx context.Context) ([]*CommonContainer, error) {
+	if operator.client == nil {
+		return nil, errors.New("podman client is empty")
+	}
+	var containers []podmanContainer
+	path := podmanListPath + "?filters=" + url.QueryEscape(podmanRunningFilter)
+	if err := operator.client.get(ctx, path, &containers); err != nil {
+		hwlog.RunLog.Error(err)
+		return nil, err
+	}
+	allContainers := make([]*CommonContainer, 0, len(containers))
+	for _, container := range containers {
+		allContainers = append(allContainers, &CommonContainer{
+			Id:        container.ID,
+			Labels:    podmanLabels(container),
+			StartedAt: timeOfUnix(container.StartedAt),
+		})
+	}
+	return allContainers, nil
+}
+
+// podmanLabels the labels of podman container, the kubernetes labels are filled for standalone container:
+// the namespace is podman, the pod is the podman pod, or the container itself when it is not in a pod
+func podmanLabels(container podmanContainer) map[string]string {
+	name := container.ID
+	if len(container.Names) > 0 {
+		name = strings.TrimPrefix(container.Names[0], "/")
+	}
+	return standaloneLabels(container.Labels, podmanNamespace, container.PodName, name)
+}
+
+// GetContainerInfoByID build the OCI spec with env and linux devices from the inspected container
+func (operator *PodmanOperatorTool) GetContainerInfoByID(ctx context.Context, id string) (v1.Spec, error) {
+	if operator.client == nil {
+		return v1.Spec{}, errors.New("podman client is empty")
+	}
+	inspect := podmanInspect{}
+	if err := operator.client.get(ctx, fmt.Sprintf(podmanInspectPath, url.PathEscape(id)), &inspect); err != nil {
+		hwlog.RunLog.Error("call podman inspect API failed")
+		return v1.Spec{}, err
+	}
+	if inspect.Config == nil || inspect.HostConfig == nil {
+		return v1.Spec{}, errors.New("empty container info")
+	}
+	return specOfRestContainer(inspect.Config.Env, inspect.HostConfig.Devices, inspect.HostConfig.Privileged), nil
+}
+
+// GetIsulaContainerInfoByID not supported by podman
+func (operator *PodmanOperatorTool) GetIsulaContainerInfoByID(context.Context, string) (isula.ContainerJson, error) {
+	return isula.ContainerJson{}, errors.New("not supported by podman")
+}
+
+// GetContainerType return container type
+func (operator *PodmanOperatorTool) GetContainerType() string {
+	return PodmanContainer
+}
@@ -0,0 +1,112 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	mockPodmanList = `[{"Id":"podman-1","Names":["infer"],"Labels":{"app":"infer"},"PodName":"",` +
+		`"StartedAt":1700000000},` +
+		`{"Id":"podman-2","Names":["worker"],"Labels":{},"PodName":"pod-a"}]`
+	mockPodmanInspect = `{"Config":{"Env":["PATH=/usr/bin"]},"HostConfig":{"Privileged":false,` +
+		`"Devices":[{"PathOnHost":"/dev/null","PathInContainer":"/dev/davinci0","CgroupPermissions":"rwm"},` +
+		`{"PathOnHost":"/not/exist","PathInContainer":"/dev/davinci1","CgroupPermissions":"rwm"}]}}`
+	mockPrivilegedInspect = `{"Config":{"Env":[]},"HostConfig":{"Privileged":true,` +
+		`"Devices":[{"PathOnHost":"/dev/null","PathInContainer":"/dev/davinci0"}]}}`
+	devNullMajor = 1
+	devNullMinor = 3
+)
+
+// startFakePodmanServer start a local podman API server on unix socket, return its endpoint
+func startFakePodmanServer(t *testing.T) string {
+	sock := filepath.Join(t.TempDir(), "podman.sock")
+	listener, err := net.Listen(unixPrefix, sock)
+	if err != nil {
+		t.Fatalf("listen on %s failed: %v", sock, err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(podmanPingPath, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("OK"))
+	})
+	mux.HandleFunc(podmanListPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("filters") != podmanRunningFilter {
+			_, _ = w.Write([]byte("[]"))
+			return
+		}
+		_, _ = w.Write([]byte(mockPodmanList))
+	})
+	mux.HandleFunc("/libpod/containers/podman-1/json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(mockPodmanInspect))
+	})
+	mux.HandleFunc("/libpod/containers/podman-2/json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(mockPrivilegedInspect))
+	})
+	server := &http.Server{Handler: mux}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			t.Logf("fake podman server stopped: %v", err)
+		}
+	}()
+	t.Cleanup(func() { _ = server.Close() })
+	return unixPre + sock
+}
+
+// TestPodmanOperator test getting containers and their spec from podman API
+func TestPodmanOperator(t *testing.T) {
+	endpoint := startFakePodmanServer(t)
+	convey.Convey("TestPodmanOperator", t, func() {
+		operator := &PodmanOperatorTool{Endpoint: endpoint}
+		convey.So(operator.Init(), convey.ShouldBeNil)
+		defer operator.Close()
+		convey.Convey("the kubernetes labels are filled for standalone containers", func() {
+			containers, err := operator.GetContainers(context.Background())
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(containers, convey.ShouldHaveLength, 2)
+			convey.So(containers[0].Labels, convey.ShouldResemble, map[string]string{"app": "infer",
+				labelK8sPodNamespace: podmanNamespace, labelK8sPodName: "infer", labelContainerName: "infer"})
+			convey.So(containers[1].Labels[labelK8sPodName], convey.ShouldEqual, "pod-a")
+			convey.So(containers[1].Labels[labelContainerName], convey.ShouldEqual, "worker")
+			convey.So(containers[0].StartedAt.Unix(), convey.ShouldEqual, mockStartedAt)
+		})
+		convey.Convey("the devices in spec are the char devices on host", func() {
+			spec, err := operator.GetContainerInfoByID(context.Background(), "podman-1")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(spec.Process.Env, convey.ShouldResemble, []string{"PATH=/usr/bin"})
+			devices := spec.Linux.Resources.Devices
+			convey.So(devices, convey.ShouldHaveLength, 1)
+			convey.So(*devices[0].Major, convey.ShouldEqual, devNullMajor)
+			convey.So(*devices[0].Minor, convey.ShouldEqual, devNullMinor)
+			convey.So(devices[0].Type, convey.ShouldEqual, charDevice)
+		})
+		convey.Convey("the devices of privileged container are not monitored", func() {
+			spec, err := operator.GetContainerInfoByID(context.Background(), "podman-2")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(spec.Linux.Resources.Devices, convey.ShouldBeEmpty)
+		})
+		convey.Convey("failed when the container is not found", func() {
+			_, err := operator.GetContainerInfoByID(context.Background(), "podman-3")
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+	})
+}
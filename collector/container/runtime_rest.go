@@ -0,0 +1,175 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/hwlog"
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/utils"
+	v1 "github.com/professorshandian/npu-exporter/collector/container/v1"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const maxRestRespBytes = 20 * 1024 * 1024
+
+// restClient the client of the REST API of runtime served on unix socket, such as podman and docker engine
+type restClient struct {
+	client *http.Client
+	// host the host in url, the request is always sent to the unix socket
+	host string
+	// name the name of API in errors
+	name string
+}
+
+// restDevice the device of container given by REST API
+type restDevice struct {
+	PathOnHost        string `json:"PathOnHost"`
+	PathInContainer   string `json:"PathInContainer"`
+	CgroupPermissions string `json:"CgroupPermissions"`
+}
+
+// newRestClient check the socket of endpoint and create the client, the API is reachable when pingPath answers
+func newRestClient(endpoint, host, name, pingPath string) (*restClient, error) {
+	sock := strings.TrimPrefix(endpoint, unixPre)
+	if _, err := utils.CheckPath(sock); err != nil {
+		hwlog.RunLog.Error("check socket path failed")
+		return nil, err
+	}
+	c := &restClient{
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return dial(ctx, sock)
+				},
+			},
+			Timeout: defaultTimeout,
+		},
+		host: host,
+		name: name,
+	}
+	if err := c.get(context.Background(), pingPath, nil); err != nil {
+		return nil, fmt.Errorf("connecting to %s API failed: %v", name, err)
+	}
+	return c, nil
+}
+
+// get send GET request and decode the json response into out, which is skipped when out is nil
+func (c *restClient) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.host+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRestRespBytes))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s API %s returns status %d", c.name, path, resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body, out)
+}
+
+// close closes the idle connections, it is safe on nil client
+func (c *restClient) close() {
+	if c != nil {
+		c.client.CloseIdleConnections()
+	}
+}
+
+// specOfRestContainer build the OCI spec with env and linux devices of the inspected container, the devices
+// are the char devices on host. the devices of privileged container are not monitored
+func specOfRestContainer(env []string, devices []restDevice, privileged bool) v1.Spec {
+	spec := v1.Spec{
+		Process: &v1.Process{Env: env},
+		Linux:   &v1.Linux{Resources: &v1.LinuxResources{}},
+	}
+	if privileged {
+		return spec
+	}
+	for _, dev := range devices {
+		major, minor, err := charDeviceNumber(dev.PathOnHost)
+		if err != nil {
+			logger.Warnf("get device number of %s failed: %v", dev.PathOnHost, err)
+			continue
+		}
+		spec.Linux.Resources.Devices = append(spec.Linux.Resources.Devices, v1.LinuxDeviceCgroup{
+			Allow:  true,
+			Type:   charDevice,
+			Major:  &major,
+			Minor:  &minor,
+			Access: dev.CgroupPermissions,
+		})
+	}
+	return spec
+}
+
+func charDeviceNumber(path string) (int64, int64, error) {
+	st := unix.Stat_t{}
+	if err := unix.Stat(path, &st); err != nil {
+		return 0, 0, err
+	}
+	if st.Mode&unix.S_IFMT != unix.S_IFCHR {
+		return 0, 0, errors.New("not a char device")
+	}
+	dev := uint64(st.Rdev)
+	return int64(unix.Major(dev)), int64(unix.Minor(dev)), nil
+}
+
+// timeOfUnix the time of unix seconds given by REST API, zero time when it is not given
+func timeOfUnix(sec int64) time.Time {
+	if sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// standaloneLabels fill the kubernetes labels of the container not managed by kubernetes, the namespace is the
+// runtime, the pod is given by runtime or the container itself
+func standaloneLabels(containerLabels map[string]string, namespace, podName, name string) map[string]string {
+	labels := make(map[string]string, len(containerLabels))
+	for k, v := range containerLabels {
+		labels[k] = v
+	}
+	if _, ok := labels[labelK8sPodName]; ok {
+		return labels
+	}
+	if podName == "" {
+		podName = name
+	}
+	labels[labelK8sPodNamespace] = namespace
+	labels[labelK8sPodName] = podName
+	labels[labelContainerName] = name
+	return labels
+}
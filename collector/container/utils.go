@@ -129,5 +129,9 @@ func makeUpDeviceInfo(c *CommonContainer) (DevicesInfo, error) {
 
 	deviceInfo.ID = c.Id
 	deviceInfo.Name = ns + "_" + podName + "_" + containerName
+	deviceInfo.Labels = c.Labels
+	deviceInfo.Annotations = c.Annotations
+	deviceInfo.StartedAt = c.StartedAt
+	deviceInfo.PodStartedAt = c.PodStartedAt
 	return deviceInfo, nil
 }
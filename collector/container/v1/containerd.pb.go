@@ -1,310 +1,642 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
 // source: containerd.proto
-// protoc:3.13.0
-// protoc-gen-go 1.3.5
 
 package v1
 
 import (
-	"context"
-	"fmt"
-	"math"
-
-	"github.com/golang/protobuf/proto"
-	"github.com/golang/protobuf/ptypes/any"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
 )
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ = fmt.Errorf
-var _ = math.Inf
-var _ = proto.Marshal
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the proto package it is being compiled against.
-// A compilation error at this line likely means your copy of the
-// proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
 
 type Container struct {
-	// ID the container id
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID is the user-specified identifier.
 	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// Labels the container labels
+	// Labels provides an area to include arbitrary data on containers.
 	Labels map[string]string `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	// Image the container image
+	// Image contains the reference of the image used to build the
 	Image string `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
-	// Spec  runtime specific.
-	Spec                 *any.Any `protobuf:"bytes,5,opt,name=spec,proto3" json:"spec,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	// Runtime specifies runtime.
+	Runtime *Container_Runtime `protobuf:"bytes,4,opt,name=runtime,proto3" json:"runtime,omitempty"`
+	// Spec opencotainer spec.
+	Spec *anypb.Any `protobuf:"bytes,5,opt,name=spec,proto3" json:"spec,omitempty"`
+	// Snapshotter is the snapshotter name used for rootfs
+	Snapshotter string `protobuf:"bytes,6,opt,name=snapshotter,proto3" json:"snapshotter,omitempty"`
+	// SnapshotKey the snapshot key to use for the container's root
+	SnapshotKey string `protobuf:"bytes,7,opt,name=snapshot_key,json=snapshotKey,proto3" json:"snapshot_key,omitempty"`
+	// CreatedAt is the create time of container.
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// UpdatedAt is the last update of container.
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Extensions allow clients to provide zero or more blobs that are directly
+	Extensions map[string]*anypb.Any `protobuf:"bytes,10,rep,name=extensions,proto3" json:"extensions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *Container) Reset() {
+	*x = Container{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_containerd_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-// Reset reset the object
-func (m *Container) Reset() { *m = Container{} }
-
-// String
-func (m *Container) String() string { return proto.CompactTextString(m) }
+func (x *Container) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
 
-// ProtoMessage
 func (*Container) ProtoMessage() {}
 
-// Descriptor
+func (x *Container) ProtoReflect() protoreflect.Message {
+	mi := &file_containerd_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Container.ProtoReflect.Descriptor instead.
 func (*Container) Descriptor() ([]byte, []int) {
-	return fileDescriptor_29bcc067d8d1b7d0, []int{0}
+	return file_containerd_proto_rawDescGZIP(), []int{0}
 }
 
-// XXX_Unmarshal
-func (m *Container) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Container.Unmarshal(m, b)
+func (x *Container) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
 }
 
-// XXX_Marshal
-func (m *Container) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Container.Marshal(b, m, deterministic)
+func (x *Container) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
 }
 
-// XXX_Merge
-func (m *Container) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Container.Merge(m, src)
+func (x *Container) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
 }
 
-// XXX_Size
-func (m *Container) XXX_Size() int {
-	return xxx_messageInfo_Container.Size(m)
+func (x *Container) GetRuntime() *Container_Runtime {
+	if x != nil {
+		return x.Runtime
+	}
+	return nil
 }
 
-// XXX_DiscardUnknown
-func (m *Container) XXX_DiscardUnknown() {
-	xxx_messageInfo_Container.DiscardUnknown(m)
+func (x *Container) GetSpec() *anypb.Any {
+	if x != nil {
+		return x.Spec
+	}
+	return nil
 }
 
-var xxx_messageInfo_Container proto.InternalMessageInfo
+func (x *Container) GetSnapshotter() string {
+	if x != nil {
+		return x.Snapshotter
+	}
+	return ""
+}
 
-// GetId
-func (m *Container) GetId() string {
-	if m != nil {
-		return m.Id
+func (x *Container) GetSnapshotKey() string {
+	if x != nil {
+		return x.SnapshotKey
 	}
 	return ""
 }
 
-// GetLabels
-func (m *Container) GetLabels() map[string]string {
-	if m != nil {
-		return m.Labels
+func (x *Container) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
 	}
 	return nil
 }
 
-// GetImage
-func (m *Container) GetImage() string {
-	if m != nil {
-		return m.Image
+func (x *Container) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
 	}
-	return ""
+	return nil
 }
 
-// GetSpec
-func (m *Container) GetSpec() *any.Any {
-	if m != nil {
-		return m.Spec
+func (x *Container) GetExtensions() map[string]*anypb.Any {
+	if x != nil {
+		return x.Extensions
 	}
 	return nil
 }
 
 type GetContainerRequest struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *GetContainerRequest) Reset()         { *m = GetContainerRequest{} }
-func (m *GetContainerRequest) String() string { return proto.CompactTextString(m) }
-func (*GetContainerRequest) ProtoMessage()    {}
-func (*GetContainerRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_29bcc067d8d1b7d0, []int{1}
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (m *GetContainerRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetContainerRequest.Unmarshal(m, b)
-}
-func (m *GetContainerRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetContainerRequest.Marshal(b, m, deterministic)
-}
-func (m *GetContainerRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetContainerRequest.Merge(m, src)
+func (x *GetContainerRequest) Reset() {
+	*x = GetContainerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_containerd_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *GetContainerRequest) XXX_Size() int {
-	return xxx_messageInfo_GetContainerRequest.Size(m)
+
+func (x *GetContainerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *GetContainerRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetContainerRequest.DiscardUnknown(m)
+
+func (*GetContainerRequest) ProtoMessage() {}
+
+func (x *GetContainerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_containerd_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_GetContainerRequest proto.InternalMessageInfo
+// Deprecated: Use GetContainerRequest.ProtoReflect.Descriptor instead.
+func (*GetContainerRequest) Descriptor() ([]byte, []int) {
+	return file_containerd_proto_rawDescGZIP(), []int{1}
+}
 
-func (m *GetContainerRequest) GetId() string {
-	if m != nil {
-		return m.Id
+func (x *GetContainerRequest) GetId() string {
+	if x != nil {
+		return x.Id
 	}
 	return ""
 }
 
 type GetContainerResponse struct {
-	Container            *Container `protobuf:"bytes,1,opt,name=container,proto3" json:"container,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Container *Container `protobuf:"bytes,1,opt,name=container,proto3" json:"container,omitempty"`
+}
+
+func (x *GetContainerResponse) Reset() {
+	*x = GetContainerResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_containerd_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetContainerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetContainerResponse) ProtoMessage() {}
+
+func (x *GetContainerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_containerd_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func (m *GetContainerResponse) Reset()         { *m = GetContainerResponse{} }
-func (m *GetContainerResponse) String() string { return proto.CompactTextString(m) }
-func (*GetContainerResponse) ProtoMessage()    {}
+// Deprecated: Use GetContainerResponse.ProtoReflect.Descriptor instead.
 func (*GetContainerResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_29bcc067d8d1b7d0, []int{2}
+	return file_containerd_proto_rawDescGZIP(), []int{2}
 }
 
-func (m *GetContainerResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetContainerResponse.Unmarshal(m, b)
+func (x *GetContainerResponse) GetContainer() *Container {
+	if x != nil {
+		return x.Container
+	}
+	return nil
 }
-func (m *GetContainerResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetContainerResponse.Marshal(b, m, deterministic)
+
+type ListContainersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Filters the containers are listed when they match any of the filters
+	Filters []string `protobuf:"bytes,1,rep,name=filters,proto3" json:"filters,omitempty"`
 }
-func (m *GetContainerResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetContainerResponse.Merge(m, src)
+
+func (x *ListContainersRequest) Reset() {
+	*x = ListContainersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_containerd_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *GetContainerResponse) XXX_Size() int {
-	return xxx_messageInfo_GetContainerResponse.Size(m)
+
+func (x *ListContainersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *GetContainerResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetContainerResponse.DiscardUnknown(m)
+
+func (*ListContainersRequest) ProtoMessage() {}
+
+func (x *ListContainersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_containerd_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_GetContainerResponse proto.InternalMessageInfo
+// Deprecated: Use ListContainersRequest.ProtoReflect.Descriptor instead.
+func (*ListContainersRequest) Descriptor() ([]byte, []int) {
+	return file_containerd_proto_rawDescGZIP(), []int{3}
+}
 
-func (m *GetContainerResponse) GetContainer() *Container {
-	if m != nil {
-		return m.Container
+func (x *ListContainersRequest) GetFilters() []string {
+	if x != nil {
+		return x.Filters
 	}
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*Container)(nil), "containerd.services.containers.v1.Container")
-	proto.RegisterMapType((map[string]string)(nil), "containerd.services.containers.v1.Container.LabelsEntry")
-	proto.RegisterType((*GetContainerRequest)(nil), "containerd.services.containers.v1.GetContainerRequest")
-	proto.RegisterType((*GetContainerResponse)(nil), "containerd.services.containers.v1.GetContainerResponse")
+type ListContainersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Containers []*Container `protobuf:"bytes,1,rep,name=containers,proto3" json:"containers,omitempty"`
 }
 
-func init() {
-	proto.RegisterFile("containerd.proto", fileDescriptor_29bcc067d8d1b7d0)
+func (x *ListContainersResponse) Reset() {
+	*x = ListContainersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_containerd_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-var fileDescriptor_29bcc067d8d1b7d0 = []byte{
-	// 327 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x51, 0x4f, 0x4b, 0xc3, 0x30,
-	0x14, 0xa7, 0xad, 0x1b, 0xec, 0x15, 0x64, 0xc4, 0x1d, 0xea, 0x4e, 0x73, 0x20, 0xf4, 0xa0, 0xa9,
-	0xab, 0xa0, 0x53, 0x4f, 0x2a, 0x32, 0x10, 0x0f, 0xd2, 0xa3, 0xb7, 0xb6, 0x7b, 0xce, 0x62, 0x96,
-	0xd4, 0x24, 0xad, 0xf6, 0xee, 0x87, 0xf5, 0x63, 0xc8, 0xd2, 0xad, 0x4e, 0x11, 0x74, 0xb7, 0xf7,
-	0x5e, 0x7f, 0x7f, 0x1b, 0xe8, 0xa6, 0x82, 0xeb, 0x38, 0xe3, 0x28, 0xa7, 0x34, 0x97, 0x42, 0x0b,
-	0xb2, 0xb7, 0x76, 0x51, 0x28, 0xcb, 0x2c, 0x45, 0x45, 0x9b, 0x9b, 0xa2, 0xe5, 0xa8, 0xbf, 0x3b,
-	0x13, 0x62, 0xc6, 0x30, 0x30, 0x84, 0xa4, 0x78, 0x0c, 0x62, 0x5e, 0xd5, 0xec, 0xe1, 0x87, 0x05,
-	0x9d, 0xeb, 0x15, 0x98, 0x6c, 0x83, 0x9d, 0x4d, 0x3d, 0x6b, 0x60, 0xf9, 0x9d, 0xc8, 0xce, 0xa6,
-	0xe4, 0x1e, 0xda, 0x2c, 0x4e, 0x90, 0x29, 0xcf, 0x1e, 0x38, 0xbe, 0x1b, 0x8e, 0xe9, 0x9f, 0x66,
-	0xb4, 0x51, 0xa3, 0x77, 0x86, 0x7a, 0xc3, 0xb5, 0xac, 0xa2, 0xa5, 0x0e, 0xe9, 0x41, 0x2b, 0x9b,
-	0xc7, 0x33, 0xf4, 0x1c, 0x63, 0x52, 0x2f, 0xc4, 0x87, 0x2d, 0x95, 0x63, 0xea, 0xb5, 0x06, 0x96,
-	0xef, 0x86, 0x3d, 0x5a, 0xe7, 0xa5, 0xab, 0xbc, 0xf4, 0x92, 0x57, 0x91, 0x41, 0xf4, 0xcf, 0xc0,
-	0x5d, 0x93, 0x25, 0x5d, 0x70, 0x9e, 0xb1, 0x5a, 0x26, 0x5e, 0x8c, 0x0b, 0x83, 0x32, 0x66, 0x05,
-	0x7a, 0x76, 0x6d, 0x60, 0x96, 0x73, 0x7b, 0x6c, 0x0d, 0xf7, 0x61, 0x67, 0x82, 0xba, 0x89, 0x17,
-	0xe1, 0x4b, 0x81, 0x4a, 0xff, 0xec, 0x3c, 0x4c, 0xa0, 0xf7, 0x1d, 0xa6, 0x72, 0xc1, 0x15, 0x92,
-	0x5b, 0xe8, 0x34, 0x45, 0x0d, 0xdc, 0x0d, 0x0f, 0x36, 0xf9, 0x1d, 0xd1, 0x17, 0x3d, 0x7c, 0xb7,
-	0x00, 0x9a, 0x0f, 0x8a, 0x94, 0xe0, 0x4c, 0x50, 0x93, 0x93, 0x7f, 0xc8, 0xfd, 0xd2, 0xa0, 0x7f,
-	0xba, 0x31, 0xaf, 0xae, 0x74, 0x75, 0xf4, 0x40, 0x9f, 0x8a, 0xf8, 0x15, 0x33, 0x9a, 0x8a, 0x79,
-	0xc0, 0xf3, 0xe2, 0x10, 0xdf, 0x72, 0x21, 0x35, 0xca, 0x20, 0x15, 0x8c, 0x61, 0xaa, 0xc5, 0x62,
-	0x5a, 0xd2, 0x2e, 0xca, 0x51, 0xd2, 0x36, 0x4f, 0x72, 0xfc, 0x19, 0x00, 0x00, 0xff, 0xff, 0x30,
-	0xcc, 0x1c, 0x74, 0x87, 0x02, 0x00, 0x00,
+func (x *ListContainersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConnInterface
+func (*ListContainersResponse) ProtoMessage() {}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+func (x *ListContainersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_containerd_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
 
-// ContainersClient is the client API for Containers service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type ContainersClient interface {
-	Get(ctx context.Context, in *GetContainerRequest, opts ...grpc.CallOption) (*GetContainerResponse, error)
+// Deprecated: Use ListContainersResponse.ProtoReflect.Descriptor instead.
+func (*ListContainersResponse) Descriptor() ([]byte, []int) {
+	return file_containerd_proto_rawDescGZIP(), []int{4}
 }
 
-type containersClient struct {
-	cc grpc.ClientConnInterface
+func (x *ListContainersResponse) GetContainers() []*Container {
+	if x != nil {
+		return x.Containers
+	}
+	return nil
 }
 
-func NewContainersClient(cc grpc.ClientConnInterface) ContainersClient {
-	return &containersClient{cc}
+type Container_Runtime struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name is the name of the runtime.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Options runtime initialization options.
+	Options *anypb.Any `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
 }
 
-func (c *containersClient) Get(ctx context.Context, in *GetContainerRequest, opts ...grpc.CallOption) (*GetContainerResponse, error) {
-	out := new(GetContainerResponse)
-	err := c.cc.Invoke(ctx, "/containerd.services.containers.v1.Containers/Get", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (x *Container_Runtime) Reset() {
+	*x = Container_Runtime{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_containerd_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return out, nil
 }
 
-// ContainersServer is the server API for Containers service.
-type ContainersServer interface {
-	Get(context.Context, *GetContainerRequest) (*GetContainerResponse, error)
+func (x *Container_Runtime) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-// UnimplementedContainersServer can be embedded to have forward compatible implementations.
-type UnimplementedContainersServer struct {
-}
+func (*Container_Runtime) ProtoMessage() {}
 
-func (*UnimplementedContainersServer) Get(context.Context, *GetContainerRequest) (*GetContainerResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+func (x *Container_Runtime) ProtoReflect() protoreflect.Message {
+	mi := &file_containerd_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func RegisterContainersServer(s *grpc.Server, srv ContainersServer) {
-	s.RegisterService(&_Containers_desc, srv)
+// Deprecated: Use Container_Runtime.ProtoReflect.Descriptor instead.
+func (*Container_Runtime) Descriptor() ([]byte, []int) {
+	return file_containerd_proto_rawDescGZIP(), []int{0, 1}
 }
 
-func _Containers_Get_Method(srv interface{}, ctx context.Context, desc func(interface{}) error, itcpt grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetContainerRequest)
-	if err := desc(in); err != nil {
-		return nil, err
-	}
-	if itcpt == nil {
-		return srv.(ContainersServer).Get(ctx, in)
+func (x *Container_Runtime) GetName() string {
+	if x != nil {
+		return x.Name
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/containerd.services.containers.v1.Containers/Get",
-	}
-	handler := func(ctx context.Context, request interface{}) (interface{}, error) {
-		return srv.(ContainersServer).Get(ctx, request.(*GetContainerRequest))
+	return ""
+}
+
+func (x *Container_Runtime) GetOptions() *anypb.Any {
+	if x != nil {
+		return x.Options
 	}
-	return itcpt(ctx, in, info, handler)
+	return nil
 }
 
-var _Containers_desc = grpc.ServiceDesc{
-	ServiceName: "containerd.services.containers.v1.Containers",
-	HandlerType: (*ContainersServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Get",
-			Handler:    _Containers_Get_Method,
+var File_containerd_proto protoreflect.FileDescriptor
+
+var file_containerd_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x64, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x21, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x64, 0x2e, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65,
+	0x72, 0x73, 0x2e, 0x76, 0x31, 0x1a, 0x19, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0xf5, 0x05, 0x0a, 0x09, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x50, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x38, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x64, 0x2e, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x4c, 0x61,
+	0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x4e, 0x0a, 0x07, 0x72, 0x75, 0x6e, 0x74, 0x69,
+	0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x61,
+	0x69, 0x6e, 0x65, 0x72, 0x64, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x63,
+	0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e,
+	0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x07,
+	0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x28, 0x0a, 0x04, 0x73, 0x70, 0x65, 0x63, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x04, 0x73, 0x70, 0x65,
+	0x63, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x74, 0x65, 0x72,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x74, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41,
+	0x74, 0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x5c, 0x0a, 0x0a,
+	0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x3c, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x64, 0x2e, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72,
+	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x45,
+	0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a,
+	0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61,
+	0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x4d, 0x0a, 0x07, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2e, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x07, 0x6f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x1a, 0x53, 0x0a, 0x0f, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x25, 0x0a, 0x13, 0x47, 0x65, 0x74,
+	0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x22, 0x62, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x74,
+	0x61, 0x69, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x63, 0x6f,
+	0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x64, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x74, 0x61,
+	0x69, 0x6e, 0x65, 0x72, 0x22, 0x31, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x74,
+	0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a,
+	0x07, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x22, 0x66, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x43,
+	0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x4c, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65,
+	0x72, 0x64, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x63, 0x6f, 0x6e, 0x74,
+	0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69,
+	0x6e, 0x65, 0x72, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x32,
+	0x81, 0x02, 0x0a, 0x0a, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x12, 0x76,
+	0x0a, 0x03, 0x47, 0x65, 0x74, 0x12, 0x36, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65,
+	0x72, 0x64, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x63, 0x6f, 0x6e, 0x74,
+	0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e,
+	0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x37, 0x2e,
+	0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x64, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x7b, 0x0a, 0x04, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x38,
+	0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x64, 0x2e, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x39, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x61,
+	0x69, 0x6e, 0x65, 0x72, 0x64, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2e, 0x63,
+	0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x42, 0x33, 0x5a, 0x31, 0x68, 0x75, 0x61, 0x77, 0x65, 0x69, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x6e, 0x70, 0x75, 0x2d, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2f, 0x76,
+	0x36, 0x2f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2f, 0x63, 0x6f, 0x6e, 0x74,
+	0x61, 0x69, 0x6e, 0x65, 0x72, 0x3b, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_containerd_proto_rawDescOnce sync.Once
+	file_containerd_proto_rawDescData = file_containerd_proto_rawDesc
+)
+
+func file_containerd_proto_rawDescGZIP() []byte {
+	file_containerd_proto_rawDescOnce.Do(func() {
+		file_containerd_proto_rawDescData = protoimpl.X.CompressGZIP(file_containerd_proto_rawDescData)
+	})
+	return file_containerd_proto_rawDescData
+}
+
+var file_containerd_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_containerd_proto_goTypes = []interface{}{
+	(*Container)(nil),              // 0: containerd.services.containers.v1.Container
+	(*GetContainerRequest)(nil),    // 1: containerd.services.containers.v1.GetContainerRequest
+	(*GetContainerResponse)(nil),   // 2: containerd.services.containers.v1.GetContainerResponse
+	(*ListContainersRequest)(nil),  // 3: containerd.services.containers.v1.ListContainersRequest
+	(*ListContainersResponse)(nil), // 4: containerd.services.containers.v1.ListContainersResponse
+	nil,                            // 5: containerd.services.containers.v1.Container.LabelsEntry
+	(*Container_Runtime)(nil),      // 6: containerd.services.containers.v1.Container.Runtime
+	nil,                            // 7: containerd.services.containers.v1.Container.ExtensionsEntry
+	(*anypb.Any)(nil),              // 8: google.protobuf.Any
+	(*timestamppb.Timestamp)(nil),  // 9: google.protobuf.Timestamp
+}
+var file_containerd_proto_depIdxs = []int32{
+	5,  // 0: containerd.services.containers.v1.Container.labels:type_name -> containerd.services.containers.v1.Container.LabelsEntry
+	6,  // 1: containerd.services.containers.v1.Container.runtime:type_name -> containerd.services.containers.v1.Container.Runtime
+	8,  // 2: containerd.services.containers.v1.Container.spec:type_name -> google.protobuf.Any
+	9,  // 3: containerd.services.containers.v1.Container.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 4: containerd.services.containers.v1.Container.updated_at:type_name -> google.protobuf.Timestamp
+	7,  // 5: containerd.services.containers.v1.Container.extensions:type_name -> containerd.services.containers.v1.Container.ExtensionsEntry
+	0,  // 6: containerd.services.containers.v1.GetContainerResponse.container:type_name -> containerd.services.containers.v1.Container
+	0,  // 7: containerd.services.containers.v1.ListContainersResponse.containers:type_name -> containerd.services.containers.v1.Container
+	8,  // 8: containerd.services.containers.v1.Container.Runtime.options:type_name -> google.protobuf.Any
+	8,  // 9: containerd.services.containers.v1.Container.ExtensionsEntry.value:type_name -> google.protobuf.Any
+	1,  // 10: containerd.services.containers.v1.Containers.Get:input_type -> containerd.services.containers.v1.GetContainerRequest
+	3,  // 11: containerd.services.containers.v1.Containers.List:input_type -> containerd.services.containers.v1.ListContainersRequest
+	2,  // 12: containerd.services.containers.v1.Containers.Get:output_type -> containerd.services.containers.v1.GetContainerResponse
+	4,  // 13: containerd.services.containers.v1.Containers.List:output_type -> containerd.services.containers.v1.ListContainersResponse
+	12, // [12:14] is the sub-list for method output_type
+	10, // [10:12] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_containerd_proto_init() }
+func file_containerd_proto_init() {
+	if File_containerd_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_containerd_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Container); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_containerd_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetContainerRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_containerd_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetContainerResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_containerd_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListContainersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_containerd_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListContainersResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_containerd_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Container_Runtime); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_containerd_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
 		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "containerd.proto",
+		GoTypes:           file_containerd_proto_goTypes,
+		DependencyIndexes: file_containerd_proto_depIdxs,
+		MessageInfos:      file_containerd_proto_msgTypes,
+	}.Build()
+	File_containerd_proto = out.File
+	file_containerd_proto_rawDesc = nil
+	file_containerd_proto_goTypes = nil
+	file_containerd_proto_depIdxs = nil
 }
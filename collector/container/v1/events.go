@@ -0,0 +1,28 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package v1 implement the containerd client
+package v1
+
+//go:generate protoc --go_out=paths=source_relative:. --go-grpc_out=paths=source_relative:. events.proto
+
+// the topics of containerd events
+const (
+	// TopicTaskStart the task of container is started
+	TopicTaskStart = "/tasks/start"
+	// TopicTaskExit the task of container is exited
+	TopicTaskExit = "/tasks/exit"
+	// TopicContainerDelete the container is deleted
+	TopicContainerDelete = "/containers/delete"
+)
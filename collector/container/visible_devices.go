@@ -0,0 +1,120 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/devmanager/common"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	// ascendVnpuSpecs the template of vNPU created by Ascend Docker Runtime on the chip in ASCEND_VISIBLE_DEVICES
+	ascendVnpuSpecs = "ASCEND_VNPU_SPECS"
+	// ascendRtVisibleDevices the indexes of the devices visible to the processes among the devices of container
+	ascendRtVisibleDevices = "ASCEND_RT_VISIBLE_DEVICES"
+
+	// UnknownID the physical chip or vdev id of vNPU which is not known from the container
+	UnknownID = -1
+)
+
+// VDevice the vNPU assigned to container
+type VDevice struct {
+	// PhyID the physical chip of vNPU, UnknownID for the vNPU created in advance, which is resolved by its VDevID
+	PhyID int
+	// VDevID the id of vNPU, UnknownID for the vNPU created by runtime, which is resolved by PhyID and Template
+	VDevID int
+	// Template the template of vNPU given by ASCEND_VNPU_SPECS, such as vir02, empty for the vNPU created in advance
+	Template string
+}
+
+// findAscendDeviceEnv find the last ASCEND_VISIBLE_DEVICES in envs
+func findAscendDeviceEnv(envs []string) (string, bool) {
+	for i := len(envs) - 1; i >= 0; i-- {
+		if strings.Contains(envs[i], ascendDeviceInfo) {
+			return envs[i], true
+		}
+	}
+	return "", false
+}
+
+// envValue the value of the last env named key
+func envValue(envs []string, key string) (string, bool) {
+	prefix := key + "="
+	for i := len(envs) - 1; i >= 0; i-- {
+		if strings.HasPrefix(envs[i], prefix) {
+			return strings.TrimPrefix(envs[i], prefix), true
+		}
+	}
+	return "", false
+}
+
+// applyVisibleDevicesEnv narrow the devices of container by ASCEND_RT_VISIBLE_DEVICES, and find the vNPU of
+// container. the vdev ids (>= 100) are the vNPU created in advance, and the single chip with ASCEND_VNPU_SPECS
+// is the physical chip of the vNPU created by runtime, which is not one of Devices
+func applyVisibleDevicesEnv(info *DevicesInfo, envs []string) {
+	if info.ID == "" || len(info.Devices) == 0 {
+		return
+	}
+	if indexes, ok := envValue(envs, ascendRtVisibleDevices); ok {
+		info.Devices = selectRtVisibleDevices(info.Devices, indexes, info.ID)
+	}
+	if template, ok := envValue(envs, ascendVnpuSpecs); ok && template != "" && len(info.Devices) == 1 &&
+		!common.IsValidVDevID(uint32(info.Devices[0])) {
+		info.VDevices = []VDevice{{PhyID: info.Devices[0], VDevID: UnknownID, Template: template}}
+		info.Devices = nil
+		return
+	}
+	for _, id := range info.Devices {
+		if id >= 0 && common.IsValidVDevID(uint32(id)) {
+			info.VDevices = append(info.VDevices, VDevice{PhyID: UnknownID, VDevID: id})
+		}
+	}
+}
+
+// selectRtVisibleDevices select the devices by the indexes in ASCEND_RT_VISIBLE_DEVICES, the devices are numbered
+// in ascending order inside container. all devices are kept when no index is valid
+func selectRtVisibleDevices(devices []int, indexes, containerID string) []int {
+	sorted := append([]int{}, devices...)
+	sort.Ints(sorted)
+	selected := make([]int, 0, len(sorted))
+	for _, item := range strings.Split(indexes, comma) {
+		idx, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil || idx < 0 || idx >= len(sorted) {
+			logger.Warnf(envErrDescribe(containerID, item, ascendRtVisibleDevices, err))
+			continue
+		}
+		if !containsInt(selected, sorted[idx]) {
+			selected = append(selected, sorted[idx])
+		}
+	}
+	if len(selected) == 0 {
+		return devices
+	}
+	return selected
+}
+
+func containsInt(slice []int, target int) bool {
+	for _, v := range slice {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package container for monitoring containers' npu allocation
+package container
+
+import (
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	mockVDevID   = 100
+	mockTemplate = "vir02"
+)
+
+// TestApplyVisibleDevicesEnv test resolving the vNPU and ASCEND_RT_VISIBLE_DEVICES of container
+func TestApplyVisibleDevicesEnv(t *testing.T) {
+	convey.Convey("TestApplyVisibleDevicesEnv", t, func() {
+		convey.Convey("the vdev ids are the vNPU created in advance", func() {
+			info := DevicesInfo{ID: mockContainerID, Devices: []int{mockVDevID, 1}}
+			applyVisibleDevicesEnv(&info, []string{ascendDeviceInfo + "=100,1"})
+			convey.So(info.Devices, convey.ShouldResemble, []int{mockVDevID, 1})
+			convey.So(info.VDevices, convey.ShouldResemble,
+				[]VDevice{{PhyID: UnknownID, VDevID: mockVDevID}})
+		})
+		convey.Convey("the chip with template is the physical chip of vNPU created by runtime", func() {
+			info := DevicesInfo{ID: mockContainerID, Devices: []int{2}}
+			applyVisibleDevicesEnv(&info, []string{ascendDeviceInfo + "=2", ascendVnpuSpecs + "=" + mockTemplate})
+			convey.So(info.Devices, convey.ShouldBeEmpty)
+			convey.So(info.VDevices, convey.ShouldResemble,
+				[]VDevice{{PhyID: 2, VDevID: UnknownID, Template: mockTemplate}})
+		})
+		convey.Convey("the devices are selected by ASCEND_RT_VISIBLE_DEVICES in ascending order", func() {
+			info := DevicesInfo{ID: mockContainerID, Devices: []int{7, 4, 5, 6}}
+			applyVisibleDevicesEnv(&info, []string{ascendRtVisibleDevices + "=1,3,1,9"})
+			convey.So(info.Devices, convey.ShouldResemble, []int{5, 7})
+			convey.So(info.VDevices, convey.ShouldBeEmpty)
+		})
+		convey.Convey("all devices are kept when no index is valid", func() {
+			info := DevicesInfo{ID: mockContainerID, Devices: []int{0, 1}}
+			applyVisibleDevicesEnv(&info, []string{ascendRtVisibleDevices + "=a"})
+			convey.So(info.Devices, convey.ShouldResemble, []int{0, 1})
+		})
+		convey.Convey("the container without npu is not changed", func() {
+			info := DevicesInfo{}
+			applyVisibleDevicesEnv(&info, []string{ascendVnpuSpecs + "=" + mockTemplate})
+			convey.So(info, convey.ShouldResemble, DevicesInfo{})
+		})
+	})
+}
@@ -0,0 +1,222 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package metrics for general collector
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/devmanager/common"
+	colcommon "github.com/professorshandian/npu-exporter/collector/common"
+	"github.com/professorshandian/npu-exporter/collector/container"
+)
+
+const (
+	// jobLabel not named job, which is the target label of prometheus
+	jobLabel = "job_name"
+	// DefaultJobLabelKey the container label whose value is the job name
+	DefaultJobLabelKey = "volcano.sh/job-name"
+	aggKeySep          = "\x00"
+)
+
+var (
+	// jobLabelKey the container label whose value is the job name, aggregate by job is disabled when it is empty
+	jobLabelKey = DefaultJobLabelKey
+
+	containerAggLevel = newAggLevel("npu_container_", []string{"namespace", "pod_name", "container_name"})
+	podAggLevel       = newAggLevel("npu_pod_", []string{"namespace", "pod_name"})
+	jobAggLevel       = newAggLevel("npu_job_", []string{"namespace", jobLabel})
+)
+
+// SetJobLabelKey set the container label whose value is the job name, empty to disable aggregate by job
+func SetJobLabelKey(key string) {
+	jobLabelKey = key
+}
+
+// aggLevel the descs of metrics aggregated at one level, such as container, pod or job
+type aggLevel struct {
+	chipNum        *prometheus.Desc
+	avgUtilization *prometheus.Desc
+	hbmUsedMemory  *prometheus.Desc
+	hbmTotalMemory *prometheus.Desc
+	power          *prometheus.Desc
+	errorCodeNum   *prometheus.Desc
+}
+
+func newAggLevel(prefix string, labels []string) aggLevel {
+	build := func(name, help string) *prometheus.Desc {
+		// the labels are appended to the field name, so that the groups do not overwrite each other in telegraf
+		return colcommon.BuildDescWithOpts(prefix+name, help, labels, colcommon.WithSeriesLabels(labels...))
+	}
+	return aggLevel{
+		chipNum:        build("chip_num", "the number of npu chips used"),
+		avgUtilization: build("avg_utilization", "the average ai core utilization of npu chips used, unit is '%'"),
+		hbmUsedMemory:  build("hbm_used_memory", "the sum of hbm used memory of npu chips used, unit is 'MB'"),
+		hbmTotalMemory: build("hbm_total_memory", "the sum of hbm total memory of npu chips used, unit is 'MB'"),
+		power:          build("power", "the sum of power of npu chips used, unit is 'W'"),
+		errorCodeNum:   build("error_code_num", "the sum of error code number of npu chips used"),
+	}
+}
+
+func (l aggLevel) describe(ch chan<- *prometheus.Desc) {
+	ch <- l.chipNum
+	ch <- l.avgUtilization
+	ch <- l.hbmUsedMemory
+	ch <- l.hbmTotalMemory
+	ch <- l.power
+	ch <- l.errorCodeNum
+}
+
+// aggValue the values aggregated from the chips of one group
+type aggValue struct {
+	labels         []string
+	chipNum        int
+	utilSum        float64
+	utilNum        int
+	hbmUsedMemory  uint64
+	hbmTotalMemory uint64
+	hbmNum         int
+	power          float64
+	powerNum       int
+	errorCodeNum   int
+}
+
+func (v *aggValue) add(chip *chipCache, hbm *hbmCache) {
+	v.chipNum++
+	if chip != nil {
+		if validateNum(float64(chip.Utilization)) {
+			v.utilSum += float64(chip.Utilization)
+			v.utilNum++
+		}
+		if validateNum(float64(chip.Power)) {
+			v.power += float64(chip.Power)
+			v.powerNum++
+		}
+		v.errorCodeNum += len(chip.ErrorCodes)
+	}
+	if hbm != nil && hbm.extInfo != nil && hbm.extInfo.HbmInfo != nil {
+		v.hbmUsedMemory += hbm.extInfo.Usage
+		v.hbmTotalMemory += hbm.extInfo.MemorySize
+		v.hbmNum++
+	}
+}
+
+func (v *aggValue) update(sink colcommon.SampleSink, level aggLevel) {
+	timestamp := time.Time{}
+	doUpdateMetric(sink, timestamp, v.chipNum, v.labels, level.chipNum)
+	if v.utilNum != 0 {
+		doUpdateMetric(sink, timestamp, v.utilSum/float64(v.utilNum), v.labels, level.avgUtilization)
+	}
+	if v.hbmNum != 0 {
+		doUpdateMetric(sink, timestamp, v.hbmUsedMemory, v.labels, level.hbmUsedMemory)
+		doUpdateMetric(sink, timestamp, v.hbmTotalMemory, v.labels, level.hbmTotalMemory)
+	}
+	if v.powerNum != 0 {
+		doUpdateMetric(sink, timestamp, v.power, v.labels, level.power)
+	}
+	doUpdateMetric(sink, timestamp, v.errorCodeNum, v.labels, level.errorCodeNum)
+}
+
+// aggGroups the groups of one level, keyed by the joined labels
+type aggGroups map[string]*aggValue
+
+func (g aggGroups) add(labels []string, chip *chipCache, hbm *hbmCache) {
+	key := strings.Join(labels, aggKeySep)
+	value, ok := g[key]
+	if !ok {
+		value = &aggValue{labels: labels}
+		g[key] = value
+	}
+	value.add(chip, hbm)
+}
+
+func (g aggGroups) update(sink colcommon.SampleSink, level aggLevel) {
+	keys := make([]string, 0, len(g))
+	for key := range g {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		g[key].update(sink, level)
+	}
+}
+
+// AggregateCollector aggregates the metrics of chips by container, pod and job
+type AggregateCollector struct {
+	colcommon.MetricsCollectorAdapter
+}
+
+// Describe description of the metric
+func (c *AggregateCollector) Describe(ch chan<- *prometheus.Desc) {
+	containerAggLevel.describe(ch)
+	podAggLevel.describe(ch)
+	jobAggLevel.describe(ch)
+}
+
+// CollectToCache nothing to collect, the metrics are aggregated from the caches of other collectors
+func (c *AggregateCollector) CollectToCache(n *colcommon.NpuCollector, chipList []colcommon.HuaWeiAIChip) {
+	colcommon.RecordGroupSuccess(colcommon.GetCacheKey(c))
+}
+
+// UpdateSamples emit the aggregated samples of containers, pods and jobs
+func (c *AggregateCollector) UpdateSamples(sink colcommon.SampleSink, n *colcommon.NpuCollector,
+	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) {
+	if len(containerMap) == 0 {
+		return
+	}
+	chipCaches := colcommon.GetInfoFromCache[chipCache](n, colcommon.GetCacheKey(&BaseInfoCollector{}))
+	hbmCaches := colcommon.GetInfoFromCache[hbmCache](n, colcommon.GetCacheKey(&HbmCollector{}))
+
+	containerGroups, podGroups, jobGroups := aggGroups{}, aggGroups{}, aggGroups{}
+	seen := make(map[int32]bool, len(chips))
+	for i := range chips {
+		chip := &chips[i]
+		// vnpu not support this metrics
+		if chip.VDevActivityInfo != nil && common.IsValidVDevID(chip.VDevActivityInfo.VDevID) {
+			continue
+		}
+		if seen[chip.PhyId] {
+			continue
+		}
+		seen[chip.PhyId] = true
+		containerInfo := geenContainerInfo(chip, containerMap)
+		containerName := getContainerNameArray(containerInfo)
+		if len(containerName) != colcommon.ContainerNameLen {
+			continue
+		}
+		var chipInfo *chipCache
+		if cache, ok := chipCaches[chip.PhyId]; ok {
+			chipInfo = &cache
+		}
+		var hbmInfo *hbmCache
+		if cache, ok := hbmCaches[chip.PhyId]; ok {
+			hbmInfo = &cache
+		}
+		ns, pod, ctr := containerName[colcommon.NameSpaceIdx], containerName[colcommon.PodNameIdx],
+			containerName[colcommon.ConNameIdx]
+		containerGroups.add([]string{ns, pod, ctr}, chipInfo, hbmInfo)
+		podGroups.add([]string{ns, pod}, chipInfo, hbmInfo)
+		if job := containerInfo.Labels[jobLabelKey]; jobLabelKey != "" && job != "" {
+			jobGroups.add([]string{ns, job}, chipInfo, hbmInfo)
+		}
+	}
+	containerGroups.update(sink, containerAggLevel)
+	podGroups.update(sink, podAggLevel)
+	jobGroups.update(sink, jobAggLevel)
+}
@@ -0,0 +1,104 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package metrics for general collector
+package metrics
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/goconvey/convey"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/devmanager/common"
+	colcommon "github.com/professorshandian/npu-exporter/collector/common"
+	"github.com/professorshandian/npu-exporter/collector/container"
+)
+
+const (
+	mockJobName     = "job1"
+	mockAggUtil     = 20
+	mockAggPower    = 50
+	mockAggHbmUsage = 100
+	mockAggHbmSize  = 400
+	podChipNum      = 3
+	jobChipNum      = 4
+)
+
+func mockAggregateCache(n *colcommon.NpuCollector, chips []colcommon.HuaWeiAIChip) {
+	chipLocal, hbmLocal := sync.Map{}, sync.Map{}
+	for _, chip := range chips {
+		chipLocal.Store(chip.PhyId, chipCache{chip: chip, timestamp: time.Now(), ErrorCodes: []int64{1},
+			Utilization: mockAggUtil * int(chip.PhyId+1), Power: mockAggPower})
+		hbmLocal.Store(chip.PhyId, hbmCache{chip: chip, timestamp: time.Now(), extInfo: &common.HbmAggregateInfo{
+			HbmInfo: &common.HbmInfo{Usage: mockAggHbmUsage, MemorySize: mockAggHbmSize}}})
+	}
+	colcommon.UpdateCache[chipCache](n, colcommon.GetCacheKey(&BaseInfoCollector{}), &chipLocal)
+	colcommon.UpdateCache[hbmCache](n, colcommon.GetCacheKey(&HbmCollector{}), &hbmLocal)
+}
+
+// mockAggContainerInfo chip 0 and 1 are used by container ns_pod_c1, chip 2 by ns_pod_c2, and chip 3 by
+// ns_pod2_c1 of the same job
+func mockAggContainerInfo() map[int32]container.DevicesInfo {
+	labels := map[string]string{DefaultJobLabelKey: mockJobName}
+	return map[int32]container.DevicesInfo{
+		0: {ID: "0", Name: "ns_pod_c1", Labels: labels},
+		1: {ID: "0", Name: "ns_pod_c1", Labels: labels},
+		2: {ID: "1", Name: "ns_pod_c2", Labels: labels},
+		3: {ID: "2", Name: "ns_pod2_c1", Labels: labels},
+	}
+}
+
+func TestAggregateCollectorUpdateSamples(t *testing.T) {
+	n := mockNewNpuCollector()
+	chips := mockGetNPUChipList()
+	mockAggregateCache(n, chips)
+	convey.Convey("TestAggregateCollectorUpdateSamples", t, func() {
+		collector := &AggregateCollector{}
+		convey.Convey("aggregate by container, pod and job", func() {
+			sink := colcommon.NewSampleSink()
+			collector.UpdateSamples(sink, n, mockAggContainerInfo(), chips)
+			fields := colcommon.ToTelegrafFields(sink.Samples(), make(map[string]map[string]interface{}))
+			general := fields[colcommon.GeneralDevTagKey]
+			convey.So(general["npu_container_chip_num_ns_pod_c1"], convey.ShouldEqual, num2)
+			convey.So(general["npu_container_avg_utilization_ns_pod_c1"], convey.ShouldEqual,
+				(mockAggUtil+mockAggUtil*num2)/float64(num2))
+			convey.So(general["npu_pod_chip_num_ns_pod"], convey.ShouldEqual, podChipNum)
+			convey.So(general["npu_pod_power_ns_pod"], convey.ShouldEqual, mockAggPower*podChipNum)
+			convey.So(general["npu_pod_hbm_total_memory_ns_pod"], convey.ShouldEqual, mockAggHbmSize*podChipNum)
+			convey.So(general["npu_job_chip_num_ns_"+mockJobName], convey.ShouldEqual, jobChipNum)
+			convey.So(general["npu_job_hbm_used_memory_ns_"+mockJobName], convey.ShouldEqual,
+				mockAggHbmUsage*jobChipNum)
+			convey.So(general["npu_job_error_code_num_ns_"+mockJobName], convey.ShouldEqual, jobChipNum)
+			for _, sample := range sink.Samples() {
+				convey.So(sample.LabelNames, convey.ShouldNotContain, "job")
+			}
+		})
+		convey.Convey("no job metrics when job label key is empty", func() {
+			SetJobLabelKey("")
+			defer SetJobLabelKey(DefaultJobLabelKey)
+			sink := colcommon.NewSampleSink()
+			collector.UpdateSamples(sink, n, mockAggContainerInfo(), chips)
+			for _, sample := range sink.Samples() {
+				convey.So(sample.Name, convey.ShouldNotStartWith, "npu_job_")
+			}
+		})
+		convey.Convey("no metrics when there is no container", func() {
+			sink := colcommon.NewSampleSink()
+			collector.UpdateSamples(sink, n, map[int32]container.DevicesInfo{}, chips)
+			convey.So(sink.Samples(), convey.ShouldBeEmpty)
+		})
+	})
+}
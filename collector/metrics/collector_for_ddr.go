@@ -24,7 +24,6 @@ import (
 	"github.com/professorshandian/npu-exporter/ascend-common/devmanager/common"
 	colcommon "github.com/professorshandian/npu-exporter/collector/common"
 	"github.com/professorshandian/npu-exporter/collector/container"
-	"github.com/professorshandian/npu-exporter/utils/logger"
 )
 
 var (
@@ -81,11 +80,12 @@ func (c *DdrCollector) CollectToCache(n *colcommon.NpuCollector, chipList []colc
 
 }
 
-// UpdatePrometheus update prometheus metrics
-func (c *DdrCollector) UpdatePrometheus(ch chan<- prometheus.Metric, n *colcommon.NpuCollector,
+// UpdateSamples emit ddr samples from cache
+func (c *DdrCollector) UpdateSamples(sink colcommon.SampleSink, n *colcommon.NpuCollector,
 	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) {
 
-	updateSingleChip := func(chipWithVnpu colcommon.HuaWeiAIChip, cache ddrCache, cardLabel []string) {
+	updateSingleChip := func(chipSink colcommon.SampleSink, chipWithVnpu colcommon.HuaWeiAIChip, cache ddrCache,
+		cardLabel []string) {
 		extInfo := cache.extInfo
 		if extInfo == nil {
 			return
@@ -93,8 +93,8 @@ func (c *DdrCollector) UpdatePrometheus(ch chan<- prometheus.Metric, n *colcommo
 		memorySize := extInfo.MemorySize
 		memoryAvailable := extInfo.MemoryAvailable
 
-		doUpdateMetric(ch, cache.timestamp, memorySize, cardLabel, descTotalMemory)
-		doUpdateMetric(ch, cache.timestamp, memorySize-memoryAvailable, cardLabel, descUsedMemory)
+		doUpdateMetric(chipSink, cache.timestamp, memorySize, cardLabel, descTotalMemory)
+		doUpdateMetric(chipSink, cache.timestamp, memorySize-memoryAvailable, cardLabel, descUsedMemory)
 
 		// vnpu not support this metrics
 		vDevActivityInfo := chipWithVnpu.VDevActivityInfo
@@ -104,38 +104,10 @@ func (c *DdrCollector) UpdatePrometheus(ch chan<- prometheus.Metric, n *colcommo
 
 		containerNameArray := getContainerNameArray(geenContainerInfo(&chipWithVnpu, containerMap))
 		if !c.Is910Series && len(containerNameArray) == colcommon.ContainerNameLen {
-			doUpdateMetric(ch, cache.timestamp, memorySize, cardLabel, npuCtrTotalMemory)
-			doUpdateMetric(ch, cache.timestamp, memorySize-memoryAvailable, cardLabel, npuCtrUsedMemory)
+			doUpdateMetric(chipSink, cache.timestamp, memorySize, cardLabel, npuCtrTotalMemory)
+			doUpdateMetric(chipSink, cache.timestamp, memorySize-memoryAvailable, cardLabel, npuCtrUsedMemory)
 		}
 	}
 
-	updateFrame[ddrCache](colcommon.GetCacheKey(c), n, containerMap, chips, updateSingleChip)
-}
-
-// UpdateTelegraf update telegraf metrics
-func (c *DdrCollector) UpdateTelegraf(fieldsMap map[string]map[string]interface{}, n *colcommon.NpuCollector,
-	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) map[string]map[string]interface{} {
-
-	caches := colcommon.GetInfoFromCache[ddrCache](n, colcommon.GetCacheKey(c))
-	for _, chip := range chips {
-		cache, ok := caches[chip.PhyId]
-		if !ok {
-			logger.Debugf("cacheKey(%v) not found", chip.PhyId)
-			continue
-		}
-		fieldMap := getFieldMap(fieldsMap, cache.chip.LogicID)
-
-		memoryInfo := cache.extInfo
-		if memoryInfo == nil {
-			logger.Debugf("info in cache is nil,cacheKey(%v)", chip.PhyId)
-			continue
-		}
-		memorySize := memoryInfo.MemorySize
-		memoryAvailable := memoryInfo.MemoryAvailable
-
-		doUpdateTelegraf(fieldMap, descTotalMemory, memorySize, "")
-		doUpdateTelegraf(fieldMap, descUsedMemory, memorySize-memoryAvailable, "")
-
-	}
-	return fieldsMap
+	updateFrame[ddrCache](colcommon.GetCacheKey(c), sink, n, containerMap, chips, updateSingleChip)
 }
@@ -35,14 +35,14 @@ var (
 
 	descEccEnableFlag = colcommon.BuildDesc("npu_chip_info_hbm_ecc_enable_flag",
 		"whether HBM ecc detection is enabled")
-	descEccSingleBitErrorCnt = colcommon.BuildDesc("npu_chip_info_hbm_ecc_single_bit_error_cnt",
+	descEccSingleBitErrorCnt = colcommon.BuildCounterDesc("npu_chip_info_hbm_ecc_single_bit_error_cnt",
 		"HBM Single Bit Error Count")
-	descEccDoubleBitErrorCnt = colcommon.BuildDesc("npu_chip_info_hbm_ecc_double_bit_error_cnt",
+	descEccDoubleBitErrorCnt = colcommon.BuildCounterDesc("npu_chip_info_hbm_ecc_double_bit_error_cnt",
 		"HBM Double Bit Error Count")
 
-	descEccTotalSingleBitErrorCnt = colcommon.BuildDesc("npu_chip_info_hbm_ecc_total_single_bit_error_cnt",
+	descEccTotalSingleBitErrorCnt = colcommon.BuildCounterDesc("npu_chip_info_hbm_ecc_total_single_bit_error_cnt",
 		"HBM Single Bit Aggregate Total Err Cnt")
-	descEccTotalDoubleBitErrorCnt = colcommon.BuildDesc("npu_chip_info_hbm_ecc_total_double_bit_error_cnt",
+	descEccTotalDoubleBitErrorCnt = colcommon.BuildCounterDesc("npu_chip_info_hbm_ecc_total_double_bit_error_cnt",
 		"HBM Double Bit Aggregate Total Err Cnt")
 	descEccSingleBitIoslatedPagesCnt = colcommon.BuildDesc("npu_chip_info_hbm_ecc_single_bit_isolated_pages_cnt",
 		"HBM Single Bit Isolated Pages Count")
@@ -104,64 +104,27 @@ func (c *HbmCollector) CollectToCache(n *colcommon.NpuCollector, chipList []colc
 	colcommon.UpdateCache[hbmCache](n, colcommon.GetCacheKey(c), &c.LocalCache)
 }
 
-// UpdatePrometheus updates the prometheus metrics.
-func (c *HbmCollector) UpdatePrometheus(ch chan<- prometheus.Metric, n *colcommon.NpuCollector,
+// UpdateSamples emit hbm samples from cache
+func (c *HbmCollector) UpdateSamples(sink colcommon.SampleSink, n *colcommon.NpuCollector,
 	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) {
 
-	updateSingleChip := func(chipWithVnpu colcommon.HuaWeiAIChip, cache hbmCache, cardLabel []string) {
+	updateSingleChip := func(chipSink colcommon.SampleSink, chipWithVnpu colcommon.HuaWeiAIChip, cache hbmCache,
+		cardLabel []string) {
 		extInfo := cache.extInfo
 		if extInfo == nil {
 			return
 		}
 		timestamp := cache.timestamp
-		doUpdateMetricWithValidateNum(ch, timestamp, float64(cache.hbmUtilization), cardLabel, descHbmUtilization)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, float64(cache.hbmUtilization), cardLabel,
+			descHbmUtilization)
 
-		c.updateHbmInfo(ch, cache, cardLabel, containerMap, chipWithVnpu)
+		c.updateHbmInfo(chipSink, cache, cardLabel, containerMap, chipWithVnpu)
 
 		eccInfo := extInfo.ECCInfo
-		updateHbmEccInfo(ch, eccInfo, timestamp, cardLabel)
+		updateHbmEccInfo(chipSink, eccInfo, timestamp, cardLabel)
 	}
 
-	updateFrame[hbmCache](colcommon.GetCacheKey(c), n, containerMap, chips, updateSingleChip)
-}
-
-// UpdateTelegraf updates the telegraf metrics.
-func (c *HbmCollector) UpdateTelegraf(fieldsMap map[string]map[string]interface{}, n *colcommon.NpuCollector,
-	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) map[string]map[string]interface{} {
-	caches := colcommon.GetInfoFromCache[hbmCache](n, colcommon.GetCacheKey(c))
-	for _, chip := range chips {
-		cache, ok := caches[chip.PhyId]
-		if !ok {
-			continue
-		}
-		fieldMap := getFieldMap(fieldsMap, cache.chip.LogicID)
-
-		extInfo := cache.extInfo
-		if extInfo == nil {
-			continue
-		}
-
-		doUpdateTelegrafWithValidateNum(fieldMap, descHbmUtilization, float64(cache.hbmUtilization), "")
-
-		doUpdateTelegraf(fieldMap, descHbmUsedMemory, extInfo.Usage, "")
-		doUpdateTelegraf(fieldMap, descHbmTotalMemory, extInfo.MemorySize, "")
-		doUpdateTelegraf(fieldMap, descHbmTemperature, extInfo.Temp, "")
-		doUpdateTelegraf(fieldMap, descHbmBWUtil, extInfo.BandWidthUtilRate, "")
-
-		eccInfo := extInfo.ECCInfo
-		if eccInfo != nil {
-			doUpdateTelegraf(fieldMap, descEccEnableFlag, eccInfo.EnableFlag, "")
-			doUpdateTelegraf(fieldMap, descEccSingleBitErrorCnt, eccInfo.SingleBitErrorCnt, "")
-			doUpdateTelegraf(fieldMap, descEccDoubleBitErrorCnt, eccInfo.DoubleBitErrorCnt, "")
-			doUpdateTelegraf(fieldMap, descEccTotalSingleBitErrorCnt, eccInfo.TotalSingleBitErrorCnt, "")
-			doUpdateTelegraf(fieldMap, descEccTotalDoubleBitErrorCnt, eccInfo.TotalDoubleBitErrorCnt, "")
-			doUpdateTelegraf(fieldMap, descEccSingleBitIoslatedPagesCnt, eccInfo.SingleBitIsolatedPagesCnt, "")
-			doUpdateTelegraf(fieldMap, descEccDoubleBitIoslatedPagesCnt, eccInfo.DoubleBitIsolatedPagesCnt, "")
-
-		}
-	}
-	return fieldsMap
-
+	updateFrame[hbmCache](colcommon.GetCacheKey(c), sink, n, containerMap, chips, updateSingleChip)
 }
 
 func getAllHBMEccInfo(c *HbmCollector, logicID int32, dmgr devmanager.DeviceInterface, chip *colcommon.HuaWeiAIChip) {
@@ -185,30 +148,30 @@ func getAllHBMEccInfo(c *HbmCollector, logicID int32, dmgr devmanager.DeviceInte
 	)
 }
 
-func updateHbmEccInfo(ch chan<- prometheus.Metric, eccInfo *common.ECCInfo, timestamp time.Time, cardLabel []string) {
+func updateHbmEccInfo(sink colcommon.SampleSink, eccInfo *common.ECCInfo, timestamp time.Time, cardLabel []string) {
 	if eccInfo == nil {
 		return
 	}
-	doUpdateMetric(ch, timestamp, eccInfo.EnableFlag, cardLabel, descEccEnableFlag)
-	doUpdateMetric(ch, timestamp, eccInfo.SingleBitErrorCnt, cardLabel, descEccSingleBitErrorCnt)
-	doUpdateMetric(ch, timestamp, eccInfo.DoubleBitErrorCnt, cardLabel, descEccDoubleBitErrorCnt)
-	doUpdateMetric(ch, timestamp, eccInfo.TotalSingleBitErrorCnt, cardLabel, descEccTotalSingleBitErrorCnt)
-	doUpdateMetric(ch, timestamp, eccInfo.TotalDoubleBitErrorCnt, cardLabel, descEccTotalDoubleBitErrorCnt)
-	doUpdateMetric(ch, timestamp, eccInfo.SingleBitIsolatedPagesCnt, cardLabel, descEccSingleBitIoslatedPagesCnt)
-	doUpdateMetric(ch, timestamp, eccInfo.DoubleBitIsolatedPagesCnt, cardLabel, descEccDoubleBitIoslatedPagesCnt)
+	doUpdateMetric(sink, timestamp, eccInfo.EnableFlag, cardLabel, descEccEnableFlag)
+	doUpdateMetric(sink, timestamp, eccInfo.SingleBitErrorCnt, cardLabel, descEccSingleBitErrorCnt)
+	doUpdateMetric(sink, timestamp, eccInfo.DoubleBitErrorCnt, cardLabel, descEccDoubleBitErrorCnt)
+	doUpdateMetric(sink, timestamp, eccInfo.TotalSingleBitErrorCnt, cardLabel, descEccTotalSingleBitErrorCnt)
+	doUpdateMetric(sink, timestamp, eccInfo.TotalDoubleBitErrorCnt, cardLabel, descEccTotalDoubleBitErrorCnt)
+	doUpdateMetric(sink, timestamp, eccInfo.SingleBitIsolatedPagesCnt, cardLabel, descEccSingleBitIoslatedPagesCnt)
+	doUpdateMetric(sink, timestamp, eccInfo.DoubleBitIsolatedPagesCnt, cardLabel, descEccDoubleBitIoslatedPagesCnt)
 }
 
-func (c *HbmCollector) updateHbmInfo(ch chan<- prometheus.Metric, cache hbmCache, cardLabel []string,
+func (c *HbmCollector) updateHbmInfo(sink colcommon.SampleSink, cache hbmCache, cardLabel []string,
 	containerMap map[int32]container.DevicesInfo, chipWithVnpu colcommon.HuaWeiAIChip) {
 	hbmInfo := cache.extInfo
 	if hbmInfo == nil {
 		return
 	}
 	timestamp := cache.timestamp
-	doUpdateMetric(ch, timestamp, hbmInfo.Usage, cardLabel, descHbmUsedMemory)
-	doUpdateMetric(ch, timestamp, hbmInfo.MemorySize, cardLabel, descHbmTotalMemory)
-	doUpdateMetric(ch, timestamp, hbmInfo.Temp, cardLabel, descHbmTemperature)
-	doUpdateMetric(ch, timestamp, hbmInfo.BandWidthUtilRate, cardLabel, descHbmBWUtil)
+	doUpdateMetric(sink, timestamp, hbmInfo.Usage, cardLabel, descHbmUsedMemory)
+	doUpdateMetric(sink, timestamp, hbmInfo.MemorySize, cardLabel, descHbmTotalMemory)
+	doUpdateMetric(sink, timestamp, hbmInfo.Temp, cardLabel, descHbmTemperature)
+	doUpdateMetric(sink, timestamp, hbmInfo.BandWidthUtilRate, cardLabel, descHbmBWUtil)
 
 	// vnpu not support this metrics
 	vDevActivityInfo := chipWithVnpu.VDevActivityInfo
@@ -218,7 +181,7 @@ func (c *HbmCollector) updateHbmInfo(ch chan<- prometheus.Metric, cache hbmCache
 
 	containerNameArray := getContainerNameArray(geenContainerInfo(&chipWithVnpu, containerMap))
 	if c.Is910Series && len(containerNameArray) == colcommon.ContainerNameLen {
-		doUpdateMetric(ch, timestamp, hbmInfo.MemorySize, cardLabel, npuCtrTotalMemory)
-		doUpdateMetric(ch, timestamp, hbmInfo.Usage, cardLabel, npuCtrUsedMemory)
+		doUpdateMetric(sink, timestamp, hbmInfo.MemorySize, cardLabel, npuCtrTotalMemory)
+		doUpdateMetric(sink, timestamp, hbmInfo.Usage, cardLabel, npuCtrUsedMemory)
 	}
 }
@@ -57,9 +57,10 @@ const (
 func init() {
 	for i := 0; i < MaxHccsNum; i++ {
 		index := strconv.Itoa(i)
-		colcommon.BuildDescSlice(&hccsTxDescs, prefix+"tx_cnt_"+index, "transmitted message count for hccs "+index)
-		colcommon.BuildDescSlice(&hccsRxDescs, prefix+"rx_cnt_"+index, "received message count for hccs "+index)
-		colcommon.BuildDescSlice(&hccsErrDescs, prefix+"crc_err_cnt_"+index, "crc error count for hccs "+index)
+		colcommon.BuildCounterDescSlice(&hccsTxDescs, prefix+"tx_cnt_"+index,
+			"transmitted message count for hccs "+index)
+		colcommon.BuildCounterDescSlice(&hccsRxDescs, prefix+"rx_cnt_"+index, "received message count for hccs "+index)
+		colcommon.BuildCounterDescSlice(&hccsErrDescs, prefix+"crc_err_cnt_"+index, "crc error count for hccs "+index)
 		colcommon.BuildDescSlice(&hccsBWTxDescs, bwPrefix+"tx_"+index,
 			"single-link transmission data bandwidth for hccs "+index)
 		colcommon.BuildDescSlice(&hccsBWRxDescs, bwPrefix+"rx_"+index,
@@ -156,19 +157,20 @@ func (c *HccsCollector) PreCollect(n *colcommon.NpuCollector, chipList []colcomm
 	}
 }
 
-// UpdatePrometheus update prometheus
-func (c *HccsCollector) UpdatePrometheus(ch chan<- prometheus.Metric, n *colcommon.NpuCollector,
+// UpdateSamples emit hccs samples from cache
+func (c *HccsCollector) UpdateSamples(sink colcommon.SampleSink, n *colcommon.NpuCollector,
 	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) {
 
-	updateSingleChip := func(chipWithVnpu colcommon.HuaWeiAIChip, cache hccsCache, cardLabel []string) {
+	updateSingleChip := func(chipSink colcommon.SampleSink, chipWithVnpu colcommon.HuaWeiAIChip, cache hccsCache,
+		cardLabel []string) {
 		timestamp := cache.timestamp
-		promUpdateHccsStatisticInfo(ch, cache, c, timestamp, cardLabel)
-		promUpdateHccsBwInfo(ch, cache, c, timestamp, cardLabel)
+		updateHccsStatisticInfo(chipSink, cache, c, timestamp, cardLabel)
+		updateHccsBwInfo(chipSink, cache, c, timestamp, cardLabel)
 	}
-	updateFrame[hccsCache](colcommon.GetCacheKey(c), n, containerMap, chips, updateSingleChip)
+	updateFrame[hccsCache](colcommon.GetCacheKey(c), sink, n, containerMap, chips, updateSingleChip)
 }
 
-func promUpdateHccsBwInfo(ch chan<- prometheus.Metric, cache hccsCache, c *HccsCollector,
+func updateHccsBwInfo(sink colcommon.SampleSink, cache hccsCache, c *HccsCollector,
 	timestamp time.Time, cardLabel []string) {
 	bandwidthInfo := cache.hccsBW
 	if bandwidthInfo == nil {
@@ -179,15 +181,15 @@ func promUpdateHccsBwInfo(ch chan<- prometheus.Metric, cache hccsCache, c *HccsC
 		return
 	}
 	for i := c.hccsBeginIndex; i < MaxHccsNum; i++ {
-		doUpdateMetric(ch, timestamp, bandwidthInfo.TxBandwidth[i], cardLabel, hccsBWTxDescs[i])
-		doUpdateMetric(ch, timestamp, bandwidthInfo.RxBandwidth[i], cardLabel, hccsBWRxDescs[i])
+		doUpdateMetric(sink, timestamp, bandwidthInfo.TxBandwidth[i], cardLabel, hccsBWTxDescs[i])
+		doUpdateMetric(sink, timestamp, bandwidthInfo.RxBandwidth[i], cardLabel, hccsBWRxDescs[i])
 	}
-	doUpdateMetric(ch, timestamp, bandwidthInfo.ProfilingTime, cardLabel, hccsBWProfilingTime)
-	doUpdateMetric(ch, timestamp, bandwidthInfo.TotalTxbw, cardLabel, hccsBWTotalTx)
-	doUpdateMetric(ch, timestamp, bandwidthInfo.TotalRxbw, cardLabel, hccsBWTotalRx)
+	doUpdateMetric(sink, timestamp, bandwidthInfo.ProfilingTime, cardLabel, hccsBWProfilingTime)
+	doUpdateMetric(sink, timestamp, bandwidthInfo.TotalTxbw, cardLabel, hccsBWTotalTx)
+	doUpdateMetric(sink, timestamp, bandwidthInfo.TotalRxbw, cardLabel, hccsBWTotalRx)
 }
 
-func promUpdateHccsStatisticInfo(ch chan<- prometheus.Metric, cache hccsCache, c *HccsCollector,
+func updateHccsStatisticInfo(sink colcommon.SampleSink, cache hccsCache, c *HccsCollector,
 	timestamp time.Time, cardLabel []string) {
 	statisticInfo := cache.hccsStat
 
@@ -199,55 +201,8 @@ func promUpdateHccsStatisticInfo(ch chan<- prometheus.Metric, cache hccsCache, c
 		return
 	}
 	for i := c.hccsBeginIndex; i < MaxHccsNum; i++ {
-		doUpdateMetric(ch, timestamp, statisticInfo.TxCnt[i], cardLabel, hccsTxDescs[i])
-		doUpdateMetric(ch, timestamp, statisticInfo.RxCnt[i], cardLabel, hccsRxDescs[i])
-		doUpdateMetric(ch, timestamp, statisticInfo.CrcErrCnt[i], cardLabel, hccsErrDescs[i])
-	}
-}
-
-// UpdateTelegraf update telegraf
-func (c *HccsCollector) UpdateTelegraf(fieldsMap map[string]map[string]interface{}, n *colcommon.NpuCollector,
-	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) map[string]map[string]interface{} {
-
-	caches := colcommon.GetInfoFromCache[hccsCache](n, colcommon.GetCacheKey(c))
-	for _, chip := range chips {
-		cache, ok := caches[chip.PhyId]
-		if !ok {
-			continue
-		}
-		fieldMap := getFieldMap(fieldsMap, cache.chip.LogicID)
-
-		telegrafUpdateHccsStatisticInfo(cache, c, fieldMap)
-		telegrafUpdateHccsBwInfo(cache, c, fieldMap)
-	}
-
-	return fieldsMap
-
-}
-
-func telegrafUpdateHccsBwInfo(cache hccsCache, c *HccsCollector, fieldMap map[string]interface{}) {
-	bandwidthInfo := cache.hccsBW
-	if bandwidthInfo == nil || c.hccsBeginIndex < 0 {
-		return
-	}
-	for i := c.hccsBeginIndex; i < MaxHccsNum; i++ {
-		doUpdateTelegraf(fieldMap, hccsBWTxDescs[i], bandwidthInfo.TxBandwidth[i], "")
-		doUpdateTelegraf(fieldMap, hccsBWRxDescs[i], bandwidthInfo.RxBandwidth[i], "")
-	}
-	doUpdateTelegraf(fieldMap, hccsBWProfilingTime, bandwidthInfo.ProfilingTime, "")
-	doUpdateTelegraf(fieldMap, hccsBWTotalTx, bandwidthInfo.TotalTxbw, "")
-	doUpdateTelegraf(fieldMap, hccsBWTotalRx, bandwidthInfo.TotalRxbw, "")
-}
-
-func telegrafUpdateHccsStatisticInfo(cache hccsCache, c *HccsCollector, fieldMap map[string]interface{}) {
-	statisticInfo := cache.hccsStat
-
-	if statisticInfo == nil || c.hccsBeginIndex < 0 {
-		return
-	}
-	for i := c.hccsBeginIndex; i < MaxHccsNum; i++ {
-		doUpdateTelegraf(fieldMap, hccsTxDescs[i], statisticInfo.TxCnt[i], "")
-		doUpdateTelegraf(fieldMap, hccsRxDescs[i], statisticInfo.RxCnt[i], "")
-		doUpdateTelegraf(fieldMap, hccsErrDescs[i], statisticInfo.CrcErrCnt[i], "")
+		doUpdateMetric(sink, timestamp, statisticInfo.TxCnt[i], cardLabel, hccsTxDescs[i])
+		doUpdateMetric(sink, timestamp, statisticInfo.RxCnt[i], cardLabel, hccsRxDescs[i])
+		doUpdateMetric(sink, timestamp, statisticInfo.CrcErrCnt[i], cardLabel, hccsErrDescs[i])
 	}
 }
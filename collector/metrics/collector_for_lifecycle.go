@@ -0,0 +1,203 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package metrics for general collector
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/devmanager/common"
+	colcommon "github.com/professorshandian/npu-exporter/collector/common"
+	"github.com/professorshandian/npu-exporter/collector/container"
+)
+
+const (
+	// DefaultIdleWindow the default window in which the ai core utilization of allocated chip keeps zero before
+	// the chip is reported as idle
+	DefaultIdleWindow = 30 * time.Minute
+	podKeySep         = "_"
+)
+
+var (
+	// idleWindow the window in which the ai core utilization of allocated chip keeps zero before the chip is
+	// reported as idle, the idle indicator is disabled when it is not positive
+	idleWindow = DefaultIdleWindow
+
+	descContainerStartTime = colcommon.BuildDesc("npu_chip_info_container_start_time",
+		"the unix time (seconds) the container using the npu is started, given by container runtime, "+
+			"which is the time it is first found on the npu when the runtime does not give it")
+	descPodAssignedTime = colcommon.BuildDesc("npu_chip_info_pod_assigned_seconds",
+		"the time (seconds) the npu has been assigned to the pod using it, since the pod sandbox is created "+
+			"or its first container on the npu is started")
+	descAssignmentChanges = colcommon.BuildCounterDesc("npu_chip_info_assignment_changes",
+		"the number of changes of the pod using the npu since exporter started, including the npu being released")
+	descIdleAllocated = colcommon.BuildDesc("npu_chip_info_idle_allocated",
+		"whether the npu is allocated but its ai core utilization keeps zero in the idle window, 1 is idle")
+)
+
+// SetIdleWindow set the window in which the ai core utilization of allocated chip keeps zero before the chip is
+// reported as idle, not positive to disable the idle indicator
+func SetIdleWindow(window time.Duration) {
+	idleWindow = window
+}
+
+// chipAssignment the assignment of chip to container and pod tracked over collections
+type chipAssignment struct {
+	containerID string
+	// pod namespace_podName of the pod using the chip, or the container id when the pod is unknown,
+	// empty when the chip is not used
+	pod            string
+	containerSince time.Time
+	podSince       time.Time
+	changes        int
+	// idleSince the time since which the ai core utilization keeps zero, zero when it is not idle
+	idleSince time.Time
+}
+
+type lifecycleCache struct {
+	chip      colcommon.HuaWeiAIChip
+	timestamp time.Time
+	chipAssignment
+}
+
+// LifecycleCollector tracks the assignment of chips to containers and pods over time
+type LifecycleCollector struct {
+	colcommon.MetricsCollectorAdapter
+	lock        sync.Mutex
+	assignments map[int32]*chipAssignment
+}
+
+// Describe description of the metric
+func (c *LifecycleCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descContainerStartTime
+	ch <- descPodAssignedTime
+	ch <- descAssignmentChanges
+	ch <- descIdleAllocated
+}
+
+// CollectToCache track the assignment of chips by the cached container info and ai core utilization
+func (c *LifecycleCollector) CollectToCache(n *colcommon.NpuCollector, chipList []colcommon.HuaWeiAIChip) {
+	containerMap := colcommon.GetContainerNPUInfo(n)
+	if containerMap == nil {
+		// the container info is unknown, keep the assignments instead of treating the chips as released
+		return
+	}
+	c.collect(n, chipList, containerMap, time.Now())
+}
+
+func (c *LifecycleCollector) collect(n *colcommon.NpuCollector, chipList []colcommon.HuaWeiAIChip,
+	containerMap map[int32]container.DevicesInfo, now time.Time) {
+	chipCaches := colcommon.GetInfoFromCache[chipCache](n, colcommon.GetCacheKey(&BaseInfoCollector{}))
+	for i := range chipList {
+		chip := &chipList[i]
+		var chipInfo *chipCache
+		if cache, ok := chipCaches[chip.PhyId]; ok {
+			chipInfo = &cache
+		}
+		assignment := c.track(chip.PhyId, geenContainerInfo(chip, containerMap), chipInfo, now)
+		c.LocalCache.Store(chip.PhyId, lifecycleCache{chip: *chip, timestamp: now, chipAssignment: assignment})
+	}
+	colcommon.UpdateCache[lifecycleCache](n, colcommon.GetCacheKey(c), &c.LocalCache)
+}
+
+// track update the assignment of chip with the container using it, and return a copy of it
+func (c *LifecycleCollector) track(phyID int32, info container.DevicesInfo, chip *chipCache,
+	now time.Time) chipAssignment {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.assignments == nil {
+		c.assignments = make(map[int32]*chipAssignment)
+	}
+	pod := podKey(info)
+	containerSince := sinceOrNow(info.StartedAt, now)
+	assignment, ok := c.assignments[phyID]
+	if !ok {
+		assignment = &chipAssignment{containerID: info.ID, pod: pod, containerSince: containerSince,
+			podSince: sinceOrNow(info.PodStartedAt, containerSince)}
+		c.assignments[phyID] = assignment
+	}
+	if assignment.pod != pod {
+		assignment.changes++
+		assignment.pod = pod
+		assignment.podSince = sinceOrNow(info.PodStartedAt, containerSince)
+		assignment.idleSince = time.Time{}
+	}
+	if assignment.containerID != info.ID {
+		assignment.containerID = info.ID
+		assignment.containerSince = containerSince
+	}
+	switch {
+	case pod == "":
+		assignment.idleSince = time.Time{}
+	case chip == nil || !validateNum(float64(chip.Utilization)):
+		// the utilization is unknown, the idle state is kept
+	case chip.Utilization != 0:
+		assignment.idleSince = time.Time{}
+	case assignment.idleSince.IsZero():
+		assignment.idleSince = now
+	}
+	return *assignment
+}
+
+// sinceOrNow the time given by runtime, or now when it is unknown or later than now
+func sinceOrNow(since time.Time, now time.Time) time.Time {
+	if since.IsZero() || since.After(now) {
+		return now
+	}
+	return since
+}
+
+// podKey the key of pod using the chip, the container id is used when the pod of container is unknown
+func podKey(info container.DevicesInfo) string {
+	names := getContainerNameArray(info)
+	if len(names) != colcommon.ContainerNameLen {
+		return info.ID
+	}
+	return names[colcommon.NameSpaceIdx] + podKeySep + names[colcommon.PodNameIdx]
+}
+
+// UpdateSamples emit the assignment samples of chips from cache
+func (c *LifecycleCollector) UpdateSamples(sink colcommon.SampleSink, n *colcommon.NpuCollector,
+	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) {
+
+	updateSingleChip := func(chipSink colcommon.SampleSink, chipWithVnpu colcommon.HuaWeiAIChip,
+		cache lifecycleCache, cardLabel []string) {
+		// vnpu not support this metrics
+		if chipWithVnpu.VDevActivityInfo != nil && common.IsValidVDevID(chipWithVnpu.VDevActivityInfo.VDevID) {
+			return
+		}
+		doUpdateMetric(chipSink, cache.timestamp, cache.changes, cardLabel, descAssignmentChanges)
+		// the container labels are got at exposition, which may differ from the tracked one until next collection
+		if cache.pod == "" || geenContainerInfo(&chipWithVnpu, containerMap).ID != cache.containerID {
+			return
+		}
+		doUpdateMetric(chipSink, cache.timestamp, cache.containerSince.Unix(), cardLabel, descContainerStartTime)
+		doUpdateMetric(chipSink, cache.timestamp, int64(cache.timestamp.Sub(cache.podSince).Seconds()), cardLabel,
+			descPodAssignedTime)
+		if idleWindow <= 0 {
+			return
+		}
+		idle := 0
+		if !cache.idleSince.IsZero() && cache.timestamp.Sub(cache.idleSince) >= idleWindow {
+			idle = 1
+		}
+		doUpdateMetric(chipSink, cache.timestamp, idle, cardLabel, descIdleAllocated)
+	}
+
+	updateFrame[lifecycleCache](colcommon.GetCacheKey(c), sink, n, containerMap, chips, updateSingleChip)
+}
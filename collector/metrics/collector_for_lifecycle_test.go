@@ -0,0 +1,112 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package metrics for general collector
+package metrics
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/goconvey/convey"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/devmanager/common"
+	colcommon "github.com/professorshandian/npu-exporter/collector/common"
+	"github.com/professorshandian/npu-exporter/collector/container"
+)
+
+const (
+	mockIdleWindow = time.Minute
+	mockBusyUtil   = 30
+	mockTimestamp  = 1700000000
+)
+
+func mockLifecycleUtil(n *colcommon.NpuCollector, chips []colcommon.HuaWeiAIChip, util int) {
+	local := sync.Map{}
+	for _, chip := range chips {
+		local.Store(chip.PhyId, chipCache{chip: chip, timestamp: time.Now(), Utilization: util})
+	}
+	colcommon.UpdateCache[chipCache](n, colcommon.GetCacheKey(&BaseInfoCollector{}), &local)
+}
+
+func lifecycleFields(collector *LifecycleCollector, n *colcommon.NpuCollector,
+	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) map[string]interface{} {
+	sink := colcommon.NewSampleSink()
+	collector.UpdateSamples(sink, n, containerMap, chips)
+	return colcommon.ToTelegrafFields(sink.Samples(), make(map[string]map[string]interface{}))["0"]
+}
+
+func TestLifecycleCollector(t *testing.T) {
+	n := mockNewNpuCollector()
+	chips := []colcommon.HuaWeiAIChip{{PhyId: 0, LogicID: 0, DeviceID: 0,
+		ChipInfo: &common.ChipInfo{Name: common.Ascend910}}}
+	start := time.Unix(mockTimestamp, 0)
+	podA := map[int32]container.DevicesInfo{0: {ID: "c1", Name: "ns_podA_c"}}
+	convey.Convey("TestLifecycleCollector", t, func() {
+		SetIdleWindow(mockIdleWindow)
+		defer SetIdleWindow(DefaultIdleWindow)
+		mockLifecycleUtil(n, chips, 0)
+		collector := &LifecycleCollector{}
+		collector.collect(n, chips, podA, start)
+		convey.Convey("the chip is idle when utilization keeps zero in the idle window", func() {
+			collector.collect(n, chips, podA, start.Add(mockIdleWindow))
+			fields := lifecycleFields(collector, n, podA, chips)
+			convey.So(fields["npu_chip_info_container_start_time"], convey.ShouldEqual, mockTimestamp)
+			convey.So(fields["npu_chip_info_pod_assigned_seconds"], convey.ShouldEqual, mockIdleWindow.Seconds())
+			convey.So(fields["npu_chip_info_assignment_changes"], convey.ShouldEqual, 0)
+			convey.So(fields["npu_chip_info_idle_allocated"], convey.ShouldEqual, 1)
+		})
+		convey.Convey("the chip is not idle when it is used", func() {
+			mockLifecycleUtil(n, chips, mockBusyUtil)
+			collector.collect(n, chips, podA, start.Add(mockIdleWindow))
+			fields := lifecycleFields(collector, n, podA, chips)
+			convey.So(fields["npu_chip_info_idle_allocated"], convey.ShouldEqual, 0)
+		})
+		convey.Convey("the pod keeps the chip when its container restarts", func() {
+			restarted := map[int32]container.DevicesInfo{0: {ID: "c2", Name: "ns_podA_c"}}
+			collector.collect(n, chips, restarted, start.Add(mockIdleWindow))
+			fields := lifecycleFields(collector, n, restarted, chips)
+			convey.So(fields["npu_chip_info_container_start_time"], convey.ShouldEqual,
+				start.Add(mockIdleWindow).Unix())
+			convey.So(fields["npu_chip_info_pod_assigned_seconds"], convey.ShouldEqual, mockIdleWindow.Seconds())
+			convey.So(fields["npu_chip_info_assignment_changes"], convey.ShouldEqual, 0)
+		})
+		convey.Convey("the start time given by runtime is used for the container started before", func() {
+			started := map[int32]container.DevicesInfo{0: {ID: "c4", Name: "ns_podC_c",
+				StartedAt: start.Add(-mockIdleWindow), PodStartedAt: start.Add(-2 * mockIdleWindow)}}
+			restarted := &LifecycleCollector{}
+			restarted.collect(n, chips, started, start)
+			fields := lifecycleFields(restarted, n, started, chips)
+			convey.So(fields["npu_chip_info_container_start_time"], convey.ShouldEqual,
+				start.Add(-mockIdleWindow).Unix())
+			convey.So(fields["npu_chip_info_pod_assigned_seconds"], convey.ShouldEqual, 2*mockIdleWindow.Seconds())
+		})
+		convey.Convey("the changes of pod are counted, including the chip being released", func() {
+			podB := map[int32]container.DevicesInfo{0: {ID: "c3", Name: "ns_podB_c"}}
+			collector.collect(n, chips, podB, start.Add(mockIdleWindow))
+			fields := lifecycleFields(collector, n, podB, chips)
+			convey.So(fields["npu_chip_info_assignment_changes"], convey.ShouldEqual, 1)
+			convey.So(fields["npu_chip_info_pod_assigned_seconds"], convey.ShouldEqual, 0)
+			convey.So(fields["npu_chip_info_idle_allocated"], convey.ShouldEqual, 0)
+
+			released := map[int32]container.DevicesInfo{}
+			collector.collect(n, chips, released, start.Add(mockIdleWindow))
+			fields = lifecycleFields(collector, n, released, chips)
+			convey.So(fields["npu_chip_info_assignment_changes"], convey.ShouldEqual, num2)
+			convey.So(fields, convey.ShouldNotContainKey, "npu_chip_info_pod_assigned_seconds")
+			convey.So(fields, convey.ShouldNotContainKey, "npu_chip_info_idle_allocated")
+		})
+	})
+}
@@ -88,65 +88,33 @@ func (c *NetworkCollector) CollectToCache(n *colcommon.NpuCollector, chipList []
 	colcommon.UpdateCache[netInfoCache](n, colcommon.GetCacheKey(c), &c.LocalCache)
 }
 
-// UpdatePrometheus update prometheus metrics
-func (c *NetworkCollector) UpdatePrometheus(ch chan<- prometheus.Metric, n *colcommon.NpuCollector,
+// UpdateSamples emit network samples from cache
+func (c *NetworkCollector) UpdateSamples(sink colcommon.SampleSink, n *colcommon.NpuCollector,
 	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) {
 
-	updateSingleChip := func(chipWithVnpu colcommon.HuaWeiAIChip, cache netInfoCache, cardLabel []string) {
+	updateSingleChip := func(chipSink colcommon.SampleSink, chipWithVnpu colcommon.HuaWeiAIChip, cache netInfoCache,
+		cardLabel []string) {
 		netInfo := cache.extInfo
 		if netInfo == nil {
 			return
 		}
 		time := cache.timestamp
 		if validateNotNilForEveryElement(netInfo.BandwidthInfo) {
-			doUpdateMetricWithValidateNum(ch, time, netInfo.BandwidthInfo.TxValue, cardLabel, descBandwidthTx)
-			doUpdateMetricWithValidateNum(ch, time, netInfo.BandwidthInfo.RxValue, cardLabel, descBandwidthRx)
+			doUpdateMetricWithValidateNum(chipSink, time, netInfo.BandwidthInfo.TxValue, cardLabel, descBandwidthTx)
+			doUpdateMetricWithValidateNum(chipSink, time, netInfo.BandwidthInfo.RxValue, cardLabel, descBandwidthRx)
 		}
 		if validateNotNilForEveryElement(netInfo.LinkSpeedInfo) {
-			doUpdateMetricWithValidateNum(ch, time, netInfo.LinkSpeedInfo.Speed, cardLabel, npuChipLinkSpeed)
+			doUpdateMetricWithValidateNum(chipSink, time, netInfo.LinkSpeedInfo.Speed, cardLabel, npuChipLinkSpeed)
 		}
 		if validateNotNilForEveryElement(netInfo.LinkStatInfo) {
-			doUpdateMetricWithValidateNum(ch, time, netInfo.LinkStatInfo.LinkUPNum, cardLabel, npuChipLinkUpNum)
+			doUpdateMetricWithValidateNum(chipSink, time, netInfo.LinkStatInfo.LinkUPNum, cardLabel, npuChipLinkUpNum)
 		}
 		if validateNotNilForEveryElement(netInfo.LinkStatusInfo) {
-			doUpdateMetricWithValidateNum(ch, time, float64(hccn.GetLinkStatusCode(netInfo.LinkStatusInfo.LinkState)),
-				cardLabel, descLinkStatus)
+			linkStatus := hccn.GetLinkStatusCode(netInfo.LinkStatusInfo.LinkState)
+			doUpdateMetricWithValidateNum(chipSink, time, float64(linkStatus), cardLabel, descLinkStatus)
 		}
 	}
-	updateFrame[netInfoCache](colcommon.GetCacheKey(c), n, containerMap, chips, updateSingleChip)
-}
-
-// UpdateTelegraf update telegraf metrics
-func (c *NetworkCollector) UpdateTelegraf(fieldsMap map[string]map[string]interface{}, n *colcommon.NpuCollector,
-	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) map[string]map[string]interface{} {
-
-	caches := colcommon.GetInfoFromCache[netInfoCache](n, colcommon.GetCacheKey(c))
-	for _, chip := range chips {
-		cache, ok := caches[chip.PhyId]
-		if !ok {
-			continue
-		}
-		fieldMap := getFieldMap(fieldsMap, cache.chip.LogicID)
-		extInfo := cache.extInfo
-		if extInfo == nil {
-			continue
-		}
-		if validateNotNilForEveryElement(extInfo.BandwidthInfo) {
-			doUpdateTelegrafWithValidateNum(fieldMap, descBandwidthTx, extInfo.BandwidthInfo.TxValue, "")
-			doUpdateTelegrafWithValidateNum(fieldMap, descBandwidthRx, extInfo.BandwidthInfo.RxValue, "")
-		}
-		if validateNotNilForEveryElement(extInfo.LinkSpeedInfo) {
-			doUpdateTelegrafWithValidateNum(fieldMap, npuChipLinkSpeed, extInfo.LinkSpeedInfo.Speed, "")
-		}
-		if validateNotNilForEveryElement(extInfo.LinkStatInfo) {
-			doUpdateTelegrafWithValidateNum(fieldMap, npuChipLinkUpNum, extInfo.LinkStatInfo.LinkUPNum, "")
-		}
-		if validateNotNilForEveryElement(extInfo.LinkStatusInfo) {
-			doUpdateTelegrafWithValidateNum(fieldMap, descLinkStatus,
-				float64(hccn.GetLinkStatusCode(extInfo.LinkStatusInfo.LinkState)), "")
-		}
-	}
-	return fieldsMap
+	updateFrame[netInfoCache](colcommon.GetCacheKey(c), sink, n, containerMap, chips, updateSingleChip)
 }
 
 func collectNetworkInfo(phyID int32) common.NpuNetInfo {
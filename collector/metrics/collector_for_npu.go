@@ -31,9 +31,16 @@ import (
 	"github.com/professorshandian/npu-exporter/utils/logger"
 )
 
+const (
+	processID          = "process_id"
+	processName        = "process_name"
+	containerNameLabel = "containerName"
+	npuNameLabel       = "name"
+)
+
 var (
 	errorCodeDescs        []*prometheus.Desc
-	cardLabelForProcess   = append(colcommon.CardLabel, "process_id", "container_id")
+	cardLabelForProcess   = append(colcommon.CardLabel, processID, "container_id", processName)
 	cardLabelForContainer []string
 	cardLabelForNpuName   = make([]string, len(colcommon.CardLabel))
 )
@@ -54,14 +61,15 @@ var (
 	descDevProcessNum = colcommon.BuildDesc("npu_chip_info_process_info_num",
 		"the npu process num")
 
-	descDevProcessInfo = colcommon.BuildDescWithLabel("npu_chip_info_process_info",
-		"the npu process info, unit is 'MB'. if process run on host, container_id and container_name will be empty",
-		cardLabelForProcess)
+	descDevProcessInfo = colcommon.BuildDescWithOpts("npu_chip_info_process_info",
+		"the npu process info, unit is 'MB'. the container of process is resolved by its cgroup, "+
+			"if process run on host, container_id and container_name will be empty",
+		cardLabelForProcess, colcommon.WithSeriesLabels(processID))
 
 	// net status
 	descNetworkStatus = colcommon.BuildDesc("npu_chip_info_network_status", "the npu network health status")
 
-	// container (vnpu not support this metrics), only report to prometheus
+	// container (vnpu not support this metrics)
 	npuCtrUtilization = colcommon.BuildDesc("container_npu_utilization",
 		"npu ai core utilization in container, unit is '%'")
 	npuCtrTotalMemory = colcommon.BuildDesc("container_npu_total_memory",
@@ -80,15 +88,15 @@ func init() {
 		colcommon.BuildDescSlice(&errorCodeDescs, "npu_chip_info_error_code_"+strconv.Itoa(i), "the npu error code")
 	}
 
-	cardLabelForContainer = append(colcommon.CardLabel, "containerID", "containerName")
+	cardLabelForContainer = append(colcommon.CardLabel, "containerID", containerNameLabel)
 	cardLabelForContainer[0] = "npuID"
-	npuCtrInfo = colcommon.BuildDescWithLabel("npu_container_info", "the container name and deviceID relationship",
-		cardLabelForContainer)
+	npuCtrInfo = colcommon.BuildDescWithOpts("npu_container_info", "the container name and deviceID relationship",
+		cardLabelForContainer, colcommon.WithInfoLabel(containerNameLabel))
 
 	copy(cardLabelForNpuName, colcommon.CardLabel)
-	cardLabelForNpuName[1] = "name"
-	descNpuName = colcommon.BuildDescWithLabel("npu_chip_info_name", "the Ascend npu name with value '1'",
-		cardLabelForNpuName)
+	cardLabelForNpuName[1] = npuNameLabel
+	descNpuName = colcommon.BuildDescWithOpts("npu_chip_info_name", "the Ascend npu name with value '1'",
+		cardLabelForNpuName, colcommon.WithInfoLabel(npuNameLabel))
 }
 
 type chipCache struct {
@@ -117,6 +125,8 @@ type chipCache struct {
 	NetHealthStatus string `json:"net_health_status"`
 	// DevProcessInfo chip process info
 	DevProcessInfo *common.DevProcessInfo
+	// processOwners the container and command of processes keyed by pid, the process not visible is absent
+	processOwners map[int32]container.ProcessOwner
 }
 
 // BaseInfoCollector collects the base info of the chip
@@ -209,43 +219,46 @@ func collectPower(logicID int32, dmgr devmanager.DeviceInterface, chip *chipCach
 	}
 }
 
-// UpdatePrometheus updates the base info of the chip
-func (c *BaseInfoCollector) UpdatePrometheus(ch chan<- prometheus.Metric, n *colcommon.NpuCollector,
+// UpdateSamples emit npu base info samples from cache
+func (c *BaseInfoCollector) UpdateSamples(sink colcommon.SampleSink, n *colcommon.NpuCollector,
 	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) {
 
-	updateSingleChip := func(chipWithVnpu colcommon.HuaWeiAIChip, cache chipCache, cardLabel []string) {
+	containersByID := colcommon.GetContainersByID(n)
+	updateSingleChip := func(chipSink colcommon.SampleSink, chipWithVnpu colcommon.HuaWeiAIChip, cache chipCache,
+		cardLabel []string) {
 		containerInfo := geenContainerInfo(&chipWithVnpu, containerMap)
 		timestamp := cache.timestamp
-		doUpdateMetricWithValidateNum(ch, timestamp, float64(cache.Power), cardLabel, descPower)
-		doUpdateMetricWithValidateNum(ch, timestamp, float64(cache.Voltage), cardLabel, descVoltage)
-		doUpdateMetricWithValidateNum(ch, timestamp, float64(cache.AICoreCurrentFreq), cardLabel, descAICoreFreq)
-		doUpdateMetricWithValidateNum(ch, timestamp, float64(cache.Temperature), cardLabel, descTemp)
-		doUpdateMetricWithValidateNum(ch, timestamp, float64(cache.Utilization), cardLabel, descUtil)
-		doUpdateMetricWithValidateNum(ch, timestamp, float64(cache.OverallUtilization), cardLabel, descOverUtil)
-		doUpdateMetricWithValidateNum(ch, timestamp, float64(cache.VectorUtilization), cardLabel, descVectorUtil)
-		doUpdateMetricWithValidateNum(ch, timestamp, 1, cardLabel, descNpuName)
-		doUpdateMetricWithValidateNum(ch, timestamp, float64(getHealthCode(cache.HealthStatus)), cardLabel, descHealthStatus)
-		doUpdateMetricWithValidateNum(ch, timestamp, float64(getHealthCode(cache.NetHealthStatus)),
+		doUpdateMetricWithValidateNum(chipSink, timestamp, float64(cache.Power), cardLabel, descPower)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, float64(cache.Voltage), cardLabel, descVoltage)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, float64(cache.AICoreCurrentFreq), cardLabel, descAICoreFreq)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, float64(cache.Temperature), cardLabel, descTemp)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, float64(cache.Utilization), cardLabel, descUtil)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, float64(cache.OverallUtilization), cardLabel, descOverUtil)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, float64(cache.VectorUtilization), cardLabel, descVectorUtil)
+		doUpdateInfoMetric(chipSink, timestamp, chipName(chipWithVnpu), cardLabel, descNpuName)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, float64(getHealthCode(cache.HealthStatus)),
+			cardLabel, descHealthStatus)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, float64(getHealthCode(cache.NetHealthStatus)),
 			cardLabel, descNetworkStatus)
 
-		updateContainerInfo(ch, containerInfo, cardLabel, &cache, chipWithVnpu)
+		updateContainerInfo(chipSink, containerInfo, cardLabel, &cache, chipWithVnpu)
 
-		updateProcessInfoForPrometheus(ch, &cache, containerInfo, timestamp, cardLabel)
-		updateErrorCodesInfo(ch, &cache, timestamp, cardLabel)
+		updateProcessInfo(chipSink, &cache, containerInfo, containersByID, timestamp, cardLabel)
+		updateErrorCodesInfo(chipSink, &cache, timestamp, cardLabel)
 	}
-	updateFrame[chipCache](colcommon.GetCacheKey(c), n, containerMap, chips, updateSingleChip)
+	updateFrame[chipCache](colcommon.GetCacheKey(c), sink, n, containerMap, chips, updateSingleChip)
 
-	ch <- prometheus.MustNewConstMetric(machineInfoNPUDesc, prometheus.GaugeValue, float64(len(chips)))
+	doUpdateMetric(sink, time.Time{}, len(chips), nil, machineInfoNPUDesc)
 }
 
-func updateContainerInfo(ch chan<- prometheus.Metric, containerInfo container.DevicesInfo,
+func updateContainerInfo(sink colcommon.SampleSink, containerInfo container.DevicesInfo,
 	cardLabel []string, chip *chipCache, chipWithVnpu colcommon.HuaWeiAIChip) {
 	containerName := getContainerNameArray(containerInfo)
 	if len(containerName) != colcommon.ContainerNameLen {
 		return
 	}
 	// based on chipType , container_npu_total_memory、container_npu_used_memory reported in hbm or ddr group
-	doUpdateMetric(ch, chip.timestamp, 1, append(cardLabel, containerInfo.ID, strings.Join(containerName, "_")),
+	doUpdateMetric(sink, chip.timestamp, 1, append(cardLabel, containerInfo.ID, strings.Join(containerName, "_")),
 		npuCtrInfo)
 
 	// vnpu not support this metrics
@@ -254,107 +267,88 @@ func updateContainerInfo(ch chan<- prometheus.Metric, containerInfo container.De
 		return
 	}
 
-	doUpdateMetricWithValidateNum(ch, chip.timestamp, float64(chip.Utilization), cardLabel, npuCtrUtilization)
+	doUpdateMetricWithValidateNum(sink, chip.timestamp, float64(chip.Utilization), cardLabel, npuCtrUtilization)
 }
 
-func updateErrorCodesInfo(ch chan<- prometheus.Metric, chip *chipCache, timestamp time.Time, cardLabel []string) {
+func updateErrorCodesInfo(sink colcommon.SampleSink, chip *chipCache, timestamp time.Time, cardLabel []string) {
 	if len(chip.ErrorCodes) > common.MaxErrorCodeLen {
 		logger.Warnf("Error code number is larger than %v, only the first %v will be reported, "+
 			"all errorCode is: %v", common.MaxErrorCodeLen, common.MaxErrorCodeLen, chip.ErrorCodes)
 	}
 	for i := 0; i < len(chip.ErrorCodes) && i < len(errorCodeDescs); i++ {
-		doUpdateMetricWithValidateNum(ch, timestamp, float64(chip.ErrorCodes[i]), cardLabel, errorCodeDescs[i])
+		doUpdateMetricWithValidateNum(sink, timestamp, float64(chip.ErrorCodes[i]), cardLabel, errorCodeDescs[i])
 	}
 }
 
-func updateProcessInfoForPrometheus(ch chan<- prometheus.Metric, chip *chipCache,
-	containerInfo container.DevicesInfo, timestamp time.Time, cardLabel []string) {
+func updateProcessInfo(sink colcommon.SampleSink, chip *chipCache, containerInfo container.DevicesInfo,
+	containersByID map[string]container.DevicesInfo, timestamp time.Time, cardLabel []string) {
 	devProcessInfo := chip.DevProcessInfo
 	if devProcessInfo == nil {
 		return
 	}
-	doUpdateMetric(ch, timestamp, devProcessInfo.ProcNum, cardLabel, descDevProcessNum)
-
-	containerID := ""
-	containerName := ""
-	cNameArray := getContainerNameArray(containerInfo)
-	if len(cNameArray) == colcommon.ContainerNameLen {
-		containerID = containerInfo.ID
-		containerName = strings.Join(cNameArray, "_")
-	}
+	doUpdateMetric(sink, timestamp, devProcessInfo.ProcNum, cardLabel, descDevProcessNum)
+
+	chipContainer := newProcessContainer(containerInfo)
 	// containerName in process info is namespace_podName_containerName
-	cardLabel[len(cardLabel)-1] = containerName
+	cardLabel[len(cardLabel)-1] = chipContainer.name
 
 	if devProcessInfo.ProcNum == 0 {
-		doUpdateMetric(ch, timestamp, 0, append(cardLabel, "", containerID), descDevProcessInfo)
+		doUpdateMetric(sink, timestamp, 0, processLabels(cardLabel, "", "", chipContainer, false), descDevProcessInfo)
 		return
 	}
 
 	for i := int32(0); i < devProcessInfo.ProcNum; i++ {
 		procInfo := devProcessInfo.DevProcArray[i]
-		doUpdateMetric(ch, timestamp, procInfo.MemUsage,
-			append(cardLabel, strconv.FormatInt(int64(procInfo.Pid), colcommon.Base), containerID), descDevProcessInfo)
-	}
-}
-
-// UpdateTelegraf updates the base info of the chip
-func (c *BaseInfoCollector) UpdateTelegraf(fieldsMap map[string]map[string]interface{}, n *colcommon.NpuCollector,
-	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) map[string]map[string]interface{} {
-	caches := colcommon.GetInfoFromCache[chipCache](n, colcommon.GetCacheKey(c))
-	for _, chip := range chips {
-		cache, ok := caches[chip.PhyId]
+		pid := strconv.FormatInt(int64(procInfo.Pid), colcommon.Base)
+		owner, ok := chip.processOwners[procInfo.Pid]
 		if !ok {
+			// the process is not visible, the container using the chip is used
+			doUpdateMetric(sink, timestamp, procInfo.MemUsage, processLabels(cardLabel, pid, "", chipContainer, false),
+				descDevProcessInfo)
 			continue
 		}
-		fieldMap := getFieldMap(fieldsMap, cache.chip.LogicID)
-
-		doUpdateTelegrafWithValidateNum(fieldMap, descTemp, float64(cache.Temperature), "")
-		doUpdateTelegrafWithValidateNum(fieldMap, descPower, float64(cache.Power), "")
-		doUpdateTelegrafWithValidateNum(fieldMap, descVoltage, float64(cache.Voltage), "")
-		doUpdateTelegrafWithValidateNum(fieldMap, descAICoreFreq, float64(cache.AICoreCurrentFreq), "")
-		doUpdateTelegrafWithValidateNum(fieldMap, descUtil, float64(cache.Utilization), "")
-		doUpdateTelegrafWithValidateNum(fieldMap, descVectorUtil, float64(cache.VectorUtilization), "")
-		doUpdateTelegrafWithValidateNum(fieldMap, descOverUtil, float64(cache.OverallUtilization), "")
-		doUpdateTelegrafWithValidateNum(fieldMap, descHealthStatus, float64(getHealthCode(cache.HealthStatus)), "")
-		doUpdateTelegrafWithValidateNum(fieldMap, descNetworkStatus, float64(getHealthCode(cache.NetHealthStatus)), "")
-		doUpdateTelegraf(fieldMap, descNpuName, chip.ChipInfo.Name, "")
-
-		updateProcessInfoForTelegraf(&cache, fieldMap)
-		updateErrorCode(&cache, fieldMap)
+		procContainer := processContainer{id: owner.ContainerID}
+		if info, found := containersByID[owner.ContainerID]; found && owner.ContainerID != "" {
+			procContainer = newProcessContainer(info)
+		}
+		doUpdateMetric(sink, timestamp, procInfo.MemUsage,
+			processLabels(cardLabel, pid, owner.Command, procContainer, true), descDevProcessInfo)
 	}
+}
 
-	if fieldsMap[colcommon.GeneralDevTagKey] == nil {
-		fieldsMap[colcommon.GeneralDevTagKey] = make(map[string]interface{})
-	}
-	doUpdateTelegraf(fieldsMap[colcommon.GeneralDevTagKey], machineInfoNPUDesc, len(chips), "")
-	return fieldsMap
+// processContainer the container of process, name is namespace_podName_containerName
+type processContainer struct {
+	namespace string
+	pod       string
+	name      string
+	id        string
 }
 
-func updateErrorCode(chip *chipCache, fieldMap map[string]interface{}) {
-	if len(errorCodeDescs) == 0 {
-		return
+func newProcessContainer(info container.DevicesInfo) processContainer {
+	names := getContainerNameArray(info)
+	if len(names) != colcommon.ContainerNameLen {
+		return processContainer{}
 	}
-	descErrorCode := errorCodeDescs[0]
-	for i := 0; i < len(chip.ErrorCodes); i++ {
-		extInfo := ""
-		if i != 0 {
-			extInfo = "_" + strconv.Itoa(i)
-		}
-		doUpdateTelegrafWithValidateNum(fieldMap, descErrorCode, float64(chip.ErrorCodes[i]), extInfo)
+	return processContainer{
+		namespace: names[colcommon.NameSpaceIdx],
+		pod:       names[colcommon.PodNameIdx],
+		name:      strings.Join(names, "_"),
+		id:        info.ID,
 	}
 }
 
-func updateProcessInfoForTelegraf(chip *chipCache, fieldMap map[string]interface{}) {
-	devProcessInfo := chip.DevProcessInfo
-	doUpdateTelegraf(fieldMap, descDevProcessNum, devProcessInfo.ProcNum, "")
-	if devProcessInfo.ProcNum == 0 {
-		doUpdateTelegraf(fieldMap, descDevProcessInfo, 0, "")
-		return
-	}
-	for i := int32(0); i < devProcessInfo.ProcNum; i++ {
-		procInfo := devProcessInfo.DevProcArray[i]
-		doUpdateTelegraf(fieldMap, descDevProcessInfo, procInfo.MemUsage, "_"+strconv.Itoa(int(procInfo.Pid)))
+// processLabels the labels of process info, the namespace and pod of chip are replaced by the container of process
+// when it is resolved by cgroup
+func processLabels(cardLabel []string, pid, command string, c processContainer, resolved bool) []string {
+	labels := make([]string, len(cardLabel), len(cardLabel)+len(cardLabelForProcess)-len(colcommon.CardLabel))
+	copy(labels, cardLabel)
+	nameIdx := len(labels) - 1
+	labels[nameIdx] = c.name
+	if resolved {
+		labels[nameIdx-colcommon.ConNameIdx+colcommon.NameSpaceIdx] = c.namespace
+		labels[nameIdx-colcommon.ConNameIdx+colcommon.PodNameIdx] = c.pod
 	}
+	return append(labels, pid, c.id, command)
 }
 
 func collectUtil(logicID int32, dmgr devmanager.DeviceInterface, chip *chipCache) {
@@ -405,6 +399,14 @@ func getHealth(logicID int32, dmgr devmanager.DeviceInterface) string {
 	return colcommon.Healthy
 }
 
+// chipName the name of chip in telegraf field, such as 910B
+func chipName(chip colcommon.HuaWeiAIChip) string {
+	if chip.ChipInfo == nil {
+		return ""
+	}
+	return chip.ChipInfo.Name
+}
+
 func getHealthCode(health string) int {
 	if health == colcommon.Abnormal {
 		return common.RetError
@@ -429,4 +431,24 @@ func setProcessInfo(logicID int32, dmgr devmanager.DeviceInterface, hwChip *chip
 		info = &common.DevProcessInfo{}
 	}
 	hwChip.DevProcessInfo = info
+	hwChip.processOwners = getProcessOwners(info)
+}
+
+// getProcessOwners resolve the container of processes by their cgroup, the process not visible is skipped, and
+// none is resolved when the exporter is not running in host pid namespace
+func getProcessOwners(info *common.DevProcessInfo) map[int32]container.ProcessOwner {
+	owners := make(map[int32]container.ProcessOwner, info.ProcNum)
+	if !container.InHostPidNamespace() {
+		return owners
+	}
+	for i := int32(0); i < info.ProcNum && int(i) < len(info.DevProcArray); i++ {
+		pid := info.DevProcArray[i].Pid
+		owner, err := container.GetProcessOwner(pid)
+		if err != nil {
+			logger.Debugf("resolve container of process %d failed: %v", pid, err)
+			continue
+		}
+		owners[pid] = owner
+	}
+	return owners
 }
@@ -113,68 +113,36 @@ func (c *OpticalCollector) CollectToCache(n *colcommon.NpuCollector, chipList []
 	colcommon.UpdateCache[opticalCache](n, colcommon.GetCacheKey(c), &c.LocalCache)
 }
 
-// UpdatePrometheus update prometheus metrics
-func (c *OpticalCollector) UpdatePrometheus(ch chan<- prometheus.Metric, n *colcommon.NpuCollector,
+// UpdateSamples emit optical samples from cache
+func (c *OpticalCollector) UpdateSamples(sink colcommon.SampleSink, n *colcommon.NpuCollector,
 	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) {
 
-	updateSingleChip := func(chipWithVnpu colcommon.HuaWeiAIChip, cache opticalCache, cardLabel []string) {
+	updateSingleChip := func(chipSink colcommon.SampleSink, chipWithVnpu colcommon.HuaWeiAIChip, cache opticalCache,
+		cardLabel []string) {
 		opticalInfo := cache.extInfo
 		if opticalInfo == nil {
 			return
 		}
 		timestamp := cache.timestamp
-		doUpdateMetricWithValidateNum(ch, timestamp, opticalInfo.OpticalState, cardLabel, descOpticalState)
-		doUpdateMetricWithValidateNum(ch, timestamp, opticalInfo.OpticalVcc, cardLabel, descOpticalVcc)
-		doUpdateMetricWithValidateNum(ch, timestamp, opticalInfo.OpticalTemp, cardLabel, descOpticalTemp)
-
-		doUpdateMetricWithValidateNum(ch, timestamp, opticalInfo.OpticalTxPower0, cardLabel, descOpticalTxPower0)
-		doUpdateMetricWithValidateNum(ch, timestamp, opticalInfo.OpticalTxPower1, cardLabel, descOpticalTxPower1)
-		doUpdateMetricWithValidateNum(ch, timestamp, opticalInfo.OpticalTxPower2, cardLabel, descOpticalTxPower2)
-		doUpdateMetricWithValidateNum(ch, timestamp, opticalInfo.OpticalTxPower3, cardLabel, descOpticalTxPower3)
-
-		doUpdateMetricWithValidateNum(ch, timestamp, opticalInfo.OpticalRxPower0, cardLabel, descOpticalRxPower0)
-		doUpdateMetricWithValidateNum(ch, timestamp, opticalInfo.OpticalRxPower1, cardLabel, descOpticalRxPower1)
-		doUpdateMetricWithValidateNum(ch, timestamp, opticalInfo.OpticalRxPower2, cardLabel, descOpticalRxPower2)
-		doUpdateMetricWithValidateNum(ch, timestamp, opticalInfo.OpticalRxPower3, cardLabel, descOpticalRxPower3)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, opticalInfo.OpticalState, cardLabel, descOpticalState)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, opticalInfo.OpticalVcc, cardLabel, descOpticalVcc)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, opticalInfo.OpticalTemp, cardLabel, descOpticalTemp)
+
+		doUpdateMetricWithValidateNum(chipSink, timestamp, opticalInfo.OpticalTxPower0, cardLabel, descOpticalTxPower0)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, opticalInfo.OpticalTxPower1, cardLabel, descOpticalTxPower1)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, opticalInfo.OpticalTxPower2, cardLabel, descOpticalTxPower2)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, opticalInfo.OpticalTxPower3, cardLabel, descOpticalTxPower3)
+
+		doUpdateMetricWithValidateNum(chipSink, timestamp, opticalInfo.OpticalRxPower0, cardLabel, descOpticalRxPower0)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, opticalInfo.OpticalRxPower1, cardLabel, descOpticalRxPower1)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, opticalInfo.OpticalRxPower2, cardLabel, descOpticalRxPower2)
+		doUpdateMetricWithValidateNum(chipSink, timestamp, opticalInfo.OpticalRxPower3, cardLabel, descOpticalRxPower3)
 	}
 
-	updateFrame[opticalCache](colcommon.GetCacheKey(c), n, containerMap, chips, updateSingleChip)
+	updateFrame[opticalCache](colcommon.GetCacheKey(c), sink, n, containerMap, chips, updateSingleChip)
 
 }
 
-// UpdateTelegraf update telegraf metrics
-func (c *OpticalCollector) UpdateTelegraf(fieldsMap map[string]map[string]interface{}, n *colcommon.NpuCollector,
-	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) map[string]map[string]interface{} {
-
-	caches := colcommon.GetInfoFromCache[opticalCache](n, colcommon.GetCacheKey(c))
-	for _, chip := range chips {
-		cache, ok := caches[chip.PhyId]
-		if !ok {
-			continue
-		}
-		fieldMap := getFieldMap(fieldsMap, cache.chip.LogicID)
-
-		extInfo := cache.extInfo
-		if extInfo == nil {
-			continue
-		}
-		doUpdateTelegrafWithValidateNum(fieldMap, descOpticalState, extInfo.OpticalState, "")
-		doUpdateTelegrafWithValidateNum(fieldMap, descOpticalVcc, extInfo.OpticalVcc, "")
-		doUpdateTelegrafWithValidateNum(fieldMap, descOpticalTemp, extInfo.OpticalTemp, "")
-
-		doUpdateTelegrafWithValidateNum(fieldMap, descOpticalTxPower0, extInfo.OpticalTxPower0, "")
-		doUpdateTelegrafWithValidateNum(fieldMap, descOpticalTxPower1, extInfo.OpticalTxPower1, "")
-		doUpdateTelegrafWithValidateNum(fieldMap, descOpticalTxPower2, extInfo.OpticalTxPower2, "")
-		doUpdateTelegrafWithValidateNum(fieldMap, descOpticalTxPower3, extInfo.OpticalTxPower3, "")
-
-		doUpdateTelegrafWithValidateNum(fieldMap, descOpticalRxPower0, extInfo.OpticalRxPower0, "")
-		doUpdateTelegrafWithValidateNum(fieldMap, descOpticalRxPower1, extInfo.OpticalRxPower1, "")
-		doUpdateTelegrafWithValidateNum(fieldMap, descOpticalRxPower2, extInfo.OpticalRxPower2, "")
-		doUpdateTelegrafWithValidateNum(fieldMap, descOpticalRxPower3, extInfo.OpticalRxPower3, "")
-	}
-	return fieldsMap
-}
-
 func getMainOptInfo(opticalInfo map[string]string) *common.OpticalInfo {
 	mainOpticalInfo := common.OpticalInfo{}
 	mainOpticalInfo.OpticalTxPower0 = hccn.GetFloatDataFromStr(opticalInfo[txPower0], txPower0)
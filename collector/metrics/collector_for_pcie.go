@@ -32,32 +32,34 @@ const (
 	avgPcieBw  = "avgPcieBw"
 	minPcieBw  = "minPcieBw"
 	maxPcieBw  = "maxPcieBw"
-
-	avgPostfix = "_avgPcieBw"
-	minPostfix = "_minPcieBw"
-	maxPostfix = "_maxPcieBw"
 )
 
 var (
 	pcieBwLabel = append(colcommon.CardLabel, pcieBwType)
 
-	descRxPBW = colcommon.BuildDescWithLabel("npu_chip_info_pcie_rx_p_bw",
-		"the npu write bw to remote‘s speed, unit is 'MB/ms'", pcieBwLabel)
+	descRxPBW = colcommon.BuildDescWithOpts("npu_chip_info_pcie_rx_p_bw",
+		"the npu write bw to remote‘s speed, unit is 'MB/ms'", pcieBwLabel,
+		colcommon.WithSeriesLabels(pcieBwType))
 
-	descRxNpBW = colcommon.BuildDescWithLabel("npu_chip_info_pcie_rx_np_bw",
-		"the npu read bw's speed from remote, unit is 'MB/ms'", pcieBwLabel)
+	descRxNpBW = colcommon.BuildDescWithOpts("npu_chip_info_pcie_rx_np_bw",
+		"the npu read bw's speed from remote, unit is 'MB/ms'", pcieBwLabel,
+		colcommon.WithSeriesLabels(pcieBwType))
 
-	descRxCplBW = colcommon.BuildDescWithLabel("npu_chip_info_pcie_rx_cpl_bw",
-		"the npu reply remote read operate cpl's speed, unit is 'MB/ms'", pcieBwLabel)
+	descRxCplBW = colcommon.BuildDescWithOpts("npu_chip_info_pcie_rx_cpl_bw",
+		"the npu reply remote read operate cpl's speed, unit is 'MB/ms'", pcieBwLabel,
+		colcommon.WithSeriesLabels(pcieBwType))
 
-	descTxPBW = colcommon.BuildDescWithLabel("npu_chip_info_pcie_tx_p_bw",
-		"the npu receive remote write operate's speed, unit is 'MB/ms'", pcieBwLabel)
+	descTxPBW = colcommon.BuildDescWithOpts("npu_chip_info_pcie_tx_p_bw",
+		"the npu receive remote write operate's speed, unit is 'MB/ms'", pcieBwLabel,
+		colcommon.WithSeriesLabels(pcieBwType))
 
-	descTxNpBW = colcommon.BuildDescWithLabel("npu_chip_info_pcie_tx_np_bw",
-		"the npu receive remote read operate's speed, unit is 'MB/ms'", pcieBwLabel)
+	descTxNpBW = colcommon.BuildDescWithOpts("npu_chip_info_pcie_tx_np_bw",
+		"the npu receive remote read operate's speed, unit is 'MB/ms'", pcieBwLabel,
+		colcommon.WithSeriesLabels(pcieBwType))
 
-	descTxCplBW = colcommon.BuildDescWithLabel("npu_chip_info_pcie_tx_cpl_bw",
-		"the npu read cpl's responese bw speed from remote, unit is 'MB/ms'", pcieBwLabel)
+	descTxCplBW = colcommon.BuildDescWithOpts("npu_chip_info_pcie_tx_cpl_bw",
+		"the npu read cpl's responese bw speed from remote, unit is 'MB/ms'", pcieBwLabel,
+		colcommon.WithSeriesLabels(pcieBwType))
 )
 var (
 	supportedPcieDevices = map[string]bool{
@@ -109,11 +111,12 @@ func (c *PcieCollector) CollectToCache(n *colcommon.NpuCollector, chipList []col
 	colcommon.UpdateCache[pcieCache](n, colcommon.GetCacheKey(c), &c.LocalCache)
 }
 
-// UpdatePrometheus update prometheus metrics
-func (c *PcieCollector) UpdatePrometheus(ch chan<- prometheus.Metric, n *colcommon.NpuCollector,
+// UpdateSamples emit pcie bandwidth samples from cache
+func (c *PcieCollector) UpdateSamples(sink colcommon.SampleSink, n *colcommon.NpuCollector,
 	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) {
 
-	updateSingleChip := func(chipWithVnpu colcommon.HuaWeiAIChip, cache pcieCache, cardLabel []string) {
+	updateSingleChip := func(chipSink colcommon.SampleSink, chipWithVnpu colcommon.HuaWeiAIChip, cache pcieCache,
+		cardLabel []string) {
 		pcieBwInfo := cache.extInfo
 		if pcieBwInfo == nil {
 			return
@@ -126,108 +129,48 @@ func (c *PcieCollector) UpdatePrometheus(ch chan<- prometheus.Metric, n *colcomm
 
 		timestamp := cache.timestamp
 
-		updateAvgPcieBwInfo(ch, timestamp, pcieBwInfo, cardLabel)
-		updateMinPcieBwInfo(ch, timestamp, pcieBwInfo, cardLabel)
-		updateMaxPcieBwInfo(ch, timestamp, pcieBwInfo, cardLabel)
+		updateAvgPcieBwInfo(chipSink, timestamp, pcieBwInfo, cardLabel)
+		updateMinPcieBwInfo(chipSink, timestamp, pcieBwInfo, cardLabel)
+		updateMaxPcieBwInfo(chipSink, timestamp, pcieBwInfo, cardLabel)
 	}
 
-	updateFrame[pcieCache](colcommon.GetCacheKey(c), n, containerMap, chips, updateSingleChip)
+	updateFrame[pcieCache](colcommon.GetCacheKey(c), sink, n, containerMap, chips, updateSingleChip)
 
 }
 
-// UpdateTelegraf update telegraf metrics
-func (c *PcieCollector) UpdateTelegraf(fieldsMap map[string]map[string]interface{}, n *colcommon.NpuCollector,
-	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) map[string]map[string]interface{} {
-
-	caches := colcommon.GetInfoFromCache[pcieCache](n, colcommon.GetCacheKey(c))
-	for _, chip := range chips {
-		cache, ok := caches[chip.PhyId]
-		if !ok {
-			continue
-		}
-		fieldMap := getFieldMap(fieldsMap, cache.chip.LogicID)
-
-		extInfo := cache.extInfo
-		if extInfo == nil {
-			continue
-		}
-		doUpdateTelegraf(fieldMap, descTxPBW, extInfo.PcieTxPBw.PcieAvgBw, avgPostfix)
-		doUpdateTelegraf(fieldMap, descTxNpBW, extInfo.PcieTxNPBw.PcieAvgBw, avgPostfix)
-		doUpdateTelegraf(fieldMap, descTxCplBW, extInfo.PcieTxCPLBw.PcieAvgBw, avgPostfix)
-		doUpdateTelegraf(fieldMap, descRxPBW, extInfo.PcieRxPBw.PcieAvgBw, avgPostfix)
-		doUpdateTelegraf(fieldMap, descRxNpBW, extInfo.PcieRxNPBw.PcieAvgBw, avgPostfix)
-		doUpdateTelegraf(fieldMap, descRxCplBW, extInfo.PcieRxCPLBw.PcieAvgBw, avgPostfix)
-
-		doUpdateTelegraf(fieldMap, descTxPBW, extInfo.PcieTxPBw.PcieMinBw, minPostfix)
-		doUpdateTelegraf(fieldMap, descTxNpBW, extInfo.PcieTxNPBw.PcieMinBw, minPostfix)
-		doUpdateTelegraf(fieldMap, descTxCplBW, extInfo.PcieTxCPLBw.PcieMinBw, minPostfix)
-		doUpdateTelegraf(fieldMap, descRxPBW, extInfo.PcieRxPBw.PcieMinBw, minPostfix)
-		doUpdateTelegraf(fieldMap, descRxNpBW, extInfo.PcieRxNPBw.PcieMinBw, minPostfix)
-		doUpdateTelegraf(fieldMap, descRxCplBW, extInfo.PcieRxCPLBw.PcieMinBw, minPostfix)
-
-		doUpdateTelegraf(fieldMap, descTxPBW, extInfo.PcieTxPBw.PcieMaxBw, maxPostfix)
-		doUpdateTelegraf(fieldMap, descTxNpBW, extInfo.PcieTxNPBw.PcieMaxBw, maxPostfix)
-		doUpdateTelegraf(fieldMap, descTxCplBW, extInfo.PcieTxCPLBw.PcieMaxBw, maxPostfix)
-		doUpdateTelegraf(fieldMap, descRxPBW, extInfo.PcieRxPBw.PcieMaxBw, maxPostfix)
-		doUpdateTelegraf(fieldMap, descRxNpBW, extInfo.PcieRxNPBw.PcieMaxBw, maxPostfix)
-		doUpdateTelegraf(fieldMap, descRxCplBW, extInfo.PcieRxCPLBw.PcieMaxBw, maxPostfix)
-
-	}
-	return fieldsMap
-}
-
 func pcieBwLabelVal(cardLabels []string, pcieBwType string) []string {
 	return append(cardLabels, pcieBwType)
 }
 
-func metricWithPcieBw(labelsVal []string, metrics *prometheus.Desc, val float64, valType string) prometheus.Metric {
-	return prometheus.MustNewConstMetric(metrics, prometheus.GaugeValue, val, pcieBwLabelVal(labelsVal, valType)...)
-}
-
-func updateAvgPcieBwInfo(ch chan<- prometheus.Metric, timestamp time.Time, pcieBwInfo *common.PCIEBwStat,
+func updateAvgPcieBwInfo(sink colcommon.SampleSink, timestamp time.Time, pcieBwInfo *common.PCIEBwStat,
 	cardLabel []string) {
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descTxPBW, float64(pcieBwInfo.PcieTxPBw.PcieAvgBw), avgPcieBw))
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descTxNpBW, float64(pcieBwInfo.PcieTxNPBw.PcieAvgBw), avgPcieBw))
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descTxCplBW, float64(pcieBwInfo.PcieTxCPLBw.PcieAvgBw), avgPcieBw))
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descRxPBW, float64(pcieBwInfo.PcieRxPBw.PcieAvgBw), avgPcieBw))
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descRxNpBW, float64(pcieBwInfo.PcieRxNPBw.PcieAvgBw), avgPcieBw))
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descRxCplBW, float64(pcieBwInfo.PcieRxCPLBw.PcieAvgBw), avgPcieBw))
+	labels := pcieBwLabelVal(cardLabel, avgPcieBw)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieTxPBw.PcieAvgBw, labels, descTxPBW)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieTxNPBw.PcieAvgBw, labels, descTxNpBW)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieTxCPLBw.PcieAvgBw, labels, descTxCplBW)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieRxPBw.PcieAvgBw, labels, descRxPBW)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieRxNPBw.PcieAvgBw, labels, descRxNpBW)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieRxCPLBw.PcieAvgBw, labels, descRxCplBW)
 }
 
-func updateMinPcieBwInfo(ch chan<- prometheus.Metric, timestamp time.Time, pcieBwInfo *common.PCIEBwStat,
+func updateMinPcieBwInfo(sink colcommon.SampleSink, timestamp time.Time, pcieBwInfo *common.PCIEBwStat,
 	cardLabel []string) {
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descTxPBW, float64(pcieBwInfo.PcieTxPBw.PcieMinBw), minPcieBw))
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descTxNpBW, float64(pcieBwInfo.PcieTxNPBw.PcieMinBw), minPcieBw))
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descTxCplBW, float64(pcieBwInfo.PcieTxCPLBw.PcieMinBw), minPcieBw))
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descRxPBW, float64(pcieBwInfo.PcieRxPBw.PcieMinBw), minPcieBw))
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descRxNpBW, float64(pcieBwInfo.PcieRxNPBw.PcieMinBw), minPcieBw))
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descRxCplBW, float64(pcieBwInfo.PcieRxCPLBw.PcieMinBw), minPcieBw))
+	labels := pcieBwLabelVal(cardLabel, minPcieBw)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieTxPBw.PcieMinBw, labels, descTxPBW)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieTxNPBw.PcieMinBw, labels, descTxNpBW)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieTxCPLBw.PcieMinBw, labels, descTxCplBW)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieRxPBw.PcieMinBw, labels, descRxPBW)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieRxNPBw.PcieMinBw, labels, descRxNpBW)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieRxCPLBw.PcieMinBw, labels, descRxCplBW)
 }
 
-func updateMaxPcieBwInfo(ch chan<- prometheus.Metric, timestamp time.Time, pcieBwInfo *common.PCIEBwStat,
+func updateMaxPcieBwInfo(sink colcommon.SampleSink, timestamp time.Time, pcieBwInfo *common.PCIEBwStat,
 	cardLabel []string) {
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descTxPBW, float64(pcieBwInfo.PcieTxPBw.PcieMaxBw), maxPcieBw))
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descTxNpBW, float64(pcieBwInfo.PcieTxNPBw.PcieMaxBw), maxPcieBw))
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descTxCplBW, float64(pcieBwInfo.PcieTxCPLBw.PcieMaxBw), maxPcieBw))
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descRxPBW, float64(pcieBwInfo.PcieRxPBw.PcieMaxBw), maxPcieBw))
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descRxNpBW, float64(pcieBwInfo.PcieRxNPBw.PcieMaxBw), maxPcieBw))
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		metricWithPcieBw(cardLabel, descRxCplBW, float64(pcieBwInfo.PcieRxCPLBw.PcieMaxBw), maxPcieBw))
+	labels := pcieBwLabelVal(cardLabel, maxPcieBw)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieTxPBw.PcieMaxBw, labels, descTxPBW)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieTxNPBw.PcieMaxBw, labels, descTxNpBW)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieTxCPLBw.PcieMaxBw, labels, descTxCplBW)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieRxPBw.PcieMaxBw, labels, descRxPBW)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieRxNPBw.PcieMaxBw, labels, descRxNpBW)
+	doUpdateMetric(sink, timestamp, pcieBwInfo.PcieRxCPLBw.PcieMaxBw, labels, descRxCplBW)
 }
@@ -53,35 +53,49 @@ const (
 
 var (
 	// mac
-	descMacRxPauseNum  = colcommon.BuildDesc("npu_chip_mac_rx_pause_num", "npu interface receive mac-rx-pause-num")
-	descMacTxPauseNum  = colcommon.BuildDesc("npu_chip_mac_tx_pause_num", "npu interface receive mac-tx-pause-num")
-	descMacRxPfcPktNum = colcommon.BuildDesc("npu_chip_mac_rx_pfc_pkt_num", "npu interface receive mac-rx-pfc-pkt-num")
-	descMacTxPfcPktNum = colcommon.BuildDesc("npu_chip_mac_tx_pfc_pkt_num", "npu interface receive mac-tx-pfc-pkt-num")
-	descMacRxBadPktNum = colcommon.BuildDesc("npu_chip_mac_rx_bad_pkt_num", "npu interface receive mac-rx-bad-pkt-num")
-	descMacTxBadPktNum = colcommon.BuildDesc("npu_chip_mac_tx_bad_pkt_num", "npu interface receive mac-tx-bad-pkt-num")
-	descMacTxBadOctNum = colcommon.BuildDesc("npu_chip_mac_tx_bad_oct_num", "npu interface receive mac-tx-bad-oct-num")
-	descMacRxBadOctNum = colcommon.BuildDesc("npu_chip_mac_rx_bad_oct_num", "npu interface receive mac-rx-bad-oct-num")
-
-	descRxFCSNum = colcommon.BuildDesc("npu_chip_info_rx_fcs_num", "the npu network fcs receive number")
-	descRxECNNum = colcommon.BuildDesc("npu_chip_info_rx_ecn_num", "the npu network ecn receive number")
+	descMacRxPauseNum = colcommon.BuildCounterDesc("npu_chip_mac_rx_pause_num",
+		"npu interface receive mac-rx-pause-num")
+	descMacTxPauseNum = colcommon.BuildCounterDesc("npu_chip_mac_tx_pause_num",
+		"npu interface receive mac-tx-pause-num")
+	descMacRxPfcPktNum = colcommon.BuildCounterDesc("npu_chip_mac_rx_pfc_pkt_num",
+		"npu interface receive mac-rx-pfc-pkt-num")
+	descMacTxPfcPktNum = colcommon.BuildCounterDesc("npu_chip_mac_tx_pfc_pkt_num",
+		"npu interface receive mac-tx-pfc-pkt-num")
+	descMacRxBadPktNum = colcommon.BuildCounterDesc("npu_chip_mac_rx_bad_pkt_num",
+		"npu interface receive mac-rx-bad-pkt-num")
+	descMacTxBadPktNum = colcommon.BuildCounterDesc("npu_chip_mac_tx_bad_pkt_num",
+		"npu interface receive mac-tx-bad-pkt-num")
+	descMacTxBadOctNum = colcommon.BuildCounterDesc("npu_chip_mac_tx_bad_oct_num",
+		"npu interface receive mac-tx-bad-oct-num")
+	descMacRxBadOctNum = colcommon.BuildCounterDesc("npu_chip_mac_rx_bad_oct_num",
+		"npu interface receive mac-rx-bad-oct-num")
+
+	descRxFCSNum = colcommon.BuildCounterDesc("npu_chip_info_rx_fcs_num", "the npu network fcs receive number")
+	descRxECNNum = colcommon.BuildCounterDesc("npu_chip_info_rx_ecn_num", "the npu network ecn receive number")
 
 	// roce
-	descRoceRxAllPktNum = colcommon.BuildDesc("npu_chip_roce_rx_all_pkt_num", "npu interface receive roce-rx-all-pkt-num")
-	descRoceTxAllPktNum = colcommon.BuildDesc("npu_chip_roce_tx_all_pkt_num", "npu interface receive roce-tx-all-pkt-num")
-	descRoceRxErrPktNum = colcommon.BuildDesc("npu_chip_roce_rx_err_pkt_num", "npu interface receive roce-rx-err-pkt-num")
-	descRoceTxErrPktNum = colcommon.BuildDesc("npu_chip_roce_tx_err_pkt_num", "npu interface receive roce-tx-err-pkt-num")
-	descRoceRxCnpPktNum = colcommon.BuildDesc("npu_chip_roce_rx_cnp_pkt_num", "npu interface receive roce-rx-cnp-pkt-num")
-	descRoceTxCnpPktNum = colcommon.BuildDesc("npu_chip_roce_tx_cnp_pkt_num", "npu interface receive roce-tx-cnp-pkt-num")
-
-	descRoceNewPktRtyNum = colcommon.BuildDesc("npu_chip_roce_new_pkt_rty_num",
+	descRoceRxAllPktNum = colcommon.BuildCounterDesc("npu_chip_roce_rx_all_pkt_num",
+		"npu interface receive roce-rx-all-pkt-num")
+	descRoceTxAllPktNum = colcommon.BuildCounterDesc("npu_chip_roce_tx_all_pkt_num",
+		"npu interface receive roce-tx-all-pkt-num")
+	descRoceRxErrPktNum = colcommon.BuildCounterDesc("npu_chip_roce_rx_err_pkt_num",
+		"npu interface receive roce-rx-err-pkt-num")
+	descRoceTxErrPktNum = colcommon.BuildCounterDesc("npu_chip_roce_tx_err_pkt_num",
+		"npu interface receive roce-tx-err-pkt-num")
+	descRoceRxCnpPktNum = colcommon.BuildCounterDesc("npu_chip_roce_rx_cnp_pkt_num",
+		"npu interface receive roce-rx-cnp-pkt-num")
+	descRoceTxCnpPktNum = colcommon.BuildCounterDesc("npu_chip_roce_tx_cnp_pkt_num",
+		"npu interface receive roce-tx-cnp-pkt-num")
+
+	descRoceNewPktRtyNum = colcommon.BuildCounterDesc("npu_chip_roce_new_pkt_rty_num",
 		"npu interface receive roce-new-pkt-rty-num")
-	descRoceOutOfOrderNum = colcommon.BuildDesc("npu_chip_roce_out_of_order_num",
+	descRoceOutOfOrderNum = colcommon.BuildCounterDesc("npu_chip_roce_out_of_order_num",
 		"the npu interface receive roce-out-of-order-num")
-	descRoceQpStatusErrNum = colcommon.BuildDesc("npu_chip_roce_qp_status_err_num",
+	descRoceQpStatusErrNum = colcommon.BuildCounterDesc("npu_chip_roce_qp_status_err_num",
 		"the npu interface receive roce-qp-status-err-num")
-	descRoceUnexpectedAcktNum = colcommon.BuildDesc("npu_chip_roce_unexpected_ack_num",
+	descRoceUnexpectedAcktNum = colcommon.BuildCounterDesc("npu_chip_roce_unexpected_ack_num",
 		"the npu interface receive roce-unexpected-ack-num")
-	descRoceVerificationErrNum = colcommon.BuildDesc("npu_chip_roce_verification_err_num",
+	descRoceVerificationErrNum = colcommon.BuildCounterDesc("npu_chip_roce_verification_err_num",
 		"the npu interface receive roce-verification-err-num")
 )
 
@@ -150,63 +164,23 @@ func (c *RoceCollector) CollectToCache(n *colcommon.NpuCollector, chipList []col
 
 }
 
-// UpdatePrometheus update prometheus metrics
-func (c *RoceCollector) UpdatePrometheus(ch chan<- prometheus.Metric, n *colcommon.NpuCollector,
+// UpdateSamples emit roce samples from cache
+func (c *RoceCollector) UpdateSamples(sink colcommon.SampleSink, n *colcommon.NpuCollector,
 	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) {
 
-	updateSingleChip := func(chipWithVnpu colcommon.HuaWeiAIChip, cache roceCache, cardLabel []string) {
+	updateSingleChip := func(chipSink colcommon.SampleSink, chipWithVnpu colcommon.HuaWeiAIChip, cache roceCache,
+		cardLabel []string) {
 		statInfo := cache.extInfo
 		if statInfo == nil {
 			return
 		}
-		updateStatInfoOfMac(ch, cache.timestamp, statInfo, cardLabel)
-		updateStatInfoOfRoCE(ch, cache.timestamp, statInfo, cardLabel)
+		updateStatInfoOfMac(chipSink, cache.timestamp, statInfo, cardLabel)
+		updateStatInfoOfRoCE(chipSink, cache.timestamp, statInfo, cardLabel)
 	}
-	updateFrame[roceCache](colcommon.GetCacheKey(c), n, containerMap, chips, updateSingleChip)
+	updateFrame[roceCache](colcommon.GetCacheKey(c), sink, n, containerMap, chips, updateSingleChip)
 
 }
 
-// UpdateTelegraf update telegraf metrics
-func (c *RoceCollector) UpdateTelegraf(fieldsMap map[string]map[string]interface{}, n *colcommon.NpuCollector,
-	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) map[string]map[string]interface{} {
-
-	caches := colcommon.GetInfoFromCache[roceCache](n, colcommon.GetCacheKey(c))
-	for _, chip := range chips {
-		cache, ok := caches[chip.PhyId]
-		if !ok {
-			continue
-		}
-		fieldMap := getFieldMap(fieldsMap, cache.chip.LogicID)
-
-		extInfo := cache.extInfo
-		if extInfo == nil {
-			continue
-		}
-		doUpdateTelegraf(fieldMap, descMacRxPauseNum, extInfo.MacRxPauseNum, "")
-		doUpdateTelegraf(fieldMap, descMacTxPauseNum, extInfo.MacTxPauseNum, "")
-		doUpdateTelegraf(fieldMap, descMacRxPfcPktNum, extInfo.MacRxPfcPktNum, "")
-		doUpdateTelegraf(fieldMap, descMacTxPfcPktNum, extInfo.MacTxPfcPktNum, "")
-		doUpdateTelegraf(fieldMap, descMacRxBadPktNum, extInfo.MacRxBadPktNum, "")
-		doUpdateTelegraf(fieldMap, descMacTxBadPktNum, extInfo.MacTxBadPktNum, "")
-		doUpdateTelegraf(fieldMap, descMacTxBadOctNum, extInfo.MacTxBadOctNum, "")
-		doUpdateTelegraf(fieldMap, descMacRxBadOctNum, extInfo.MacRxBadOctNum, "")
-		doUpdateTelegraf(fieldMap, descRxFCSNum, extInfo.MacRXFcsErrPktNum, "")
-
-		doUpdateTelegraf(fieldMap, descRoceRxAllPktNum, extInfo.RoceRxAllPktNum, "")
-		doUpdateTelegraf(fieldMap, descRoceTxAllPktNum, extInfo.RoceTxAllPktNum, "")
-		doUpdateTelegraf(fieldMap, descRoceRxErrPktNum, extInfo.RoceRxErrPktNum, "")
-		doUpdateTelegraf(fieldMap, descRoceTxErrPktNum, extInfo.RoceTxErrPktNum, "")
-		doUpdateTelegraf(fieldMap, descRoceRxCnpPktNum, extInfo.RoceRxCnpPktNum, "")
-		doUpdateTelegraf(fieldMap, descRoceTxCnpPktNum, extInfo.RoceTxCnpPktNum, "")
-		doUpdateTelegraf(fieldMap, descRoceNewPktRtyNum, extInfo.RoceNewPktRtyNum, "")
-		doUpdateTelegraf(fieldMap, descRoceUnexpectedAcktNum, extInfo.RoceUnexpectedAckNum, "")
-		doUpdateTelegraf(fieldMap, descRoceOutOfOrderNum, extInfo.RoceOutOfOrderNum, "")
-		doUpdateTelegraf(fieldMap, descRoceVerificationErrNum, extInfo.RoceVerificationErrNum, "")
-		doUpdateTelegraf(fieldMap, descRoceQpStatusErrNum, extInfo.RoceQpStatusErrNum, "")
-		doUpdateTelegraf(fieldMap, descRxECNNum, extInfo.RoceEcnDBNum, "")
-	}
-	return fieldsMap
-}
 func getMainStatInfo(statInfo map[string]int) *common.StatInfo {
 	mainStatInfo := common.StatInfo{}
 	mainStatInfo.MacRxPauseNum = float64(statInfo[macRxMacPauseNum])
@@ -234,30 +208,29 @@ func getMainStatInfo(statInfo map[string]int) *common.StatInfo {
 	return &mainStatInfo
 }
 
-func updateStatInfoOfMac(ch chan<- prometheus.Metric, ts time.Time, statInfo *common.StatInfo, cardLabel []string) {
-	doUpdateMetric(ch, ts, statInfo.MacRxPauseNum, cardLabel, descMacRxPauseNum)
-	doUpdateMetric(ch, ts, statInfo.MacTxPauseNum, cardLabel, descMacTxPauseNum)
-	doUpdateMetric(ch, ts, statInfo.MacRxPfcPktNum, cardLabel, descMacRxPfcPktNum)
-	doUpdateMetric(ch, ts, statInfo.MacTxPfcPktNum, cardLabel, descMacTxPfcPktNum)
-	doUpdateMetric(ch, ts, statInfo.MacRxBadPktNum, cardLabel, descMacRxBadPktNum)
-	doUpdateMetric(ch, ts, statInfo.MacTxBadPktNum, cardLabel, descMacTxBadPktNum)
-	doUpdateMetric(ch, ts, statInfo.MacTxBadOctNum, cardLabel, descMacTxBadOctNum)
-	doUpdateMetric(ch, ts, statInfo.MacRxBadOctNum, cardLabel, descMacRxBadOctNum)
-	doUpdateMetric(ch, ts, statInfo.MacRXFcsErrPktNum, cardLabel, descRxFCSNum)
+func updateStatInfoOfMac(sink colcommon.SampleSink, ts time.Time, statInfo *common.StatInfo, cardLabel []string) {
+	doUpdateMetric(sink, ts, statInfo.MacRxPauseNum, cardLabel, descMacRxPauseNum)
+	doUpdateMetric(sink, ts, statInfo.MacTxPauseNum, cardLabel, descMacTxPauseNum)
+	doUpdateMetric(sink, ts, statInfo.MacRxPfcPktNum, cardLabel, descMacRxPfcPktNum)
+	doUpdateMetric(sink, ts, statInfo.MacTxPfcPktNum, cardLabel, descMacTxPfcPktNum)
+	doUpdateMetric(sink, ts, statInfo.MacRxBadPktNum, cardLabel, descMacRxBadPktNum)
+	doUpdateMetric(sink, ts, statInfo.MacTxBadPktNum, cardLabel, descMacTxBadPktNum)
+	doUpdateMetric(sink, ts, statInfo.MacTxBadOctNum, cardLabel, descMacTxBadOctNum)
+	doUpdateMetric(sink, ts, statInfo.MacRxBadOctNum, cardLabel, descMacRxBadOctNum)
+	doUpdateMetric(sink, ts, statInfo.MacRXFcsErrPktNum, cardLabel, descRxFCSNum)
 }
 
-func updateStatInfoOfRoCE(ch chan<- prometheus.Metric, ts time.Time, statInfo *common.StatInfo, cardLabel []string) {
-	doUpdateMetric(ch, ts, statInfo.RoceRxAllPktNum, cardLabel, descRoceRxAllPktNum)
-	doUpdateMetric(ch, ts, statInfo.RoceTxAllPktNum, cardLabel, descRoceTxAllPktNum)
-	doUpdateMetric(ch, ts, statInfo.RoceRxErrPktNum, cardLabel, descRoceRxErrPktNum)
-	doUpdateMetric(ch, ts, statInfo.RoceTxErrPktNum, cardLabel, descRoceTxErrPktNum)
-	doUpdateMetric(ch, ts, statInfo.RoceRxCnpPktNum, cardLabel, descRoceRxCnpPktNum)
-	doUpdateMetric(ch, ts, statInfo.RoceTxCnpPktNum, cardLabel, descRoceTxCnpPktNum)
-	doUpdateMetric(ch, ts, statInfo.RoceNewPktRtyNum, cardLabel, descRoceNewPktRtyNum)
-	doUpdateMetric(ch, ts, statInfo.RoceUnexpectedAckNum, cardLabel, descRoceUnexpectedAcktNum)
-	doUpdateMetric(ch, ts, statInfo.RoceOutOfOrderNum, cardLabel, descRoceOutOfOrderNum)
-	doUpdateMetric(ch, ts, statInfo.RoceVerificationErrNum, cardLabel, descRoceVerificationErrNum)
-	doUpdateMetric(ch, ts, statInfo.RoceQpStatusErrNum, cardLabel, descRoceQpStatusErrNum)
-	doUpdateMetric(ch, ts, statInfo.RoceEcnDBNum, cardLabel, descRxECNNum)
-	doUpdateMetric(ch, ts, statInfo.RoceEcnDBNum, cardLabel, descRxECNNum)
+func updateStatInfoOfRoCE(sink colcommon.SampleSink, ts time.Time, statInfo *common.StatInfo, cardLabel []string) {
+	doUpdateMetric(sink, ts, statInfo.RoceRxAllPktNum, cardLabel, descRoceRxAllPktNum)
+	doUpdateMetric(sink, ts, statInfo.RoceTxAllPktNum, cardLabel, descRoceTxAllPktNum)
+	doUpdateMetric(sink, ts, statInfo.RoceRxErrPktNum, cardLabel, descRoceRxErrPktNum)
+	doUpdateMetric(sink, ts, statInfo.RoceTxErrPktNum, cardLabel, descRoceTxErrPktNum)
+	doUpdateMetric(sink, ts, statInfo.RoceRxCnpPktNum, cardLabel, descRoceRxCnpPktNum)
+	doUpdateMetric(sink, ts, statInfo.RoceTxCnpPktNum, cardLabel, descRoceTxCnpPktNum)
+	doUpdateMetric(sink, ts, statInfo.RoceNewPktRtyNum, cardLabel, descRoceNewPktRtyNum)
+	doUpdateMetric(sink, ts, statInfo.RoceUnexpectedAckNum, cardLabel, descRoceUnexpectedAcktNum)
+	doUpdateMetric(sink, ts, statInfo.RoceOutOfOrderNum, cardLabel, descRoceOutOfOrderNum)
+	doUpdateMetric(sink, ts, statInfo.RoceVerificationErrNum, cardLabel, descRoceVerificationErrNum)
+	doUpdateMetric(sink, ts, statInfo.RoceQpStatusErrNum, cardLabel, descRoceQpStatusErrNum)
+	doUpdateMetric(sink, ts, statInfo.RoceEcnDBNum, cardLabel, descRxECNNum)
 }
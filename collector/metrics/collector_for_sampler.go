@@ -0,0 +1,296 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package metrics for general collector
+package metrics
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	colcommon "github.com/professorshandian/npu-exporter/collector/common"
+	"github.com/professorshandian/npu-exporter/collector/container"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	statLabel = "stat"
+	statMin   = "min"
+	statMax   = "max"
+	statMean  = "mean"
+	statP95   = "p95"
+
+	p95 = 0.95
+	// defaultSamplerRingSize max samples kept for every metric of chip when the collection window is unknown
+	defaultSamplerRingSize = 1000
+	// samplerWindowFactor the ring holds the samples of twice the collection window, so that the samples are not
+	// overwritten when the collection is delayed
+	samplerWindowFactor = 2
+	samplerHelp         = " sampled at high frequency, stat is min, max, mean or p95 in the collection window"
+)
+
+var (
+	// samplerInterval interval of high-frequency sampling, the sampler is disabled when it is zero
+	samplerInterval time.Duration
+
+	samplerLabel = append(colcommon.CardLabel, statLabel)
+
+	descUtilSampled = colcommon.BuildDescWithOpts("npu_chip_info_utilization_sampled",
+		"the ai core utilization"+samplerHelp, samplerLabel, colcommon.WithSeriesLabels(statLabel))
+	descOverUtilSampled = colcommon.BuildDescWithOpts("npu_chip_info_overall_utilization_sampled",
+		"the overall utilization of npu"+samplerHelp, samplerLabel, colcommon.WithSeriesLabels(statLabel))
+	descVectorUtilSampled = colcommon.BuildDescWithOpts("npu_chip_info_vector_utilization_sampled",
+		"the vector ai core utilization"+samplerHelp, samplerLabel, colcommon.WithSeriesLabels(statLabel))
+	descHbmBWUtilSampled = colcommon.BuildDescWithOpts("npu_chip_info_hbm_bandwidth_utilization_sampled",
+		"the npu hbm bandwidth util rate"+samplerHelp, samplerLabel, colcommon.WithSeriesLabels(statLabel))
+	descPowerSampled = colcommon.BuildDescWithOpts("npu_chip_info_power_sampled",
+		"the npu power"+samplerHelp, samplerLabel, colcommon.WithSeriesLabels(statLabel))
+
+	samplerDescs = []*prometheus.Desc{descUtilSampled, descOverUtilSampled, descVectorUtilSampled,
+		descHbmBWUtilSampled, descPowerSampled}
+)
+
+// SetSamplerInterval set the interval of high-frequency sampling, zero to disable the sampler
+func SetSamplerInterval(interval time.Duration) {
+	samplerInterval = interval
+}
+
+type sampleStats struct {
+	min  float64
+	max  float64
+	mean float64
+	p95  float64
+}
+
+// sampleRing ring buffer of the samples of one metric, the oldest is overwritten when it is full
+type sampleRing struct {
+	// size the capacity of ring, defaultSamplerRingSize when it is not positive
+	size   int
+	values []float64
+	next   int
+	full   bool
+}
+
+func (r *sampleRing) push(value float64) {
+	if r.values == nil {
+		size := r.size
+		if size <= 0 {
+			size = defaultSamplerRingSize
+		}
+		r.values = make([]float64, size)
+	}
+	r.values[r.next] = value
+	r.next = (r.next + 1) % len(r.values)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// drain get all samples and empty the ring
+func (r *sampleRing) drain() []float64 {
+	var res []float64
+	if r.full {
+		res = append(res, r.values[r.next:]...)
+	}
+	res = append(res, r.values[:r.next]...)
+	r.next = 0
+	r.full = false
+	return res
+}
+
+func calcStats(values []float64) (sampleStats, bool) {
+	if len(values) == 0 {
+		return sampleStats{}, false
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+	// nearest-rank percentile
+	idx := int(math.Ceil(p95*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sampleStats{
+		min:  sorted[0],
+		max:  sorted[len(sorted)-1],
+		mean: sum / float64(len(sorted)),
+		p95:  sorted[idx],
+	}, true
+}
+
+type samplerCache struct {
+	chip      colcommon.HuaWeiAIChip
+	timestamp time.Time
+	// stats summaries of the samples in the collection window, keyed by desc
+	stats map[*prometheus.Desc]sampleStats
+}
+
+// SamplerCollector samples utilization and power at sub-second interval, and summarizes the samples
+// of every collection window as min, max, mean and p95
+type SamplerCollector struct {
+	colcommon.MetricsCollectorAdapter
+	lock  sync.Mutex
+	rings map[int32]map[*prometheus.Desc]*sampleRing
+	// ringSize the capacity of rings, which holds all samples of the collection window
+	ringSize int
+}
+
+// samplerRingSize the capacity of ring holding the samples of the collection window
+func samplerRingSize(window time.Duration) int {
+	if samplerInterval <= 0 || window <= 0 {
+		return defaultSamplerRingSize
+	}
+	return int(samplerWindowFactor*window/samplerInterval) + 1
+}
+
+// IsSupported the sampler is supported when the interval of high-frequency sampling is set
+func (c *SamplerCollector) IsSupported(n *colcommon.NpuCollector) bool {
+	if samplerInterval <= 0 {
+		logger.Infof("high-frequency sampling is disabled, [%v] is not collected", colcommon.GetCacheKey(c))
+		return false
+	}
+	return true
+}
+
+// Describe description of the metric
+func (c *SamplerCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range samplerDescs {
+		ch <- desc
+	}
+}
+
+// StartBackground start sampling in background until ctx is done
+func (c *SamplerCollector) StartBackground(group *sync.WaitGroup, ctx context.Context, n *colcommon.NpuCollector) {
+	if samplerInterval <= 0 {
+		return
+	}
+	c.lock.Lock()
+	c.ringSize = samplerRingSize(colcommon.GetUpdateTime(n))
+	c.lock.Unlock()
+	logger.Infof("high-frequency sampling every %v, %d samples are kept for every metric", samplerInterval,
+		c.ringSize)
+	group.Add(1)
+	go func() {
+		defer group.Done()
+		ticker := time.NewTicker(samplerInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Info("received the stop signal,stop high-frequency sampling")
+				return
+			case <-ticker.C:
+				c.sampleOnce(n, colcommon.GetChipList(n))
+			}
+		}
+	}()
+}
+
+func (c *SamplerCollector) sampleOnce(n *colcommon.NpuCollector, chips []colcommon.HuaWeiAIChip) {
+	for _, chip := range chips {
+		logicID := chip.LogicID
+		cache := &chipCache{chip: chip}
+		collectUtil(logicID, n.Dmgr, cache)
+		collectPower(logicID, n.Dmgr, cache)
+		values := map[*prometheus.Desc]float64{
+			descUtilSampled:       float64(cache.Utilization),
+			descOverUtilSampled:   float64(cache.OverallUtilization),
+			descVectorUtilSampled: float64(cache.VectorUtilization),
+			descPowerSampled:      float64(cache.Power),
+		}
+		if supportedHbmDevices[n.Dmgr.GetDevType()] {
+			hbmInfo, err := n.Dmgr.GetDeviceHbmInfo(logicID)
+			handleErr(err, colcommon.DomainForHBM, logicID)
+			if err == nil && hbmInfo != nil {
+				values[descHbmBWUtilSampled] = float64(hbmInfo.BandWidthUtilRate)
+			}
+		}
+		c.push(logicID, values)
+	}
+}
+
+func (c *SamplerCollector) push(logicID int32, values map[*prometheus.Desc]float64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.rings == nil {
+		c.rings = make(map[int32]map[*prometheus.Desc]*sampleRing)
+	}
+	chipRings, ok := c.rings[logicID]
+	if !ok {
+		chipRings = make(map[*prometheus.Desc]*sampleRing, len(samplerDescs))
+		c.rings[logicID] = chipRings
+	}
+	for desc, value := range values {
+		if !validateNum(value) {
+			continue
+		}
+		ring, ok := chipRings[desc]
+		if !ok {
+			ring = &sampleRing{size: c.ringSize}
+			chipRings[desc] = ring
+		}
+		ring.push(value)
+	}
+}
+
+// drain summarize the samples of chip in the collection window and start a new window
+func (c *SamplerCollector) drain(logicID int32) map[*prometheus.Desc]sampleStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	res := make(map[*prometheus.Desc]sampleStats, len(samplerDescs))
+	for desc, ring := range c.rings[logicID] {
+		if stats, ok := calcStats(ring.drain()); ok {
+			res[desc] = stats
+		}
+	}
+	return res
+}
+
+// CollectToCache summarize the samples since last collection to cache
+func (c *SamplerCollector) CollectToCache(n *colcommon.NpuCollector, chipList []colcommon.HuaWeiAIChip) {
+	for _, chip := range chipList {
+		c.LocalCache.Store(chip.PhyId, samplerCache{chip: chip, timestamp: time.Now(), stats: c.drain(chip.LogicID)})
+	}
+	colcommon.UpdateCache[samplerCache](n, colcommon.GetCacheKey(c), &c.LocalCache)
+}
+
+// UpdateSamples emit high-frequency sampling summaries from cache
+func (c *SamplerCollector) UpdateSamples(sink colcommon.SampleSink, n *colcommon.NpuCollector,
+	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) {
+
+	updateSingleChip := func(chipSink colcommon.SampleSink, chipWithVnpu colcommon.HuaWeiAIChip, cache samplerCache,
+		cardLabel []string) {
+		for _, desc := range samplerDescs {
+			stats, ok := cache.stats[desc]
+			if !ok {
+				continue
+			}
+			doUpdateMetric(chipSink, cache.timestamp, stats.min, append(cardLabel, statMin), desc)
+			doUpdateMetric(chipSink, cache.timestamp, stats.max, append(cardLabel, statMax), desc)
+			doUpdateMetric(chipSink, cache.timestamp, stats.mean, append(cardLabel, statMean), desc)
+			doUpdateMetric(chipSink, cache.timestamp, stats.p95, append(cardLabel, statP95), desc)
+		}
+	}
+
+	updateFrame[samplerCache](colcommon.GetCacheKey(c), sink, n, containerMap, chips, updateSingleChip)
+}
@@ -0,0 +1,140 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package metrics for general collector
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/devmanager/common"
+	colcommon "github.com/professorshandian/npu-exporter/collector/common"
+	"github.com/professorshandian/npu-exporter/collector/container"
+)
+
+const (
+	statNum          = 4
+	mockUtil         = 30
+	mockHbmBWUtil    = 40
+	mockPower        = 100.5
+	mockSampleRounds = 3
+)
+
+func TestSampleRing(t *testing.T) {
+	convey.Convey("TestSampleRing", t, func() {
+		ring := &sampleRing{}
+		convey.Convey("drain the samples in push order", func() {
+			ring.push(1)
+			ring.push(num2)
+			convey.So(ring.drain(), convey.ShouldResemble, []float64{1, num2})
+			convey.So(ring.drain(), convey.ShouldBeEmpty)
+		})
+		convey.Convey("the oldest sample is overwritten when ring is full", func() {
+			for i := 0; i < defaultSamplerRingSize+num2; i++ {
+				ring.push(float64(i))
+			}
+			values := ring.drain()
+			convey.So(len(values), convey.ShouldEqual, defaultSamplerRingSize)
+			convey.So(values[0], convey.ShouldEqual, num2)
+			convey.So(values[defaultSamplerRingSize-1], convey.ShouldEqual, defaultSamplerRingSize+1)
+		})
+	})
+}
+
+func TestSamplerRingSize(t *testing.T) {
+	const minInterval, maxWindow = 10 * time.Millisecond, time.Minute
+	convey.Convey("TestSamplerRingSize", t, func() {
+		defer SetSamplerInterval(0)
+		convey.So(samplerRingSize(maxWindow), convey.ShouldEqual, defaultSamplerRingSize)
+		SetSamplerInterval(minInterval)
+		size := samplerRingSize(maxWindow)
+		// all samples of the window are kept at the minimum interval and maximum window
+		convey.So(size, convey.ShouldBeGreaterThanOrEqualTo, int(maxWindow/minInterval))
+		ring := &sampleRing{size: size}
+		for i := 0; i < int(maxWindow/minInterval); i++ {
+			ring.push(float64(i))
+		}
+		convey.So(ring.drain(), convey.ShouldHaveLength, int(maxWindow/minInterval))
+	})
+}
+
+func TestCalcStats(t *testing.T) {
+	const num20, num100 = 20, 100
+	convey.Convey("TestCalcStats", t, func() {
+		_, ok := calcStats(nil)
+		convey.So(ok, convey.ShouldBeFalse)
+
+		values := make([]float64, 0, num20)
+		for i := num20; i > 0; i-- {
+			values = append(values, float64(i*num5))
+		}
+		stats, ok := calcStats(values)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(stats.min, convey.ShouldEqual, num5)
+		convey.So(stats.max, convey.ShouldEqual, num100)
+		convey.So(stats.mean, convey.ShouldEqual, (num5+num100)/float64(num2))
+		convey.So(stats.p95, convey.ShouldEqual, num100-num5)
+	})
+}
+
+func TestSamplerCollectorIsSupported(t *testing.T) {
+	n := mockNewNpuCollector()
+	convey.Convey("TestSamplerCollectorIsSupported", t, func() {
+		defer SetSamplerInterval(0)
+		collector := &SamplerCollector{}
+		convey.So(collector.IsSupported(n), convey.ShouldBeFalse)
+		SetSamplerInterval(time.Duration(num100) * time.Millisecond)
+		convey.So(collector.IsSupported(n), convey.ShouldBeTrue)
+	})
+}
+
+func TestSamplerCollectorUpdateSamples(t *testing.T) {
+	n := mockNewNpuCollector()
+	chips := []colcommon.HuaWeiAIChip{{PhyId: 0, LogicID: 0, ChipInfo: &common.ChipInfo{Name: "910B"}}}
+	convey.Convey("TestSamplerCollectorUpdateSamples", t, func() {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+		patches.ApplyMethodReturn(n.Dmgr, "GetDevType", common.Ascend910B)
+		patches.ApplyMethodReturn(n.Dmgr, "GetDeviceUtilizationRate", uint32(mockUtil), nil)
+		patches.ApplyMethodReturn(n.Dmgr, "GetDevicePowerInfo", float32(mockPower), nil)
+		patches.ApplyMethodReturn(n.Dmgr, "GetDeviceHbmInfo",
+			&common.HbmInfo{BandWidthUtilRate: mockHbmBWUtil}, nil)
+
+		collector := &SamplerCollector{}
+		for i := 0; i < mockSampleRounds; i++ {
+			collector.sampleOnce(n, chips)
+		}
+		collector.CollectToCache(n, chips)
+
+		sink := colcommon.NewSampleSink()
+		collector.UpdateSamples(sink, n, map[int32]container.DevicesInfo{}, chips)
+		samples := sink.Samples()
+		convey.So(len(samples), convey.ShouldEqual, len(samplerDescs)*statNum)
+		fields := colcommon.ToTelegrafFields(samples, make(map[string]map[string]interface{}))
+		convey.So(fields["0"]["npu_chip_info_power_sampled_p95"], convey.ShouldEqual, mockPower)
+		convey.So(fields["0"]["npu_chip_info_hbm_bandwidth_utilization_sampled_max"], convey.ShouldEqual,
+			mockHbmBWUtil)
+
+		convey.Convey("a new window is started after collection", func() {
+			collector.CollectToCache(n, chips)
+			sink := colcommon.NewSampleSink()
+			collector.UpdateSamples(sink, n, map[int32]container.DevicesInfo{}, chips)
+			convey.So(sink.Samples(), convey.ShouldBeEmpty)
+		})
+	})
+}
@@ -27,9 +27,9 @@ import (
 )
 
 var (
-	descSioCrcTxErrCnt = colcommon.BuildDesc("npu_chip_info_sio_crc_tx_err_cnt",
+	descSioCrcTxErrCnt = colcommon.BuildCounterDesc("npu_chip_info_sio_crc_tx_err_cnt",
 		"sio transmitted error count between die")
-	descSioCrcRxErrCnt = colcommon.BuildDesc("npu_chip_info_sio_crc_rx_err_cnt",
+	descSioCrcRxErrCnt = colcommon.BuildCounterDesc("npu_chip_info_sio_crc_rx_err_cnt",
 		"sio received error count between die")
 )
 var (
@@ -80,40 +80,18 @@ func (c *SioCollector) CollectToCache(n *colcommon.NpuCollector, chipList []colc
 	colcommon.UpdateCache[sioCache](n, colcommon.GetCacheKey(c), &c.LocalCache)
 }
 
-// UpdatePrometheus update prometheus metrics
-func (c *SioCollector) UpdatePrometheus(ch chan<- prometheus.Metric, n *colcommon.NpuCollector,
+// UpdateSamples emit sio samples from cache
+func (c *SioCollector) UpdateSamples(sink colcommon.SampleSink, n *colcommon.NpuCollector,
 	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) {
 
-	updateSingleChip := func(chipWithVnpu colcommon.HuaWeiAIChip, cache sioCache, cardLabel []string) {
+	updateSingleChip := func(chipSink colcommon.SampleSink, chipWithVnpu colcommon.HuaWeiAIChip, cache sioCache,
+		cardLabel []string) {
 		extInfo := cache.extInfo
 		if extInfo == nil {
 			return
 		}
-		doUpdateMetric(ch, cache.timestamp, extInfo.TxErrCnt, cardLabel, descSioCrcTxErrCnt)
-		doUpdateMetric(ch, cache.timestamp, extInfo.RxErrCnt, cardLabel, descSioCrcRxErrCnt)
+		doUpdateMetric(chipSink, cache.timestamp, extInfo.TxErrCnt, cardLabel, descSioCrcTxErrCnt)
+		doUpdateMetric(chipSink, cache.timestamp, extInfo.RxErrCnt, cardLabel, descSioCrcRxErrCnt)
 	}
-	updateFrame[sioCache](colcommon.GetCacheKey(c), n, containerMap, chips, updateSingleChip)
-}
-
-// UpdateTelegraf update telegraf metrics
-func (c *SioCollector) UpdateTelegraf(fieldsMap map[string]map[string]interface{}, n *colcommon.NpuCollector,
-	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) map[string]map[string]interface{} {
-
-	caches := colcommon.GetInfoFromCache[sioCache](n, colcommon.GetCacheKey(c))
-	for _, chip := range chips {
-		cache, ok := caches[chip.PhyId]
-		if !ok {
-			continue
-		}
-		fieldMap := getFieldMap(fieldsMap, cache.chip.LogicID)
-
-		extInfo := cache.extInfo
-		if extInfo == nil {
-			continue
-		}
-
-		doUpdateTelegraf(fieldMap, descSioCrcTxErrCnt, extInfo.TxErrCnt, "")
-		doUpdateTelegraf(fieldMap, descSioCrcRxErrCnt, extInfo.RxErrCnt, "")
-	}
-	return fieldsMap
+	updateFrame[sioCache](colcommon.GetCacheKey(c), sink, n, containerMap, chips, updateSingleChip)
 }
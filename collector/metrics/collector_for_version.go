@@ -16,6 +16,8 @@
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/professorshandian/npu-exporter/collector/common"
@@ -23,9 +25,13 @@ import (
 	"github.com/professorshandian/npu-exporter/versions"
 )
 
+const (
+	exporterVersion = "exporterVersion"
+)
+
 var (
-	versionInfoDesc = common.BuildDescWithLabel("npu_exporter_version_info", "exporter version with value '1'",
-		[]string{"exporterVersion"})
+	versionInfoDesc = common.BuildDescWithOpts("npu_exporter_version_info", "exporter version with value '1'",
+		[]string{exporterVersion}, common.WithInfoLabel(exporterVersion))
 )
 
 // VersionCollector collect sio info
@@ -38,19 +44,13 @@ func (c *VersionCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- versionInfoDesc
 }
 
-// UpdatePrometheus update prometheus metric
-func (c *VersionCollector) UpdatePrometheus(ch chan<- prometheus.Metric, n *common.NpuCollector,
-	containerMap map[int32]container.DevicesInfo, chips []common.HuaWeiAIChip) {
-	ch <- prometheus.MustNewConstMetric(versionInfoDesc, prometheus.GaugeValue, 1, []string{versions.BuildVersion}...)
+// CollectToCache nothing to collect, the version is built in
+func (c *VersionCollector) CollectToCache(n *common.NpuCollector, chipList []common.HuaWeiAIChip) {
+	common.RecordGroupSuccess(common.GetCacheKey(c))
 }
 
-// UpdateTelegraf update telegraf metric
-func (c *VersionCollector) UpdateTelegraf(fieldsMap map[string]map[string]interface{}, n *common.NpuCollector,
-	containerMap map[int32]container.DevicesInfo, chips []common.HuaWeiAIChip) map[string]map[string]interface{} {
-
-	if fieldsMap[common.GeneralDevTagKey] == nil {
-		fieldsMap[common.GeneralDevTagKey] = make(map[string]interface{})
-	}
-	doUpdateTelegraf(fieldsMap[common.GeneralDevTagKey], versionInfoDesc, versions.BuildVersion, "")
-	return fieldsMap
+// UpdateSamples emit the version sample
+func (c *VersionCollector) UpdateSamples(sink common.SampleSink, n *common.NpuCollector,
+	containerMap map[int32]container.DevicesInfo, chips []common.HuaWeiAIChip) {
+	doUpdateMetric(sink, time.Time{}, 1, []string{versions.BuildVersion}, versionInfoDesc)
 }
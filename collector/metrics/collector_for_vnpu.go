@@ -91,11 +91,12 @@ func (c *VnpuCollector) CollectToCache(n *colcommon.NpuCollector, chipList []col
 	colcommon.UpdateCache[chipCache](n, colcommon.GetCacheKey(c), &c.LocalCache)
 }
 
-// UpdatePrometheus update prometheus metrics
-func (c *VnpuCollector) UpdatePrometheus(ch chan<- prometheus.Metric, n *colcommon.NpuCollector,
+// UpdateSamples emit vnpu samples from cache
+func (c *VnpuCollector) UpdateSamples(sink colcommon.SampleSink, n *colcommon.NpuCollector,
 	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) {
 
-	updateSingleChip := func(chipWithVnpu colcommon.HuaWeiAIChip, cache chipCache, cardLabel []string) {
+	updateSingleChip := func(chipSink colcommon.SampleSink, chipWithVnpu colcommon.HuaWeiAIChip, cache chipCache,
+		cardLabel []string) {
 		if chipWithVnpu.VDevActivityInfo == nil {
 			return
 		}
@@ -108,44 +109,16 @@ func (c *VnpuCollector) UpdatePrometheus(ch chan<- prometheus.Metric, n *colcomm
 			return
 		}
 		cardLabel = getPodDisplayInfo(&chipWithVnpu, containerName)
-		doUpdateMetric(ch, cache.timestamp, vDevActivityInfo.VDevAiCoreRate, cardLabel, podAiCoreUtilizationRate)
-		doUpdateMetric(ch, cache.timestamp, vDevActivityInfo.VDevTotalMem, cardLabel, podTotalMemory)
-		doUpdateMetric(ch, cache.timestamp, vDevActivityInfo.VDevUsedMem, cardLabel, podUsedMemory)
+		vDevSink := chipSink.WithDevKey(colcommon.VDevKey(chipWithVnpu.LogicID, vDevActivityInfo.VDevID))
+		doUpdateMetric(vDevSink, cache.timestamp, vDevActivityInfo.VDevAiCoreRate, cardLabel, podAiCoreUtilizationRate)
+		doUpdateMetric(vDevSink, cache.timestamp, vDevActivityInfo.VDevTotalMem, cardLabel, podTotalMemory)
+		doUpdateMetric(vDevSink, cache.timestamp, vDevActivityInfo.VDevUsedMem, cardLabel, podUsedMemory)
 	}
 
-	updateFrame[chipCache](colcommon.GetCacheKey(c), n, containerMap, chips, updateSingleChip)
+	updateFrame[chipCache](colcommon.GetCacheKey(c), sink, n, containerMap, chips, updateSingleChip)
 
 }
 
-// UpdateTelegraf update telegraf metrics
-func (c *VnpuCollector) UpdateTelegraf(fieldsMap map[string]map[string]interface{}, n *colcommon.NpuCollector,
-	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) map[string]map[string]interface{} {
-
-	caches := colcommon.GetInfoFromCache[chipCache](n, colcommon.GetCacheKey(c))
-	for _, chip := range chips {
-		cache, ok := caches[chip.PhyId]
-		if !ok {
-			continue
-		}
-
-		vDevActivityInfo := chip.VDevActivityInfo
-		if vDevActivityInfo == nil || !common.IsValidVDevID(vDevActivityInfo.VDevID) {
-			continue
-		}
-
-		devTagKey := strconv.Itoa(int(cache.chip.LogicID)) + "_" + strconv.Itoa(int(vDevActivityInfo.VDevID))
-
-		if fieldsMap[devTagKey] == nil {
-			fieldsMap[devTagKey] = make(map[string]interface{})
-		}
-
-		doUpdateTelegraf(fieldsMap[devTagKey], podAiCoreUtilizationRate, vDevActivityInfo.VDevAiCoreRate, "")
-		doUpdateTelegraf(fieldsMap[devTagKey], podTotalMemory, vDevActivityInfo.VDevTotalMem, "")
-		doUpdateTelegraf(fieldsMap[devTagKey], podUsedMemory, vDevActivityInfo.VDevUsedMem, "")
-	}
-	return fieldsMap
-}
-
 func getPodDisplayInfo(chip *colcommon.HuaWeiAIChip, containerName []string) []string {
 	if len(containerName) != colcommon.ContainerNameLen {
 		logger.Errorf("container name length %v is not %v", len(containerName), colcommon.ContainerNameLen)
@@ -75,7 +75,7 @@ func TestVnpuCollectorCollectToCache(t *testing.T) {
 	})
 }
 
-func TestVnpuCollectorUpdatePrometheus(t *testing.T) {
+func TestVnpuCollectorUpdateSamples(t *testing.T) {
 	collector := &VnpuCollector{}
 	n := mockNewNpuCollector()
 	containerMap := mockContainerInfo()
@@ -88,42 +88,37 @@ func TestVnpuCollectorUpdatePrometheus(t *testing.T) {
 		preHandleFunc func()
 		expectValue   int
 	}{
-		{name: "TestVnpuCollectorUpdatePrometheus_effective virtual device scenarios",
+		{name: "TestVnpuCollectorUpdateSamples_effective virtual device scenarios",
 			preHandleFunc: func() {},
 			expectValue:   vnpuMetricNum,
 		},
-		{name: "TestVnpuCollectorUpdatePrometheus_there is no container info",
+		{name: "TestVnpuCollectorUpdateSamples_there is no container info",
 			preHandleFunc: func() {
 				containerMap = map[int32]container.DevicesInfo{}
 			},
 			expectValue: 0,
 		},
-		{name: "TestVnpuCollectorUpdatePrometheus_the vdevid is invalid",
+		{name: "TestVnpuCollectorUpdateSamples_the vdevid is invalid",
 			preHandleFunc: func() {
 				chip.VDevActivityInfo.VDevID = invalidVnpuID
 			},
 			expectValue: 0,
 		},
-		{name: "TestVnpuCollectorUpdatePrometheus_there is no vdev info",
+		{name: "TestVnpuCollectorUpdateSamples_there is no vdev info",
 			preHandleFunc: func() {
 				chip.VDevActivityInfo = nil
 			},
 			expectValue: 0,
 		},
 	}
-	ch := make(chan prometheus.Metric, vnpuMetricNum)
-	defer close(ch)
 	for _, tt := range testCases {
 		convey.Convey(tt.name, t, func() {
 			tt.preHandleFunc()
-			collector.UpdatePrometheus(ch, n, containerMap, []colcommon.HuaWeiAIChip{chip})
-			convey.So(len(ch), convey.ShouldEqual, tt.expectValue)
-			//clean ch
-			for {
-				if len(ch) == 0 {
-					break
-				}
-				<-ch
+			sink := colcommon.NewSampleSink()
+			collector.UpdateSamples(sink, n, containerMap, []colcommon.HuaWeiAIChip{chip})
+			convey.So(len(sink.Samples()), convey.ShouldEqual, tt.expectValue)
+			for _, sample := range sink.Samples() {
+				convey.So(sample.DevKey, convey.ShouldEqual, "0_100")
 			}
 		})
 	}
@@ -140,17 +135,18 @@ func mockContainerInfo() map[int32]container.DevicesInfo {
 	return containerMap
 }
 
-func TestVnpuCollectorUpdateTelegraf(t *testing.T) {
+func TestVnpuCollectorRenderTelegraf(t *testing.T) {
 	collector := &VnpuCollector{}
 	n := mockNewNpuCollector()
 	containerMap := mockContainerInfo()
 	testChips := []colcommon.HuaWeiAIChip{{PhyId: 0}}
 	collector.CollectToCache(n, testChips)
 	chip := createValidVnpuChip()
-	convey.Convey("TestVnpuCollectorUpdateTelegraf", t, func() {
+	convey.Convey("TestVnpuCollectorRenderTelegraf", t, func() {
 		convey.Convey("effective virtual device scenarios", func() {
 			chipsWithVnpu := []colcommon.HuaWeiAIChip{chip}
-			newFieldMaps := collector.UpdateTelegraf(make(map[string]map[string]interface{}), n, containerMap, chipsWithVnpu)
+			samples := colcommon.GatherSamples(n, containerMap, chipsWithVnpu, []colcommon.MetricsCollector{collector})
+			newFieldMaps := colcommon.ToTelegrafFields(samples, make(map[string]map[string]interface{}))
 			convey.So(len(newFieldMaps), convey.ShouldEqual, 1)
 			convey.So(len(newFieldMaps["0_100"]), convey.ShouldEqual, vnpuMetricNum)
 		})
@@ -158,7 +154,8 @@ func TestVnpuCollectorUpdateTelegraf(t *testing.T) {
 			chip.VDevActivityInfo = nil
 			chipsWithVnpu := []colcommon.HuaWeiAIChip{chip}
 			containerMap = map[int32]container.DevicesInfo{}
-			newFieldMaps := collector.UpdateTelegraf(make(map[string]map[string]interface{}), n, containerMap, chipsWithVnpu)
+			samples := colcommon.GatherSamples(n, containerMap, chipsWithVnpu, []colcommon.MetricsCollector{collector})
+			newFieldMaps := colcommon.ToTelegrafFields(samples, make(map[string]map[string]interface{}))
 			convey.So(len(newFieldMaps), convey.ShouldEqual, 0)
 		})
 
@@ -184,13 +184,11 @@ func TestCollectToCache(t *testing.T) {
 	})
 }
 
-// TestUpdatePrometheus test UpdatePrometheus
-func TestUpdatePrometheus(t *testing.T) {
+// TestUpdateSamples test UpdateSamples and the rendering of samples
+func TestUpdateSamples(t *testing.T) {
 	n := mockNewNpuCollector()
 
-	convey.Convey("TestUpdatePrometheus", t, func() {
-
-		ch := make(chan prometheus.Metric, maxMetricsCount)
+	convey.Convey("TestUpdateSamples", t, func() {
 
 		patches := gomonkey.NewPatches()
 		defer patches.Reset()
@@ -207,43 +205,74 @@ func TestUpdatePrometheus(t *testing.T) {
 		mockRoceCache(n, chips, colcommon.GetCacheKey(&RoceCollector{}))
 		mockSioCache(n, chips, colcommon.GetCacheKey(&SioCollector{}))
 
-		for _, c := range collectorChain {
-			c.UpdatePrometheus(ch, n, containerInfos, chips)
-		}
+		samples := colcommon.GatherSamples(n, containerInfos, chips, collectorChain)
+		t.Logf("TestUpdateSamples len(samples):%v", len(samples))
+		convey.So(samples, convey.ShouldNotBeEmpty)
 
-		t.Logf("TestUpdatePrometheus len(ch):%v", len(ch))
-		convey.So(ch, convey.ShouldNotBeEmpty)
+		convey.Convey("every sample can be rendered as prometheus metric", func() {
+			for _, sample := range samples {
+				_, err := colcommon.ToPrometheusMetric(sample)
+				convey.So(err, convey.ShouldBeNil)
+			}
+		})
+
+		convey.Convey("every sample can be rendered as telegraf field", func() {
+			fieldsMap := colcommon.ToTelegrafFields(samples, make(map[string]map[string]interface{}))
+			t.Logf("fieldsMap len:%v", len(fieldsMap))
+			convey.So(fieldsMap, convey.ShouldNotBeEmpty)
+			convey.So(fieldsMap[colcommon.GeneralDevTagKey], convey.ShouldNotBeEmpty)
+			// the fields keep the type of values and the chip name
+			convey.So(fieldsMap[colcommon.GeneralDevTagKey]["machine_npu_nums"], convey.ShouldHaveSameTypeAs, 0)
+			convey.So(fieldsMap["0"]["npu_chip_info_name"], convey.ShouldEqual, chips[0].ChipInfo.Name)
+		})
 	})
 }
 
-// TestUpdateTelegraf test UpdateTelegraf
-func TestUpdateTelegraf(t *testing.T) {
-	n := mockNewNpuCollector()
-
-	convey.Convey("TestUpdatePrometheus", t, func() {
-
-		patches := gomonkey.NewPatches()
-		defer patches.Reset()
-		containerInfos := mockGetContainerNPUInfo()
-		chips := mockGetNPUChipList()
-
-		mockDdrCache(n, chips, colcommon.GetCacheKey(&DdrCollector{}))
-		mockHbmCache(n, chips, colcommon.GetCacheKey(&HbmCollector{}))
-		mockHccsCache(n, chips, colcommon.GetCacheKey(&HccsCollector{}))
-		mockNetInfoCache(n, chips, colcommon.GetCacheKey(&NetworkCollector{}))
-		mockChipCache(n, chips, colcommon.GetCacheKey(&BaseInfoCollector{}))
-		mockOpticalCache(n, chips, colcommon.GetCacheKey(&OpticalCollector{}))
-		mockPcieCache(n, chips, colcommon.GetCacheKey(&PcieCollector{}))
-		mockRoceCache(n, chips, colcommon.GetCacheKey(&RoceCollector{}))
-		mockSioCache(n, chips, colcommon.GetCacheKey(&SioCollector{}))
-		fieldsMap := make(map[string]map[string]interface{})
-
-		for _, c := range collectorChain {
-			c.UpdateTelegraf(fieldsMap, n, containerInfos, chips)
+// TestUpdateProcessInfo test the process is attributed to the container resolved by its cgroup
+func TestUpdateProcessInfo(t *testing.T) {
+	const (
+		chipContainerID = "chipContainerID"
+		procContainerID = "procContainerID"
+		unknownID       = "unknownContainerID"
+		hostPid         = 100
+		procPid         = 101
+		unknownPid      = 102
+		hiddenPid       = 103
+	)
+	convey.Convey("TestUpdateProcessInfo", t, func() {
+		chipContainer := container.DevicesInfo{ID: chipContainerID, Name: "ns1_pod1_ctr1"}
+		containersByID := map[string]container.DevicesInfo{
+			chipContainerID: chipContainer,
+			procContainerID: {ID: procContainerID, Name: "ns2_pod2_ctr2"},
 		}
-
-		t.Logf("fieldsMap len(ch):%v", len(fieldsMap))
-		convey.So(fieldsMap, convey.ShouldNotBeEmpty)
+		chip := &chipCache{
+			DevProcessInfo: &common.DevProcessInfo{ProcNum: 4, DevProcArray: []common.DevProcInfo{
+				{Pid: hostPid, MemUsage: 1}, {Pid: procPid, MemUsage: 2}, {Pid: unknownPid, MemUsage: 3},
+				{Pid: hiddenPid, MemUsage: 4}}},
+			processOwners: map[int32]container.ProcessOwner{
+				hostPid:    {Command: "host"},
+				procPid:    {ContainerID: procContainerID, Command: "train"},
+				unknownPid: {ContainerID: unknownID, Command: "other"},
+			},
+		}
+		cardLabel := []string{"0", "Ascend910", "0", "0000:01:00.0", "ns1", "pod1", "ctr1"}
+		sink := colcommon.NewSampleSink()
+		updateProcessInfo(sink, chip, chipContainer, containersByID, time.Now(), cardLabel)
+
+		got := make(map[string][]string)
+		for _, sample := range sink.Samples() {
+			if len(sample.LabelValues) == len(cardLabelForProcess) {
+				got[sample.Label(processID)] = sample.LabelValues[len(colcommon.CardLabel)-3:]
+			}
+		}
+		convey.So(got[strconv.Itoa(hostPid)], convey.ShouldResemble,
+			[]string{"", "", "", strconv.Itoa(hostPid), "", "host"})
+		convey.So(got[strconv.Itoa(procPid)], convey.ShouldResemble,
+			[]string{"ns2", "pod2", "ns2_pod2_ctr2", strconv.Itoa(procPid), procContainerID, "train"})
+		convey.So(got[strconv.Itoa(unknownPid)], convey.ShouldResemble,
+			[]string{"", "", "", strconv.Itoa(unknownPid), unknownID, "other"})
+		convey.So(got[strconv.Itoa(hiddenPid)], convey.ShouldResemble,
+			[]string{"ns1", "pod1", "ns1_pod1_ctr1", strconv.Itoa(hiddenPid), chipContainerID, ""})
 	})
 }
 
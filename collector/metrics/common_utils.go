@@ -31,23 +31,6 @@ import (
 	"github.com/professorshandian/npu-exporter/utils/logger"
 )
 
-// getDescName parse metrics name from prometheus.Desc object
-func getDescName(desc *prometheus.Desc) string {
-	str := desc.String()
-
-	startIndex := strings.Index(str, "fqName: ") + len("fqName: ")
-
-	readfqName := str[startIndex:]
-
-	endIndex := strings.Index(readfqName, ",")
-
-	if endIndex != -1 {
-		readfqName = readfqName[:endIndex]
-	}
-	readfqName = strings.Trim(readfqName, "\"")
-	return readfqName
-}
-
 func validateNum(num float64) bool {
 	if num == -1 || num == math.MaxUint32 || float32(num) == math.MaxUint32 {
 		return false
@@ -56,24 +39,13 @@ func validateNum(num float64) bool {
 	return true
 }
 
-func doUpdateTelegrafWithValidateNum(fieldMap map[string]interface{}, desc *prometheus.Desc,
-	value float64, extInfo string) {
-	if validateNum(value) {
-		doUpdateTelegraf(fieldMap, desc, value, extInfo)
-	}
-}
-
-func doUpdateTelegraf(fieldMap map[string]interface{}, desc *prometheus.Desc, value interface{}, extInfo string) {
-	fieldMap[getDescName(desc)+extInfo] = value
-}
-
-func doUpdateMetricWithValidateNum(ch chan<- prometheus.Metric, timestamp time.Time, value float64,
+func doUpdateMetricWithValidateNum(sink colcommon.SampleSink, timestamp time.Time, value float64,
 	cardLabel []string, desc *prometheus.Desc) {
 	if validateNum(value) {
-		doUpdateMetric(ch, timestamp, value, cardLabel, desc)
+		doUpdateMetric(sink, timestamp, value, cardLabel, desc)
 	}
 }
-func doUpdateMetric(ch chan<- prometheus.Metric, timestamp time.Time, value interface{},
+func doUpdateMetric(sink colcommon.SampleSink, timestamp time.Time, value interface{},
 	cardLabel []string, desc *prometheus.Desc) {
 	var finalValue float64
 
@@ -94,14 +66,34 @@ func doUpdateMetric(ch chan<- prometheus.Metric, timestamp time.Time, value inte
 		finalValue = value.(float64)
 	default:
 		logger.Errorf("invalid param in function doUpdateMetric,"+
-			"metrics name is (%v), value type is (%T),value is (%v)", getDescName(desc), value, value)
+			"metrics desc is (%v), value type is (%T),value is (%v)", desc, value, value)
 	}
 	// collect failed, set value to -1
 	if finalValue == common.FailedValue {
 		finalValue = common.FailedMetricValue
 	}
-	ch <- prometheus.NewMetricWithTimestamp(timestamp,
-		prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, finalValue, cardLabel...))
+	sample, err := colcommon.NewSample(desc, finalValue, timestamp, cardLabel)
+	if err != nil {
+		logger.Errorf("build sample failed: %v", err)
+		return
+	}
+	// the telegraf field keeps the type of value, and the info metric keeps its info label
+	if sample.InfoLabel == "" {
+		sample.FieldValue = value
+	}
+	sink.Add(sample)
+}
+
+// doUpdateInfoMetric emit info metric with value 1, the telegraf field is fieldValue instead of the info label
+func doUpdateInfoMetric(sink colcommon.SampleSink, timestamp time.Time, fieldValue interface{},
+	cardLabel []string, desc *prometheus.Desc) {
+	sample, err := colcommon.NewSample(desc, 1, timestamp, cardLabel)
+	if err != nil {
+		logger.Errorf("build sample failed: %v", err)
+		return
+	}
+	sample.FieldValue = fieldValue
+	sink.Add(sample)
 }
 
 func getContainerInfoWithDefault(cNameArray []string) (containerName, namespaceValue, podNameValue string) {
@@ -147,14 +139,6 @@ func getContainerNameArray(devInfo container.DevicesInfo) []string {
 	return strings.Split(devInfo.Name, "_")
 }
 
-func getFieldMap(fieldsMap map[string]map[string]interface{}, devTagKey int32) map[string]interface{} {
-	devTagKeyStr := strconv.Itoa(int(devTagKey))
-	if fieldsMap[devTagKeyStr] == nil {
-		fieldsMap[devTagKeyStr] = make(map[string]interface{})
-	}
-	return fieldsMap[devTagKeyStr]
-}
-
 func handleErr(err error, domain string, logicID int32) {
 	if err != nil {
 		logErrMetricsWithLimit(domain, logicID, err)
@@ -188,8 +172,10 @@ func logForUnSupportDevice(isSupport bool, devType string, group string, extInfo
 	}
 }
 
-func updateFrame[T any](cacheKey string, n *colcommon.NpuCollector, containerMap map[int32]container.DevicesInfo,
-	chips []colcommon.HuaWeiAIChip, callBack func(chipWithVnpu colcommon.HuaWeiAIChip, cache T, cardLabel []string)) {
+// updateFrame emit samples of every chip, the samples of chip are keyed by its logicID
+func updateFrame[T any](cacheKey string, sink colcommon.SampleSink, n *colcommon.NpuCollector,
+	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip,
+	callBack func(chipSink colcommon.SampleSink, chipWithVnpu colcommon.HuaWeiAIChip, cache T, cardLabel []string)) {
 
 	caches := colcommon.GetInfoFromCache[T](n, cacheKey)
 	if len(caches) == 0 {
@@ -204,6 +190,6 @@ func updateFrame[T any](cacheKey string, n *colcommon.NpuCollector, containerMap
 			continue
 		}
 
-		callBack(chip, cache, cardLabel)
+		callBack(sink.WithDevKey(colcommon.ChipDevKey(chip.LogicID)), chip, cache, cardLabel)
 	}
 }
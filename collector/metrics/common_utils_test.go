@@ -32,23 +32,6 @@ const (
 	num100     = 100
 )
 
-// TestGetDescName test GetDescName
-func TestGetDescName(t *testing.T) {
-	convey.Convey("TestGetDescName", t, func() {
-		desc := prometheus.NewDesc(
-			"test_metric",
-			"Test metric description",
-			[]string{"label1"},
-			nil,
-		)
-
-		convey.Convey("success to get metrics name", func() {
-			name := getDescName(desc)
-			convey.So(name, convey.ShouldEqual, "test_metric")
-		})
-	})
-}
-
 // TestValidateNum test numerical verification
 func TestValidateNum(t *testing.T) {
 	convey.Convey("TestValidateNum", t, func() {
@@ -64,20 +47,21 @@ func TestValidateNum(t *testing.T) {
 	})
 }
 
-// TestDoUpdateTelegraf test update telegraf
-func TestDoUpdateTelegraf(t *testing.T) {
-	convey.Convey("TestDoUpdateTelegraf", t, func() {
-		fieldMap := make(map[string]interface{})
-		desc := prometheus.NewDesc("test_metric", "", nil, nil)
+// TestDoUpdateMetricWithValidateNum test update metric with numerical verification
+func TestDoUpdateMetricWithValidateNum(t *testing.T) {
+	convey.Convey("TestDoUpdateMetricWithValidateNum", t, func() {
+		sink := colcommon.NewSampleSink()
+		desc := colcommon.BuildDescWithLabel("test_validate_metric", "", []string{"label"})
 
 		convey.Convey("update when num is valid", func() {
-			doUpdateTelegrafWithValidateNum(fieldMap, desc, num100, "_suffix")
-			convey.So(fieldMap["test_metric_suffix"], convey.ShouldEqual, num100)
+			doUpdateMetricWithValidateNum(sink, time.Now(), num100, []string{"label"}, desc)
+			convey.So(len(sink.Samples()), convey.ShouldEqual, 1)
+			convey.So(sink.Samples()[0].Value, convey.ShouldEqual, num100)
 		})
 
 		convey.Convey("don't update when num is invalid", func() {
-			doUpdateTelegrafWithValidateNum(fieldMap, desc, -1, "_suffix")
-			convey.So(fieldMap, convey.ShouldBeEmpty)
+			doUpdateMetricWithValidateNum(sink, time.Now(), invalidNum, []string{"label"}, desc)
+			convey.So(sink.Samples(), convey.ShouldBeEmpty)
 		})
 	})
 }
@@ -89,10 +73,11 @@ func TestDoUpdateMetric(t *testing.T) {
 		num100   = 100
 		negaNum  = -5
 		floatNum = 3.14
+		epsilon  = 1e-6
 	)
 	convey.Convey("TestDoUpdateMetric", t, func() {
-		ch := make(chan prometheus.Metric, 1)
-		desc := prometheus.NewDesc("test_metric", "", []string{"label"}, nil)
+		sink := colcommon.NewSampleSink()
+		desc := colcommon.BuildDescWithLabel("test_metric", "", []string{"label"})
 
 		convey.Convey("convert the various numeric types correctly", func() {
 			testCases := []struct {
@@ -105,12 +90,17 @@ func TestDoUpdateMetric(t *testing.T) {
 				{float32(floatNum), floatNum},
 			}
 
-			for _, tc := range testCases {
-				doUpdateMetric(ch, time.Now(), tc.input, []string{"label"}, desc)
-				m := <-ch
-				convey.So(m, convey.ShouldNotBeEmpty)
+			for i, tc := range testCases {
+				doUpdateMetric(sink, time.Now(), tc.input, []string{"label"}, desc)
+				convey.So(sink.Samples()[i].Value, convey.ShouldAlmostEqual, tc.expected, epsilon)
 			}
 		})
+
+		convey.Convey("don't update when the metric is not registered", func() {
+			unregistered := prometheus.NewDesc("test_unregistered_metric", "", []string{"label"}, nil)
+			doUpdateMetric(sink, time.Now(), num10, []string{"label"}, unregistered)
+			convey.So(sink.Samples(), convey.ShouldBeEmpty)
+		})
 	})
 }
 
@@ -0,0 +1,236 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package health for health and readiness check of npu-exporter
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/devmanager/hccn"
+	colcommon "github.com/professorshandian/npu-exporter/collector/common"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	// LivenessPath url path of the liveness probe
+	LivenessPath = "/healthz"
+	// ReadinessPath url path of the readiness probe
+	ReadinessPath = "/readyz"
+
+	checkDevManager       = "devmanager"
+	checkContainerRuntime = "container_runtime"
+	checkHccnTool         = "hccn_tool"
+	checkGroupPrefix      = "group:"
+
+	statusOK   = "ok"
+	statusFail = "fail"
+
+	// staleFactor a group is stale when it has not succeeded in staleFactor update cycles
+	staleFactor  = 3
+	minStaleTime = 10 * time.Second
+)
+
+var (
+	checkStatusLabels  = []string{"check", "critical"}
+	groupSuccessLabels = []string{"group"}
+
+	descCheckStatus = prometheus.NewDesc("npu_exporter_health_check_status",
+		"the result of health check, 1 means healthy, 0 means unhealthy", checkStatusLabels, nil)
+	descGroupLastSuccess = prometheus.NewDesc("npu_exporter_group_last_success_timestamp_seconds",
+		"the last time the metrics group finished a collect cycle, unit is 's'", groupSuccessLabels, nil)
+)
+
+func init() {
+	// the node labels are validated against the labels of health metrics
+	colcommon.RegisterLabelNames(checkStatusLabels...)
+	colcommon.RegisterLabelNames(groupSuccessLabels...)
+}
+
+// CheckResult result of a single subsystem check
+type CheckResult struct {
+	Name        string     `json:"name"`
+	Healthy     bool       `json:"healthy"`
+	Critical    bool       `json:"critical"`
+	Message     string     `json:"message,omitempty"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+}
+
+// Report result of all subsystem checks
+type Report struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Checker check the state of every subsystem of the collector
+type Checker struct {
+	collector *colcommon.NpuCollector
+	staleTime time.Duration
+}
+
+// NewChecker create an instance of Checker, updateTime is the collect interval of metrics groups
+func NewChecker(collector *colcommon.NpuCollector, updateTime time.Duration) *Checker {
+	staleTime := staleFactor * updateTime
+	if staleTime < minStaleTime {
+		staleTime = minStaleTime
+	}
+	return &Checker{collector: collector, staleTime: staleTime}
+}
+
+// Check run all checks, liveness only checks the process level subsystems,
+// readiness also requires every group to have succeeded recently
+func (c *Checker) Check(readiness bool) Report {
+	checks := []CheckResult{c.checkDevManager(readiness), c.checkContainerRuntime(readiness), checkHccn()}
+	if readiness {
+		checks = append(checks, c.checkGroups()...)
+	}
+	report := Report{Status: statusOK, Checks: checks}
+	for _, check := range checks {
+		if check.Critical && !check.Healthy {
+			report.Status = statusFail
+			break
+		}
+	}
+	return report
+}
+
+func (c *Checker) checkDevManager(readiness bool) CheckResult {
+	res := CheckResult{Name: checkDevManager, Critical: true, Healthy: true}
+	if c.collector == nil || c.collector.Dmgr == nil {
+		res.Healthy = false
+		res.Message = "device manager is not initialized"
+		return res
+	}
+	if readiness && len(colcommon.GetChipList(c.collector)) == 0 {
+		res.Healthy = false
+		res.Message = "no npu chip found"
+	}
+	return res
+}
+
+func (c *Checker) checkContainerRuntime(readiness bool) CheckResult {
+	status := colcommon.GetContainerRuntimeStatus()
+	res := CheckResult{Name: checkContainerRuntime, Critical: true, Healthy: true, Message: status.LastError}
+	if !status.LastSuccess.IsZero() {
+		lastSuccess := status.LastSuccess
+		res.LastSuccess = &lastSuccess
+	}
+	if status.Stopped {
+		res.Healthy = false
+		res.Message = fmt.Sprintf("container info collect is stopped: %s", status.LastError)
+		return res
+	}
+	if readiness && status.LastSuccess.IsZero() {
+		res.Healthy = false
+		if res.Message == "" {
+			res.Message = "container info has not been collected yet"
+		}
+	}
+	return res
+}
+
+func checkHccn() CheckResult {
+	res := CheckResult{Name: checkHccnTool, Healthy: true}
+	if err := hccn.CheckHccnTool(); err != nil {
+		res.Healthy = false
+		res.Message = err.Error()
+	}
+	return res
+}
+
+func (c *Checker) checkGroups() []CheckResult {
+	lastSuccess := colcommon.GetGroupLastSuccess()
+	chains := [][]colcommon.MetricsCollector{colcommon.ChainForSingleGoroutine, colcommon.ChainForMultiGoroutine}
+	res := make([]CheckResult, 0)
+	for _, chain := range chains {
+		for _, collector := range chain {
+			name := colcommon.GetCacheKey(collector)
+			check := CheckResult{Name: checkGroupPrefix + name, Critical: true, Healthy: true}
+			t, ok := lastSuccess[name]
+			if !ok {
+				check.Healthy = false
+				check.Message = "group has not finished a collect cycle yet"
+				res = append(res, check)
+				continue
+			}
+			check.LastSuccess = &t
+			if time.Since(t) > c.staleTime {
+				check.Healthy = false
+				check.Message = fmt.Sprintf("group has not succeeded for more than %v", c.staleTime)
+			}
+			res = append(res, check)
+		}
+	}
+	return res
+}
+
+// LivenessHandler handler of the liveness probe
+func (c *Checker) LivenessHandler() http.Handler {
+	return c.handler(false)
+}
+
+// ReadinessHandler handler of the readiness probe
+func (c *Checker) ReadinessHandler() http.Handler {
+	return c.handler(true)
+}
+
+func (c *Checker) handler(readiness bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		report := c.Check(readiness)
+		code := http.StatusOK
+		if report.Status != statusOK {
+			code = http.StatusServiceUnavailable
+		}
+		data, err := json.Marshal(report)
+		if err != nil {
+			logger.Errorf("marshal health report failed: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		if _, err = w.Write(data); err != nil {
+			logger.Errorf("write to response error: %v", err)
+		}
+	})
+}
+
+// Describe desc metrics of health check
+func (c *Checker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descCheckStatus
+	ch <- descGroupLastSuccess
+}
+
+// Collect update metrics of health check, the readiness checks are exported
+func (c *Checker) Collect(ch chan<- prometheus.Metric) {
+	for _, check := range c.Check(true).Checks {
+		value := 0.0
+		if check.Healthy {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(descCheckStatus, prometheus.GaugeValue, value, check.Name,
+			strconv.FormatBool(check.Critical))
+		if check.LastSuccess != nil && strings.HasPrefix(check.Name, checkGroupPrefix) {
+			ch <- prometheus.MustNewConstMetric(descGroupLastSuccess, prometheus.GaugeValue,
+				float64(check.LastSuccess.Unix()), strings.TrimPrefix(check.Name, checkGroupPrefix))
+		}
+	}
+}
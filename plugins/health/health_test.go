@@ -0,0 +1,131 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package health for health and readiness check of npu-exporter
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/smartystreets/goconvey/convey"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/hwlog"
+	"github.com/professorshandian/npu-exporter/ascend-common/devmanager"
+	"github.com/professorshandian/npu-exporter/ascend-common/devmanager/hccn"
+	colcommon "github.com/professorshandian/npu-exporter/collector/common"
+	"github.com/professorshandian/npu-exporter/collector/container"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	num5         = 5
+	mockGroup    = "mockCollector"
+	metricsCount = 10
+)
+
+type mockCollector struct {
+	colcommon.MetricsCollectorAdapter
+}
+
+func init() {
+	logger.HwLogConfig = &hwlog.LogConfig{
+		OnlyToStdout: true,
+	}
+	logger.InitLogger("Prometheus")
+}
+
+func mockChecker() *Checker {
+	colcommon.ChainForSingleGoroutine = []colcommon.MetricsCollector{&mockCollector{}}
+	colcommon.ChainForMultiGoroutine = nil
+	n := colcommon.NewNpuCollector(time.Duration(num5), time.Duration(num5), &container.DevicesParser{},
+		&devmanager.DeviceManager{})
+	return NewChecker(n, time.Second)
+}
+
+func mockPatches(groups map[string]time.Time, status colcommon.ContainerRuntimeStatus) *gomonkey.Patches {
+	return gomonkey.ApplyFuncReturn(colcommon.GetChipList, []colcommon.HuaWeiAIChip{{LogicID: 0}}).
+		ApplyFuncReturn(colcommon.GetContainerRuntimeStatus, status).
+		ApplyFuncReturn(colcommon.GetGroupLastSuccess, groups).
+		ApplyFuncReturn(hccn.CheckHccnTool, errors.New("hccn_tool not found"))
+}
+
+func mockHealthyPatches(groupTime time.Time) *gomonkey.Patches {
+	return mockPatches(map[string]time.Time{mockGroup: groupTime},
+		colcommon.ContainerRuntimeStatus{LastSuccess: time.Now()})
+}
+
+func serve(h http.Handler) int {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+	return rec.Code
+}
+
+func TestCheck(t *testing.T) {
+	convey.Convey("test health check", t, func() {
+		checker := mockChecker()
+		convey.Convey("all subsystems are healthy, hccn_tool is not critical", func() {
+			patches := mockHealthyPatches(time.Now())
+			defer patches.Reset()
+			convey.So(serve(checker.ReadinessHandler()), convey.ShouldEqual, http.StatusOK)
+			convey.So(serve(checker.LivenessHandler()), convey.ShouldEqual, http.StatusOK)
+		})
+		convey.Convey("group has not finished a collect cycle", func() {
+			patches := mockPatches(map[string]time.Time{},
+				colcommon.ContainerRuntimeStatus{LastSuccess: time.Now()})
+			defer patches.Reset()
+			convey.So(serve(checker.ReadinessHandler()), convey.ShouldEqual, http.StatusServiceUnavailable)
+			convey.So(serve(checker.LivenessHandler()), convey.ShouldEqual, http.StatusOK)
+		})
+		convey.Convey("group is stale, only readiness fails", func() {
+			patches := mockHealthyPatches(time.Now().Add(-time.Hour))
+			defer patches.Reset()
+			convey.So(serve(checker.ReadinessHandler()), convey.ShouldEqual, http.StatusServiceUnavailable)
+			convey.So(serve(checker.LivenessHandler()), convey.ShouldEqual, http.StatusOK)
+		})
+		convey.Convey("container info collect is stopped", func() {
+			patches := mockPatches(map[string]time.Time{mockGroup: time.Now()},
+				colcommon.ContainerRuntimeStatus{Stopped: true, LastError: "connection refused"})
+			defer patches.Reset()
+			report := checker.Check(false)
+			convey.So(report.Status, convey.ShouldEqual, statusFail)
+		})
+		convey.Convey("device manager is not initialized", func() {
+			patches := mockHealthyPatches(time.Now())
+			defer patches.Reset()
+			report := NewChecker(nil, time.Second).Check(false)
+			convey.So(report.Status, convey.ShouldEqual, statusFail)
+		})
+	})
+}
+
+func TestCollect(t *testing.T) {
+	convey.Convey("test health check metrics", t, func() {
+		checker := mockChecker()
+		patches := mockHealthyPatches(time.Now())
+		defer patches.Reset()
+		descCh := make(chan *prometheus.Desc, metricsCount)
+		checker.Describe(descCh)
+		convey.So(len(descCh), convey.ShouldEqual, len([]*prometheus.Desc{descCheckStatus, descGroupLastSuccess}))
+		ch := make(chan prometheus.Metric, metricsCount)
+		checker.Collect(ch)
+		// devmanager, container runtime, hccn_tool, group status and group last success
+		convey.So(len(ch), convey.ShouldEqual, num5)
+	})
+}
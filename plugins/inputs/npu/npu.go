@@ -17,6 +17,7 @@ package npu
 
 import (
 	_ "embed"
+	"strconv"
 	"strings"
 
 	"github.com/influxdata/telegraf"
@@ -66,11 +67,16 @@ func (npu *WatchNPU) Gather(acc telegraf.Accumulator) error {
 	fieldsMap = npu.gatherChain(fieldsMap, colcommon.ChainForSingleGoroutine, containerMap, chips)
 	fieldsMap = npu.gatherChain(fieldsMap, colcommon.ChainForMultiGoroutine, containerMap, chips)
 
+	// the node identity labels are added as tags of every device
+	nodeTags := colcommon.NodeLabels()
 	generalFields := fieldsMap[colcommon.GeneralDevTagKey]
-	acc.AddFields(devName, generalFields, map[string]string{"device": devTagValue})
+	generalTag := map[string]string{"device": devTagValue}
+	addTags(generalTag, nodeTags)
+	acc.AddFields(devName, generalFields, generalTag)
 
 	// after the report is completed, deleted to avoid repeated reporting in the for loop
 	delete(fieldsMap, colcommon.GeneralDevTagKey)
+	chipMap := buildChipMap(chips)
 	for key, fields := range fieldsMap {
 
 		ids := strings.Split(key, "_")
@@ -78,6 +84,10 @@ func (npu *WatchNPU) Gather(acc telegraf.Accumulator) error {
 		if len(ids) >= num2 {
 			devTag["vdev_id"] = ids[1]
 		}
+		if chip, ok := chipMap[key]; ok {
+			addChipTags(devTag, chip, containerMap)
+		}
+		addTags(devTag, nodeTags)
 
 		acc.AddFields(devName, fields, devTag)
 	}
@@ -88,10 +98,59 @@ func (npu *WatchNPU) Gather(acc telegraf.Accumulator) error {
 func (npu *WatchNPU) gatherChain(fieldsMap map[string]map[string]interface{}, chain []colcommon.MetricsCollector,
 	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) map[string]map[string]interface{} {
 
+	sink := colcommon.NewSampleSink()
 	for _, collector := range chain {
-		fieldsMap = collector.UpdateTelegraf(fieldsMap, npu.collector, containerMap, chips)
+		collector.UpdateSamples(sink, npu.collector, containerMap, chips)
+	}
+	return colcommon.ToTelegrafFields(colcommon.RelabelSamples(sink.Samples()), fieldsMap)
+}
+
+// buildChipMap index chips by the key of fieldsMap, which is logicID for chip and logicID_vdevID for vNPU
+func buildChipMap(chips []colcommon.HuaWeiAIChip) map[string]*colcommon.HuaWeiAIChip {
+	chipMap := make(map[string]*colcommon.HuaWeiAIChip, len(chips))
+	for i := range chips {
+		chip := &chips[i]
+		key := strconv.Itoa(int(chip.LogicID))
+		if _, ok := chipMap[key]; !ok {
+			chipMap[key] = chip
+		}
+		if chip.VDevActivityInfo != nil && common.IsValidVDevID(chip.VDevActivityInfo.VDevID) {
+			chipMap[key+"_"+strconv.Itoa(int(chip.VDevActivityInfo.VDevID))] = chip
+		}
+	}
+	return chipMap
+}
+
+func addTags(devTag map[string]string, tags map[string]string) {
+	for name, value := range tags {
+		devTag[name] = value
+	}
+}
+
+// addChipTags add npu_uuid and the container tags, so that the usage can be attributed to workloads
+func addChipTags(devTag map[string]string, chip *colcommon.HuaWeiAIChip,
+	containerMap map[int32]container.DevicesInfo) {
+	if chip.VDieID != "" {
+		devTag["npu_uuid"] = chip.VDieID
+	}
+	deviceID := chip.DeviceID
+	if _, isVDev := devTag["vdev_id"]; isVDev && chip.VDevActivityInfo != nil && chip.VDevActivityInfo.IsVirtualDev {
+		deviceID = int32(chip.VDevActivityInfo.VDevID)
+	}
+	devInfo, ok := containerMap[deviceID]
+	if !ok || devInfo.Name == "" {
+		return
+	}
+	names := strings.Split(devInfo.Name, "_")
+	if len(names) != colcommon.ContainerNameLen {
+		logger.Debugf("container name %s of device %d is not in the format of namespace_pod_container",
+			devInfo.Name, deviceID)
+		return
 	}
-	return fieldsMap
+	devTag["namespace"] = names[colcommon.NameSpaceIdx]
+	devTag["pod_name"] = names[colcommon.PodNameIdx]
+	devTag["container_name"] = names[colcommon.ConNameIdx]
+	addTags(devTag, colcommon.MetadataValues(devInfo))
 }
 
 func init() {
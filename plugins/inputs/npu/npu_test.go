@@ -92,7 +92,7 @@ func TestGather(t *testing.T) {
 			patches.ApplyMethodReturn(npu.collector.Dmgr, "GetDevType", tt.deviceType)
 			patches.ApplyFuncReturn(colcommon.GetContainerNPUInfo, nil)
 			patches.ApplyFuncReturn(colcommon.GetChipListWithVNPU, nil)
-			patches.ApplyMethodReturn(colcommon.ChainForSingleGoroutine[0], "UpdateTelegraf",
+			patches.ApplyFuncReturn(colcommon.ToTelegrafFields,
 				map[string]map[string]interface{}{
 					colcommon.GeneralDevTagKey: {"npu_exporter_version_info": "7.0.0"},
 					"0":                        {"npu_chip_info_power": "1"},
@@ -106,6 +106,51 @@ func TestGather(t *testing.T) {
 	}
 }
 
+// TestGatherContainerTags verifies the container and uuid tags of chip and vNPU
+func TestGatherContainerTags(t *testing.T) {
+	npu := &WatchNPU{
+		collector: mockNewNpuCollector(),
+	}
+	acc := &MockAccumulator{}
+	const vDevID = 100
+	convey.Convey("TestGatherContainerTags", t, func() {
+		patches := gomonkey.NewPatches()
+		defer patches.Reset()
+
+		patches.ApplyMethodReturn(npu.collector.Dmgr, "GetDevType", common.Ascend310P)
+		patches.ApplyFuncReturn(colcommon.GetContainerNPUInfo, map[int32]container.DevicesInfo{
+			0:      {Name: "ns1_pod1_c1"},
+			vDevID: {Name: "ns2_pod2_c2"},
+		})
+		patches.ApplyFuncReturn(colcommon.GetChipListWithVNPU, []colcommon.HuaWeiAIChip{
+			{DeviceID: 0, LogicID: 0, VDieID: "uuid-0"},
+			{DeviceID: 1, LogicID: 1, VDieID: "uuid-1", VDevActivityInfo: &common.VDevActivityInfo{
+				VDevID: vDevID, IsVirtualDev: true}},
+		})
+		patches.ApplyFuncReturn(colcommon.ToTelegrafFields,
+			map[string]map[string]interface{}{
+				"0":     {"npu_chip_info_power": "1"},
+				"1_100": {"npu_chip_info_voltage": "1"},
+			})
+
+		err := npu.Gather(acc)
+		convey.So(err, convey.ShouldBeNil)
+		var chipTagged, vDevTagged bool
+		for key := range acc.fields {
+			if strings.Contains(key, "device="+strings.ToLower(common.Ascend310P)+"-0") {
+				chipTagged = strings.Contains(key, "namespace=ns1") && strings.Contains(key, "pod_name=pod1") &&
+					strings.Contains(key, "container_name=c1") && strings.Contains(key, "npu_uuid=uuid-0")
+			}
+			if strings.Contains(key, "vdev_id=100") {
+				vDevTagged = strings.Contains(key, "namespace=ns2") && strings.Contains(key, "container_name=c2") &&
+					strings.Contains(key, "npu_uuid=uuid-1")
+			}
+		}
+		convey.So(chipTagged, convey.ShouldBeTrue)
+		convey.So(vDevTagged, convey.ShouldBeTrue)
+	})
+}
+
 // TestGatherChain tests the gatherChain method of WatchNPU
 func TestGatherChain(t *testing.T) {
 	npu := &WatchNPU{}
@@ -0,0 +1,233 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package otlp for exporting metrics in OpenTelemetry OTLP protocol
+package otlp
+
+import (
+	"sort"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+
+	"github.com/professorshandian/npu-exporter/utils/logger"
+	"github.com/professorshandian/npu-exporter/versions"
+)
+
+const (
+	scopeName   = "npu-exporter"
+	serviceName = "npu-exporter"
+	keySep      = "\xff"
+	msPerNano   = 1e6
+)
+
+// resourceLabels prometheus labels which are moved to resource attributes, value is the attribute key
+var resourceLabels = []struct {
+	label string
+	attr  string
+}{
+	{label: "id", attr: "npu.chip.id"},
+	{label: "namespace", attr: "k8s.namespace.name"},
+	{label: "pod_name", attr: "k8s.pod.name"},
+	{label: "container_name", attr: "k8s.container.name"},
+}
+
+type resourceBuilder struct {
+	resource *metricspb.ResourceMetrics
+	metrics  map[string]*metricspb.Metric
+	starts   *startTracker
+}
+
+// counterStart the start time of the cumulative counter series and its last observed point
+type counterStart struct {
+	start uint64
+	last  float64
+	ts    uint64
+}
+
+// startTracker tracks the start time of cumulative counter series across exports. the start of series observed
+// in the first export is the start time of exporter, and of the later ones is the time they are first observed.
+// the start is moved to the previous point when the counter is reset
+type startTracker struct {
+	// initNano the start time of exporter, zero after the first export
+	initNano uint64
+	series   map[string]counterStart
+	seen     map[string]counterStart
+}
+
+func newStartTracker(initNano uint64) *startTracker {
+	return &startTracker{initNano: initNano, series: make(map[string]counterStart)}
+}
+
+func (t *startTracker) begin() {
+	t.seen = make(map[string]counterStart, len(t.series))
+}
+
+// end forget the series not observed in this export
+func (t *startTracker) end() {
+	t.series = t.seen
+	t.seen = nil
+	t.initNano = 0
+}
+
+func (t *startTracker) startOf(key string, value float64, ts uint64) uint64 {
+	prev, ok := t.series[key]
+	cur := counterStart{start: prev.start, last: value, ts: ts}
+	switch {
+	case !ok && t.initNano != 0 && t.initNano < ts:
+		cur.start = t.initNano
+	case !ok:
+		cur.start = ts
+	case value < prev.last:
+		cur.start = prev.ts
+	default:
+	}
+	t.seen[key] = cur
+	return cur.start
+}
+
+// convertFamilies convert gathered metric families to resource metrics, every chip and container is a resource,
+// the start time of cumulative counters is got from starts
+func convertFamilies(families []*dto.MetricFamily, nodeName string, nowNano uint64,
+	starts *startTracker) []*metricspb.ResourceMetrics {
+	starts.begin()
+	defer starts.end()
+	builders := make(map[string]*resourceBuilder)
+	keys := make([]string, 0)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			resAttrs, pointAttrs := splitLabels(m.GetLabel())
+			key := attrsKey(resAttrs)
+			builder, ok := builders[key]
+			if !ok {
+				builder = newResourceBuilder(resAttrs, nodeName)
+				builder.starts = starts
+				builders[key] = builder
+				keys = append(keys, key)
+			}
+			ts := nowNano
+			if m.TimestampMs != nil {
+				ts = uint64(m.GetTimestampMs()) * msPerNano
+			}
+			builder.addPoint(family, m, key+keySep+attrsKey(pointAttrs), pointAttrs, ts)
+		}
+	}
+	res := make([]*metricspb.ResourceMetrics, 0, len(keys))
+	for _, key := range keys {
+		res = append(res, builders[key].resource)
+	}
+	return res
+}
+
+func newResourceBuilder(resAttrs []*commonpb.KeyValue, nodeName string) *resourceBuilder {
+	attrs := []*commonpb.KeyValue{stringAttr("service.name", serviceName)}
+	if nodeName != "" {
+		attrs = append(attrs, stringAttr("k8s.node.name", nodeName))
+	}
+	attrs = append(attrs, resAttrs...)
+	return &resourceBuilder{
+		resource: &metricspb.ResourceMetrics{
+			Resource: &resourcepb.Resource{Attributes: attrs},
+			ScopeMetrics: []*metricspb.ScopeMetrics{{
+				Scope: &commonpb.InstrumentationScope{Name: scopeName, Version: versions.BuildVersion},
+			}},
+		},
+		metrics: make(map[string]*metricspb.Metric),
+	}
+}
+
+func (b *resourceBuilder) addPoint(family *dto.MetricFamily, m *dto.Metric, seriesKey string,
+	attrs []*commonpb.KeyValue, ts uint64) {
+	point := &metricspb.NumberDataPoint{Attributes: attrs, TimeUnixNano: ts}
+	switch family.GetType() {
+	case dto.MetricType_COUNTER:
+		value := m.GetCounter().GetValue()
+		point.Value = &metricspb.NumberDataPoint_AsDouble{AsDouble: value}
+		point.StartTimeUnixNano = b.starts.startOf(family.GetName()+keySep+seriesKey, value, ts)
+	case dto.MetricType_GAUGE:
+		point.Value = &metricspb.NumberDataPoint_AsDouble{AsDouble: m.GetGauge().GetValue()}
+	case dto.MetricType_UNTYPED:
+		point.Value = &metricspb.NumberDataPoint_AsDouble{AsDouble: m.GetUntyped().GetValue()}
+	default:
+		logger.Debugf("metric type %v of %s is not supported by otlp exporter", family.GetType(), family.GetName())
+		return
+	}
+	metric, ok := b.metrics[family.GetName()]
+	if !ok {
+		metric = &metricspb.Metric{Name: family.GetName(), Description: family.GetHelp()}
+		if family.GetType() == dto.MetricType_COUNTER {
+			metric.Data = &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+				AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+				IsMonotonic:            true,
+			}}
+		} else {
+			metric.Data = &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{}}
+		}
+		b.metrics[family.GetName()] = metric
+		scope := b.resource.ScopeMetrics[0]
+		scope.Metrics = append(scope.Metrics, metric)
+	}
+	switch data := metric.Data.(type) {
+	case *metricspb.Metric_Sum:
+		data.Sum.DataPoints = append(data.Sum.DataPoints, point)
+	case *metricspb.Metric_Gauge:
+		data.Gauge.DataPoints = append(data.Gauge.DataPoints, point)
+	default:
+	}
+}
+
+// splitLabels split labels into resource attributes and data point attributes, empty labels are dropped
+func splitLabels(pairs []*dto.LabelPair) ([]*commonpb.KeyValue, []*commonpb.KeyValue) {
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		values[pair.GetName()] = pair.GetValue()
+	}
+	resAttrs := make([]*commonpb.KeyValue, 0, len(resourceLabels))
+	for _, rl := range resourceLabels {
+		if v, ok := values[rl.label]; ok {
+			if v != "" {
+				resAttrs = append(resAttrs, stringAttr(rl.attr, v))
+			}
+			delete(values, rl.label)
+		}
+	}
+	names := make([]string, 0, len(values))
+	for name, v := range values {
+		if v != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	pointAttrs := make([]*commonpb.KeyValue, 0, len(names))
+	for _, name := range names {
+		pointAttrs = append(pointAttrs, stringAttr(name, values[name]))
+	}
+	return resAttrs, pointAttrs
+}
+
+func attrsKey(attrs []*commonpb.KeyValue) string {
+	parts := make([]string, 0, len(attrs))
+	for _, attr := range attrs {
+		parts = append(parts, attr.GetKey()+"="+attr.GetValue().GetStringValue())
+	}
+	return strings.Join(parts, keySep)
+}
+
+func stringAttr(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{
+		Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
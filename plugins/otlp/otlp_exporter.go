@@ -0,0 +1,188 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package otlp for exporting metrics in OpenTelemetry OTLP protocol
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	// ProtocolHTTP OTLP/HTTP with protobuf payload
+	ProtocolHTTP = "http"
+	// ProtocolGRPC OTLP/gRPC
+	ProtocolGRPC = "grpc"
+
+	defaultInterval = 15 * time.Second
+	defaultTimeout  = 10 * time.Second
+	maxErrBodyLen   = 512
+
+	headerContentType = "Content-Type"
+	contentTypeProto  = "application/x-protobuf"
+)
+
+// Config config of otlp exporter
+type Config struct {
+	// Endpoint url of OTLP/HTTP receiver such as http://127.0.0.1:4318/v1/metrics,
+	// or host:port of OTLP/gRPC receiver
+	Endpoint string
+	// Protocol http or grpc
+	Protocol string
+	// Interval how often to export metrics
+	Interval time.Duration
+	// Timeout timeout of a single export
+	Timeout time.Duration
+	// NodeName the node name added to resource attributes
+	NodeName string
+}
+
+// Exporter export metrics of gatherer to OTLP receiver
+type Exporter struct {
+	cfg        Config
+	gatherer   prometheus.Gatherer
+	httpClient *http.Client
+	grpcConn   *grpc.ClientConn
+	grpcClient colmetricspb.MetricsServiceClient
+	starts     *startTracker
+	startsLock sync.Mutex
+}
+
+// NewExporter create an instance of Exporter
+func NewExporter(cfg Config, gatherer prometheus.Gatherer) (*Exporter, error) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	e := &Exporter{cfg: cfg, gatherer: gatherer, starts: newStartTracker(uint64(time.Now().UnixNano()))}
+	switch cfg.Protocol {
+	case ProtocolHTTP, "":
+		e.cfg.Protocol = ProtocolHTTP
+		u, err := url.Parse(cfg.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("otlp http endpoint %q is invalid", cfg.Endpoint)
+		}
+		e.httpClient = &http.Client{Timeout: cfg.Timeout}
+	case ProtocolGRPC:
+		if cfg.Endpoint == "" {
+			return nil, fmt.Errorf("otlp grpc endpoint is empty")
+		}
+		conn, err := grpc.Dial(cfg.Endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, fmt.Errorf("dial otlp grpc endpoint failed: %v", err)
+		}
+		e.grpcConn = conn
+		e.grpcClient = colmetricspb.NewMetricsServiceClient(conn)
+	default:
+		return nil, fmt.Errorf("otlp protocol %q is not supported, just support http and grpc", cfg.Protocol)
+	}
+	return e, nil
+}
+
+// Start export metrics on every interval until ctx is done
+func (e *Exporter) Start(ctx context.Context, group *sync.WaitGroup) {
+	group.Add(1)
+	go func() {
+		defer group.Done()
+		defer e.Close()
+		ticker := time.NewTicker(e.cfg.Interval)
+		defer ticker.Stop()
+		for {
+			if err := e.Export(ctx); err != nil {
+				logger.Warnf("export metrics by otlp failed: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				logger.Info("received the stop signal,stop otlp export")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Export gather metrics once and export them
+func (e *Exporter) Export(ctx context.Context) error {
+	families, err := e.gatherer.Gather()
+	if err != nil {
+		logger.Warnf("gather metrics for otlp with error: %v", err)
+	}
+	e.startsLock.Lock()
+	req := &colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: convertFamilies(families, e.cfg.NodeName, uint64(time.Now().UnixNano()), e.starts),
+	}
+	e.startsLock.Unlock()
+	if len(req.ResourceMetrics) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, e.cfg.Timeout)
+	defer cancel()
+	if e.cfg.Protocol == ProtocolGRPC {
+		_, err = e.grpcClient.Export(ctx, req)
+		return err
+	}
+	return e.exportHTTP(ctx, req)
+}
+
+func (e *Exporter) exportHTTP(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) error {
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.Endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set(headerContentType, contentTypeProto)
+	resp, err := e.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrBodyLen))
+	if err != nil {
+		logger.Debugf("read response of otlp export failed: %v", err)
+	}
+	return fmt.Errorf("status: %s, body: %s", resp.Status, string(body))
+}
+
+// Close release the connection of exporter
+func (e *Exporter) Close() {
+	if e.grpcConn == nil {
+		return
+	}
+	if err := e.grpcConn.Close(); err != nil {
+		logger.Warnf("close otlp grpc connection failed: %v", err)
+	}
+}
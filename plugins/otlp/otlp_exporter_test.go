@@ -0,0 +1,213 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package otlp for exporting metrics in OpenTelemetry OTLP protocol
+package otlp
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/smartystreets/goconvey/convey"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/hwlog"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	gaugeName   = "npu_chip_info_temperature"
+	counterName = "npu_exporter_mock_total"
+	mockNode    = "node-1"
+	mockValue   = 42.5
+	chipNum     = 2
+)
+
+func init() {
+	logger.HwLogConfig = &hwlog.LogConfig{
+		OnlyToStdout: true,
+	}
+	logger.InitLogger("Prometheus")
+}
+
+// receiver a local stand-in of OTLP receiver for both http and grpc
+type receiver struct {
+	colmetricspb.UnimplementedMetricsServiceServer
+	lock     sync.Mutex
+	requests []*colmetricspb.ExportMetricsServiceRequest
+}
+
+// Export receive metrics by grpc
+func (r *receiver) Export(_ context.Context,
+	req *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.requests = append(r.requests, req)
+	return &colmetricspb.ExportMetricsServiceResponse{}, nil
+}
+
+// ServeHTTP receive metrics by http
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	exportReq := &colmetricspb.ExportMetricsServiceRequest{}
+	if err = proto.Unmarshal(data, exportReq); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if _, err = r.Export(req.Context(), exportReq); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (r *receiver) received() []*colmetricspb.ExportMetricsServiceRequest {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.requests
+}
+
+func mockGatherer() prometheus.Gatherer {
+	reg := prometheus.NewRegistry()
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: gaugeName, Help: "help"},
+		[]string{"id", "model_name", "container_name"})
+	gauge.WithLabelValues("0", "910B", "c1").Set(mockValue)
+	gauge.WithLabelValues("1", "910B", "").Set(mockValue)
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: counterName, Help: "help"})
+	counter.Add(1)
+	reg.MustRegister(gauge, counter)
+	return reg
+}
+
+func attr(rm *metricspb.ResourceMetrics, key string) string {
+	for _, kv := range rm.GetResource().GetAttributes() {
+		if kv.GetKey() == key {
+			return kv.GetValue().GetStringValue()
+		}
+	}
+	return ""
+}
+
+func checkRequest(req *colmetricspb.ExportMetricsServiceRequest) {
+	// one resource for every chip and one for the metrics which are not related to chip
+	convey.So(len(req.GetResourceMetrics()), convey.ShouldEqual, chipNum+1)
+	for _, rm := range req.GetResourceMetrics() {
+		convey.So(attr(rm, "k8s.node.name"), convey.ShouldEqual, mockNode)
+		metric := rm.GetScopeMetrics()[0].GetMetrics()[0]
+		switch metric.GetName() {
+		case counterName:
+			convey.So(metric.GetSum().GetIsMonotonic(), convey.ShouldBeTrue)
+			point := metric.GetSum().GetDataPoints()[0]
+			convey.So(point.GetStartTimeUnixNano(), convey.ShouldBeGreaterThan, 0)
+			convey.So(point.GetStartTimeUnixNano(), convey.ShouldBeLessThanOrEqualTo, point.GetTimeUnixNano())
+		case gaugeName:
+			points := metric.GetGauge().GetDataPoints()
+			convey.So(len(points), convey.ShouldEqual, 1)
+			convey.So(points[0].GetAsDouble(), convey.ShouldEqual, mockValue)
+			convey.So(points[0].GetAttributes()[0].GetKey(), convey.ShouldEqual, "model_name")
+			if attr(rm, "npu.chip.id") == "0" {
+				convey.So(attr(rm, "k8s.container.name"), convey.ShouldEqual, "c1")
+			}
+		default:
+			convey.So(metric.GetName(), convey.ShouldBeIn, []string{counterName, gaugeName})
+		}
+	}
+}
+
+func TestExportHTTP(t *testing.T) {
+	convey.Convey("test otlp export by http", t, func() {
+		rcv := &receiver{}
+		server := httptest.NewServer(rcv)
+		defer server.Close()
+		e, err := NewExporter(Config{Endpoint: server.URL + "/v1/metrics", NodeName: mockNode}, mockGatherer())
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(e.Export(context.Background()), convey.ShouldBeNil)
+		requests := rcv.received()
+		convey.So(len(requests), convey.ShouldEqual, 1)
+		checkRequest(requests[0])
+	})
+}
+
+func TestExportGRPC(t *testing.T) {
+	convey.Convey("test otlp export by grpc", t, func() {
+		rcv := &receiver{}
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		convey.So(err, convey.ShouldBeNil)
+		server := grpc.NewServer()
+		colmetricspb.RegisterMetricsServiceServer(server, rcv)
+		go func() {
+			if err := server.Serve(lis); err != nil {
+				t.Logf("grpc server stopped: %v", err)
+			}
+		}()
+		defer server.Stop()
+		e, err := NewExporter(Config{Endpoint: lis.Addr().String(), Protocol: ProtocolGRPC, NodeName: mockNode},
+			mockGatherer())
+		convey.So(err, convey.ShouldBeNil)
+		defer e.Close()
+		convey.So(e.Export(context.Background()), convey.ShouldBeNil)
+		requests := rcv.received()
+		convey.So(len(requests), convey.ShouldEqual, 1)
+		checkRequest(requests[0])
+	})
+}
+
+func TestNewExporter(t *testing.T) {
+	convey.Convey("test new exporter with invalid config", t, func() {
+		_, err := NewExporter(Config{Endpoint: "127.0.0.1:4318"}, mockGatherer())
+		convey.So(err, convey.ShouldNotBeNil)
+		_, err = NewExporter(Config{Endpoint: "127.0.0.1:4317", Protocol: "udp"}, mockGatherer())
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
+
+func TestStartTracker(t *testing.T) {
+	const (
+		initNano = 100
+		firstTs  = 200
+		secondTs = 300
+		thirdTs  = 400
+		value    = 10
+		key      = "series"
+	)
+	convey.Convey("test the start time of cumulative counter", t, func() {
+		starts := newStartTracker(initNano)
+		startOf := func(value float64, ts uint64) uint64 {
+			starts.begin()
+			defer starts.end()
+			return starts.startOf(key, value, ts)
+		}
+		convey.So(startOf(value, firstTs), convey.ShouldEqual, initNano)
+		convey.So(startOf(value+1, secondTs), convey.ShouldEqual, initNano)
+		// the counter is reset
+		convey.So(startOf(1, thirdTs), convey.ShouldEqual, secondTs)
+		// the series not observed is forgotten, and restarts at the time it is observed again
+		starts.begin()
+		starts.end()
+		convey.So(startOf(value, thirdTs), convey.ShouldEqual, thirdTs)
+	})
+}
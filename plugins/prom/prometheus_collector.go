@@ -26,24 +26,40 @@ import (
 // CollectorForPrometheus Entry point for collecting and converting
 type CollectorForPrometheus struct {
 	collector *common.NpuCollector
+	// schema the metric schema exported, v1 or v2
+	schema string
 }
 
 // NewPrometheusCollector create an instance of prometheus Collector
 func NewPrometheusCollector(collector *common.NpuCollector) *CollectorForPrometheus {
+	return NewPrometheusCollectorWithSchema(collector, common.SchemaV1)
+}
+
+// NewPrometheusCollectorWithSchema create an instance of prometheus Collector exporting the metric schema
+func NewPrometheusCollectorWithSchema(collector *common.NpuCollector, schema string) *CollectorForPrometheus {
 	promCollector := &CollectorForPrometheus{
 		collector: collector,
+		schema:    schema,
 	}
 	return promCollector
 }
 
 // Describe desc metrics of prometheus
-func (*CollectorForPrometheus) Describe(ch chan<- *prometheus.Desc) {
+func (n *CollectorForPrometheus) Describe(ch chan<- *prometheus.Desc) {
 	if ch == nil {
 		logger.Error("ch is nil ")
 		return
 	}
+	// the v2 metrics are converted from v1 samples when collecting, so it is registered as unchecked collector
+	if n.schema == common.SchemaV2 {
+		return
+	}
 	describeChain(ch, common.ChainForSingleGoroutine)
 	describeChain(ch, common.ChainForMultiGoroutine)
+	// the rates are derived from counters when samples are emitted
+	for _, desc := range common.RateDescs() {
+		ch <- desc
+	}
 }
 
 func describeChain(ch chan<- *prometheus.Desc, chain []common.MetricsCollector) {
@@ -66,7 +82,21 @@ func collectChain(ch chan<- prometheus.Metric, n *CollectorForPrometheus, contai
 		logger.Error("ch is nil")
 		return
 	}
+	sink := common.NewSampleSink()
 	for _, collector := range chain {
-		collector.UpdatePrometheus(ch, n.collector, containerMap, chips)
+		collector.UpdateSamples(sink, n.collector, containerMap, chips)
+	}
+	common.EnrichSamples(sink.Samples(), containerMap)
+	samples := sink.Samples()
+	if n.schema == common.SchemaV2 {
+		samples = common.ToSchemaV2(samples)
+	}
+	for _, sample := range common.RelabelSamples(samples) {
+		metric, err := common.ToPrometheusMetric(sample)
+		if err != nil {
+			logger.Errorf("render sample of %s failed: %v", sample.Name, err)
+			continue
+		}
+		ch <- metric
 	}
 }
@@ -36,6 +36,7 @@ import (
 
 const (
 	maxMetricsCount         = 2000
+	num4                    = 4
 	num5                    = 5
 	mockContainerName       = "mockContainerName"
 	maxChipNum        int32 = 8
@@ -56,6 +57,11 @@ func TestDescribe(t *testing.T) {
 
 			convey.So(ch, convey.ShouldNotBeEmpty)
 		})
+		convey.Convey("test prometheus desc of v2 schema", func() {
+			ch := make(chan *prometheus.Desc, maxMetricsCount)
+			NewPrometheusCollectorWithSchema(nil, common.SchemaV2).Describe(ch)
+			convey.So(ch, convey.ShouldBeEmpty)
+		})
 
 	})
 }
@@ -157,3 +163,56 @@ func initChain() {
 		&metrics.OpticalCollector{},
 	}
 }
+
+// linkSamplesCollector emit the per-link and per-lane v1 samples, whose helps name the index
+type linkSamplesCollector struct {
+	common.MetricsCollectorAdapter
+	descs []*prometheus.Desc
+}
+
+func newLinkSamplesCollector() *linkSamplesCollector {
+	c := &linkSamplesCollector{}
+	for i := 0; i < num4; i++ {
+		index := strconv.Itoa(i)
+		c.descs = append(c.descs,
+			common.BuildCounterDesc("npu_chip_info_hccs_statistic_info_tx_cnt_"+index,
+				"transmitted message count for hccs "+index),
+			common.BuildDesc("npu_chip_info_hccs_bandwidth_info_tx_"+index,
+				"single-link transmission data bandwidth for hccs "+index),
+			common.BuildDesc("npu_chip_optical_tx_power_"+index, "npu interface receive optical-tx-power-"+index))
+	}
+	return c
+}
+
+func (c *linkSamplesCollector) UpdateSamples(sink common.SampleSink, _ *common.NpuCollector,
+	_ map[int32]container.DevicesInfo, _ []common.HuaWeiAIChip) {
+	for _, desc := range c.descs {
+		sample, err := common.NewSample(desc, 1, time.Time{}, make([]string, len(common.CardLabel)))
+		if err == nil {
+			sink.Add(sample)
+		}
+	}
+}
+
+// TestGatherSchemaV2 test the v1 metrics of each link are gathered into one v2 family with a single help
+func TestGatherSchemaV2(t *testing.T) {
+	convey.Convey("TestGatherSchemaV2", t, func() {
+		defer initChain()
+		common.ChainForSingleGoroutine = []common.MetricsCollector{newLinkSamplesCollector()}
+		common.ChainForMultiGoroutine = nil
+		reg := prometheus.NewRegistry()
+		convey.So(reg.Register(NewPrometheusCollectorWithSchema(mockNewNpuCollector(), common.SchemaV2)),
+			convey.ShouldBeNil)
+		families, err := reg.Gather()
+		convey.So(err, convey.ShouldBeNil)
+		counts := make(map[string]int, len(families))
+		for _, family := range families {
+			counts[family.GetName()] = len(family.GetMetric())
+		}
+		convey.So(counts, convey.ShouldResemble, map[string]int{
+			"npu_chip_hccs_transmitted_messages_total": num4,
+			"npu_chip_hccs_transmit_bytes_per_second":  num4,
+			"npu_chip_optical_tx_power":                num4,
+		})
+	})
+}
@@ -0,0 +1,159 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package remotewrite for pushing metrics in prometheus remote write protocol
+package remotewrite
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// field numbers of prometheus.WriteRequest, prometheus.TimeSeries, prometheus.Label and prometheus.Sample
+const (
+	fieldTimeSeries  protowire.Number = 1
+	fieldLabels      protowire.Number = 1
+	fieldSamples     protowire.Number = 2
+	fieldLabelName   protowire.Number = 1
+	fieldLabelValue  protowire.Number = 2
+	fieldSampleValue protowire.Number = 1
+	fieldSampleTime  protowire.Number = 2
+
+	metricNameLabel = "__name__"
+	quantileLabel   = "quantile"
+	bucketLabel     = "le"
+	sumSuffix       = "_sum"
+	countSuffix     = "_count"
+	bucketSuffix    = "_bucket"
+	infBucket       = "+Inf"
+	floatBitSize    = 64
+)
+
+// Label a label of time series
+type Label struct {
+	Name  string
+	Value string
+}
+
+// TimeSeries a time series with a single sample
+type TimeSeries struct {
+	Labels    []Label
+	Value     float64
+	Timestamp int64
+}
+
+// convertFamilies convert gathered metric families to time series, nowMs is used when metric has no timestamp
+func convertFamilies(families []*dto.MetricFamily, externalLabels map[string]string, nowMs int64) []TimeSeries {
+	series := make([]TimeSeries, 0)
+	for _, family := range families {
+		name := family.GetName()
+		for _, m := range family.GetMetric() {
+			ts := nowMs
+			if m.TimestampMs != nil {
+				ts = m.GetTimestampMs()
+			}
+			base := buildLabels(m.GetLabel(), externalLabels)
+			add := func(metricName string, value float64, extra ...Label) {
+				labels := make([]Label, 0, len(base)+len(extra)+1)
+				labels = append(labels, Label{Name: metricNameLabel, Value: metricName})
+				labels = append(labels, base...)
+				labels = append(labels, extra...)
+				sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+				series = append(series, TimeSeries{Labels: labels, Value: value, Timestamp: ts})
+			}
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				add(name, m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				add(name, m.GetGauge().GetValue())
+			case dto.MetricType_SUMMARY:
+				for _, q := range m.GetSummary().GetQuantile() {
+					add(name, q.GetValue(), Label{Name: quantileLabel, Value: formatFloat(q.GetQuantile())})
+				}
+				add(name+sumSuffix, m.GetSummary().GetSampleSum())
+				add(name+countSuffix, float64(m.GetSummary().GetSampleCount()))
+			case dto.MetricType_HISTOGRAM:
+				for _, b := range m.GetHistogram().GetBucket() {
+					add(name+bucketSuffix, float64(b.GetCumulativeCount()),
+						Label{Name: bucketLabel, Value: formatFloat(b.GetUpperBound())})
+				}
+				add(name+bucketSuffix, float64(m.GetHistogram().GetSampleCount()),
+					Label{Name: bucketLabel, Value: infBucket})
+				add(name+sumSuffix, m.GetHistogram().GetSampleSum())
+				add(name+countSuffix, float64(m.GetHistogram().GetSampleCount()))
+			default:
+				add(name, m.GetUntyped().GetValue())
+			}
+		}
+	}
+	return series
+}
+
+// buildLabels merge the labels of metric and external labels, the labels of metric take precedence
+func buildLabels(pairs []*dto.LabelPair, externalLabels map[string]string) []Label {
+	labels := make([]Label, 0, len(pairs)+len(externalLabels))
+	exists := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		labels = append(labels, Label{Name: pair.GetName(), Value: pair.GetValue()})
+		exists[pair.GetName()] = true
+	}
+	for name, value := range externalLabels {
+		if !exists[name] {
+			labels = append(labels, Label{Name: name, Value: value})
+		}
+	}
+	return labels
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return infBucket
+	}
+	return strconv.FormatFloat(f, 'g', -1, floatBitSize)
+}
+
+// marshalWriteRequest encode time series into the protobuf format of prometheus.WriteRequest
+func marshalWriteRequest(series []TimeSeries) []byte {
+	var buf []byte
+	for _, ts := range series {
+		buf = protowire.AppendTag(buf, fieldTimeSeries, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, marshalTimeSeries(ts))
+	}
+	return buf
+}
+
+func marshalTimeSeries(ts TimeSeries) []byte {
+	var buf []byte
+	for _, label := range ts.Labels {
+		var labelBuf []byte
+		labelBuf = protowire.AppendTag(labelBuf, fieldLabelName, protowire.BytesType)
+		labelBuf = protowire.AppendString(labelBuf, label.Name)
+		labelBuf = protowire.AppendTag(labelBuf, fieldLabelValue, protowire.BytesType)
+		labelBuf = protowire.AppendString(labelBuf, label.Value)
+		buf = protowire.AppendTag(buf, fieldLabels, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, labelBuf)
+	}
+	var sampleBuf []byte
+	sampleBuf = protowire.AppendTag(sampleBuf, fieldSampleValue, protowire.Fixed64Type)
+	sampleBuf = protowire.AppendFixed64(sampleBuf, math.Float64bits(ts.Value))
+	sampleBuf = protowire.AppendTag(sampleBuf, fieldSampleTime, protowire.VarintType)
+	sampleBuf = protowire.AppendVarint(sampleBuf, uint64(ts.Timestamp))
+	buf = protowire.AppendTag(buf, fieldSamples, protowire.BytesType)
+	buf = protowire.AppendBytes(buf, sampleBuf)
+	return buf
+}
@@ -0,0 +1,188 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package remotewrite for pushing metrics in prometheus remote write protocol
+package remotewrite
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/utils"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	queueFileSuffix = ".rw"
+	tmpFileSuffix   = ".tmp"
+	// maxQueueFileSize max size of a queued request, unit is MB
+	maxQueueFileSize = 10
+)
+
+// queue pending requests which are not sent yet, the oldest request is dropped when queue is full
+type queue interface {
+	push(data []byte) error
+	peek() ([]byte, bool)
+	pop()
+	len() int
+}
+
+type memoryQueue struct {
+	lock    sync.Mutex
+	items   [][]byte
+	maxSize int
+}
+
+func newMemoryQueue(maxSize int) *memoryQueue {
+	return &memoryQueue{items: make([][]byte, 0), maxSize: maxSize}
+}
+
+func (q *memoryQueue) push(data []byte) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if len(q.items) >= q.maxSize {
+		logger.Warnf("remote write queue is full, drop the oldest request")
+		q.items = q.items[1:]
+	}
+	q.items = append(q.items, data)
+	return nil
+}
+
+func (q *memoryQueue) peek() ([]byte, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if len(q.items) == 0 {
+		return nil, false
+	}
+	return q.items[0], true
+}
+
+func (q *memoryQueue) pop() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if len(q.items) != 0 {
+		q.items = q.items[1:]
+	}
+}
+
+func (q *memoryQueue) len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return len(q.items)
+}
+
+// diskQueue persists every pending request as a file, so that requests survive the restart of exporter
+type diskQueue struct {
+	lock    sync.Mutex
+	dir     string
+	files   []string
+	nextSeq uint64
+	maxSize int
+}
+
+func newDiskQueue(dir string, maxSize int) (*diskQueue, error) {
+	realDir, err := utils.RealDirChecker(dir, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("check remote write queue dir failed: %v", err)
+	}
+	entries, err := os.ReadDir(realDir)
+	if err != nil {
+		return nil, err
+	}
+	q := &diskQueue{dir: realDir, files: make([]string, 0), maxSize: maxSize}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, queueFileSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, queueFileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		q.files = append(q.files, name)
+		if seq >= q.nextSeq {
+			q.nextSeq = seq + 1
+		}
+	}
+	sort.Strings(q.files)
+	logger.Infof("load %d pending remote write requests from %s", len(q.files), realDir)
+	return q, nil
+}
+
+func (q *diskQueue) push(data []byte) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if len(q.files) >= q.maxSize {
+		logger.Warnf("remote write queue is full, drop the oldest request")
+		q.removeHead()
+	}
+	name := fmt.Sprintf("%020d%s", q.nextSeq, queueFileSuffix)
+	tmpPath := filepath.Join(q.dir, name+tmpFileSuffix)
+	if err := os.WriteFile(tmpPath, data, utils.FileMode); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, filepath.Join(q.dir, name)); err != nil {
+		if removeErr := os.Remove(tmpPath); removeErr != nil {
+			logger.Warnf("remove temp file failed: %v", removeErr)
+		}
+		return err
+	}
+	q.nextSeq++
+	q.files = append(q.files, name)
+	return nil
+}
+
+func (q *diskQueue) peek() ([]byte, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for len(q.files) != 0 {
+		path, err := utils.RealFileChecker(filepath.Join(q.dir, q.files[0]), false, false, maxQueueFileSize)
+		if err == nil {
+			var data []byte
+			if data, err = os.ReadFile(path); err == nil {
+				return data, true
+			}
+		}
+		logger.Warnf("read queued request %s failed, drop it: %v", q.files[0], err)
+		q.removeHead()
+	}
+	return nil, false
+}
+
+func (q *diskQueue) pop() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.removeHead()
+}
+
+func (q *diskQueue) len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return len(q.files)
+}
+
+func (q *diskQueue) removeHead() {
+	if len(q.files) == 0 {
+		return
+	}
+	if err := os.Remove(filepath.Join(q.dir, q.files[0])); err != nil && !os.IsNotExist(err) {
+		logger.Warnf("remove queued request failed: %v", err)
+	}
+	q.files = q.files[1:]
+}
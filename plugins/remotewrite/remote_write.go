@@ -0,0 +1,240 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package remotewrite for pushing metrics in prometheus remote write protocol
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/professorshandian/npu-exporter/utils/logger"
+	"github.com/professorshandian/npu-exporter/versions"
+)
+
+const (
+	defaultInterval   = 15 * time.Second
+	defaultTimeout    = 10 * time.Second
+	defaultMaxRetries = 3
+	defaultMinBackoff = 500 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+	defaultQueueSize  = 100
+	maxQueueSize      = 10000
+	maxErrBodyLen     = 512
+	backoffFactor     = 2
+
+	headerEncoding     = "Content-Encoding"
+	headerContentType  = "Content-Type"
+	headerVersion      = "X-Prometheus-Remote-Write-Version"
+	headerUserAgent    = "User-Agent"
+	encodingSnappy     = "snappy"
+	contentTypeProto   = "application/x-protobuf"
+	remoteWriteVersion = "0.1.0"
+)
+
+// Config config of remote write
+type Config struct {
+	// URL the url of remote write receiver
+	URL string
+	// Interval how often to push metrics
+	Interval time.Duration
+	// Timeout timeout of a single request
+	Timeout time.Duration
+	// MaxRetries max retry times of a request before it is kept in queue for the next push
+	MaxRetries int
+	// MinBackoff the initial backoff between retries, doubled after each retry
+	MinBackoff time.Duration
+	// MaxBackoff the upper limit of backoff
+	MaxBackoff time.Duration
+	// QueueSize max number of pending requests, the oldest is dropped when the queue is full
+	QueueSize int
+	// QueueDir pending requests are kept on disk when set, otherwise in memory
+	QueueDir string
+	// ExternalLabels labels added to every time series
+	ExternalLabels map[string]string
+}
+
+// errNotRecoverable the request is rejected by receiver and must not be retried
+var errNotRecoverable = errors.New("request is rejected by remote write receiver")
+
+// Writer push metrics of gatherer to the remote write receiver
+type Writer struct {
+	cfg      Config
+	gatherer prometheus.Gatherer
+	client   *http.Client
+	queue    queue
+}
+
+// NewWriter create an instance of Writer
+func NewWriter(cfg Config, gatherer prometheus.Gatherer) (*Writer, error) {
+	if err := validateConfig(&cfg); err != nil {
+		return nil, err
+	}
+	var q queue = newMemoryQueue(cfg.QueueSize)
+	if cfg.QueueDir != "" {
+		diskQ, err := newDiskQueue(cfg.QueueDir, cfg.QueueSize)
+		if err != nil {
+			return nil, err
+		}
+		q = diskQ
+	}
+	return &Writer{
+		cfg:      cfg,
+		gatherer: gatherer,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		queue:    q,
+	}, nil
+}
+
+func validateConfig(cfg *Config) error {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("remote write url %q is invalid", cfg.URL)
+	}
+	if cfg.QueueSize < 0 || cfg.QueueSize > maxQueueSize {
+		return fmt.Errorf("remote write queue size %d is out of range [0, %d]", cfg.QueueSize, maxQueueSize)
+	}
+	setDefault(&cfg.Interval, defaultInterval)
+	setDefault(&cfg.Timeout, defaultTimeout)
+	setDefault(&cfg.MinBackoff, defaultMinBackoff)
+	setDefault(&cfg.MaxBackoff, defaultMaxBackoff)
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+	if cfg.QueueSize == 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	return nil
+}
+
+func setDefault(d *time.Duration, value time.Duration) {
+	if *d <= 0 {
+		*d = value
+	}
+}
+
+// Start push metrics on every interval until ctx is done
+func (w *Writer) Start(ctx context.Context, group *sync.WaitGroup) {
+	group.Add(1)
+	go func() {
+		defer group.Done()
+		ticker := time.NewTicker(w.cfg.Interval)
+		defer ticker.Stop()
+		for {
+			w.Push(ctx)
+			select {
+			case <-ctx.Done():
+				logger.Info("received the stop signal,stop remote write")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Push gather metrics once, put them into queue and send all pending requests
+func (w *Writer) Push(ctx context.Context) {
+	families, err := w.gatherer.Gather()
+	if err != nil {
+		logger.Warnf("gather metrics for remote write with error: %v", err)
+	}
+	series := convertFamilies(families, w.cfg.ExternalLabels, time.Now().UnixMilli())
+	if len(series) != 0 {
+		if err = w.queue.push(snappy.Encode(nil, marshalWriteRequest(series))); err != nil {
+			logger.Errorf("put remote write request into queue failed: %v", err)
+		}
+	}
+	w.flush(ctx)
+}
+
+func (w *Writer) flush(ctx context.Context) {
+	for {
+		data, ok := w.queue.peek()
+		if !ok {
+			return
+		}
+		err := w.sendWithRetry(ctx, data)
+		if err != nil && !errors.Is(err, errNotRecoverable) {
+			logger.Warnf("remote write failed, %d requests are pending: %v", w.queue.len(), err)
+			return
+		}
+		if err != nil {
+			logger.Errorf("drop remote write request: %v", err)
+		}
+		w.queue.pop()
+	}
+}
+
+func (w *Writer) sendWithRetry(ctx context.Context, data []byte) error {
+	backoff := w.cfg.MinBackoff
+	var err error
+	for i := 0; i <= w.cfg.MaxRetries; i++ {
+		if err = w.send(ctx, data); err == nil || errors.Is(err, errNotRecoverable) {
+			return err
+		}
+		if i == w.cfg.MaxRetries {
+			break
+		}
+		logger.Debugf("remote write failed, retry after %v: %v", backoff, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= backoffFactor
+		if backoff > w.cfg.MaxBackoff {
+			backoff = w.cfg.MaxBackoff
+		}
+	}
+	return err
+}
+
+func (w *Writer) send(ctx context.Context, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(headerEncoding, encodingSnappy)
+	req.Header.Set(headerContentType, contentTypeProto)
+	req.Header.Set(headerVersion, remoteWriteVersion)
+	req.Header.Set(headerUserAgent, "npu-exporter/"+versions.BuildVersion)
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrBodyLen))
+	if err != nil {
+		logger.Debugf("read response of remote write failed: %v", err)
+	}
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+	// 4xx except 429 means the data is invalid, retrying never helps
+	if resp.StatusCode >= http.StatusBadRequest && resp.StatusCode < http.StatusInternalServerError &&
+		resp.StatusCode != http.StatusTooManyRequests {
+		return fmt.Errorf("%w, status: %s, body: %s", errNotRecoverable, resp.Status, string(body))
+	}
+	return fmt.Errorf("status: %s, body: %s", resp.Status, string(body))
+}
@@ -0,0 +1,278 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package remotewrite for pushing metrics in prometheus remote write protocol
+package remotewrite
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/smartystreets/goconvey/convey"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/hwlog"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	mockMetricName = "npu_chip_info_temperature"
+	mockValue      = 42.5
+	nodeLabel      = "node"
+	mockNode       = "node-1"
+	queueDirMode   = 0750
+)
+
+func init() {
+	logger.HwLogConfig = &hwlog.LogConfig{
+		OnlyToStdout: true,
+	}
+	logger.InitLogger("Prometheus")
+}
+
+// receiver a local stand-in of remote write receiver
+type receiver struct {
+	lock     sync.Mutex
+	failures int
+	status   int
+	series   []TimeSeries
+	requests int
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.requests++
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(r.status)
+		return
+	}
+	if req.Header.Get(headerEncoding) != encodingSnappy {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	compressed, err := io.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	series, err := unmarshalWriteRequest(data)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.series = append(r.series, series...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *receiver) received() ([]TimeSeries, int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.series, r.requests
+}
+
+func (r *receiver) fail(times, status int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.failures = times
+	r.status = status
+}
+
+func unmarshalWriteRequest(data []byte) ([]TimeSeries, error) {
+	res := make([]TimeSeries, 0)
+	err := walkFields(data, func(num protowire.Number, v []byte, _ uint64) error {
+		var ts TimeSeries
+		err := walkFields(v, func(num protowire.Number, v []byte, _ uint64) error {
+			if num == fieldLabels {
+				var label Label
+				err := walkFields(v, func(num protowire.Number, v []byte, _ uint64) error {
+					if num == fieldLabelName {
+						label.Name = string(v)
+					} else {
+						label.Value = string(v)
+					}
+					return nil
+				})
+				ts.Labels = append(ts.Labels, label)
+				return err
+			}
+			return walkFields(v, func(num protowire.Number, _ []byte, n uint64) error {
+				if num == fieldSampleValue {
+					ts.Value = math.Float64frombits(n)
+				} else {
+					ts.Timestamp = int64(n)
+				}
+				return nil
+			})
+		})
+		res = append(res, ts)
+		return err
+	})
+	return res, err
+}
+
+func walkFields(data []byte, fn func(protowire.Number, []byte, uint64) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return errors.New("invalid tag")
+		}
+		data = data[n:]
+		var err error
+		switch typ {
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(data)
+			n, err = m, fn(num, v, 0)
+		case protowire.Fixed64Type:
+			v, m := protowire.ConsumeFixed64(data)
+			n, err = m, fn(num, nil, v)
+		case protowire.VarintType:
+			v, m := protowire.ConsumeVarint(data)
+			n, err = m, fn(num, nil, v)
+		default:
+			return errors.New("unexpected wire type")
+		}
+		if n < 0 || err != nil {
+			return errors.New("invalid field")
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+func mockGatherer() prometheus.Gatherer {
+	reg := prometheus.NewRegistry()
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: mockMetricName, Help: "help"}, []string{"id"})
+	gauge.WithLabelValues("0").Set(mockValue)
+	reg.MustRegister(gauge)
+	return reg
+}
+
+func newTestWriter(url string, queueDir string) (*Writer, error) {
+	return NewWriter(Config{
+		URL:            url,
+		MaxRetries:     1,
+		MinBackoff:     time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		QueueDir:       queueDir,
+		ExternalLabels: map[string]string{nodeLabel: mockNode},
+	}, mockGatherer())
+}
+
+func labelValue(ts TimeSeries, name string) string {
+	for _, label := range ts.Labels {
+		if label.Name == name {
+			return label.Value
+		}
+	}
+	return ""
+}
+
+func TestPush(t *testing.T) {
+	convey.Convey("test remote write push", t, func() {
+		rcv := &receiver{}
+		server := httptest.NewServer(rcv)
+		defer server.Close()
+		w, err := newTestWriter(server.URL, "")
+		convey.So(err, convey.ShouldBeNil)
+
+		convey.Convey("series are received with external labels", func() {
+			w.Push(context.Background())
+			series, _ := rcv.received()
+			convey.So(len(series), convey.ShouldEqual, 1)
+			convey.So(labelValue(series[0], metricNameLabel), convey.ShouldEqual, mockMetricName)
+			convey.So(labelValue(series[0], nodeLabel), convey.ShouldEqual, mockNode)
+			convey.So(labelValue(series[0], "id"), convey.ShouldEqual, "0")
+			convey.So(series[0].Value, convey.ShouldEqual, mockValue)
+		})
+		convey.Convey("request is retried with backoff", func() {
+			rcv.fail(1, http.StatusServiceUnavailable)
+			w.Push(context.Background())
+			series, requests := rcv.received()
+			convey.So(len(series), convey.ShouldEqual, 1)
+			convey.So(requests, convey.ShouldEqual, 2)
+		})
+		convey.Convey("request is kept in queue during outage", func() {
+			const outageTimes = 4
+			rcv.fail(outageTimes, http.StatusInternalServerError)
+			w.Push(context.Background())
+			w.Push(context.Background())
+			convey.So(w.queue.len(), convey.ShouldEqual, 2)
+			w.Push(context.Background())
+			series, _ := rcv.received()
+			convey.So(len(series), convey.ShouldEqual, 3)
+			convey.So(w.queue.len(), convey.ShouldEqual, 0)
+		})
+		convey.Convey("request rejected by receiver is dropped", func() {
+			rcv.fail(1, http.StatusBadRequest)
+			w.Push(context.Background())
+			_, requests := rcv.received()
+			convey.So(requests, convey.ShouldEqual, 1)
+			convey.So(w.queue.len(), convey.ShouldEqual, 0)
+		})
+	})
+}
+
+func TestDiskQueue(t *testing.T) {
+	convey.Convey("test remote write with disk queue", t, func() {
+		dir, err := os.MkdirTemp("", "queue")
+		convey.So(err, convey.ShouldBeNil)
+		defer os.RemoveAll(dir)
+		convey.So(os.Chmod(dir, queueDirMode), convey.ShouldBeNil)
+		rcv := &receiver{}
+		server := httptest.NewServer(rcv)
+		defer server.Close()
+
+		const outageTimes = 2
+		rcv.fail(outageTimes, http.StatusInternalServerError)
+		w, err := newTestWriter(server.URL, dir)
+		convey.So(err, convey.ShouldBeNil)
+		w.Push(context.Background())
+		convey.So(w.queue.len(), convey.ShouldEqual, 1)
+
+		// pending request survives the restart of writer
+		w, err = newTestWriter(server.URL, dir)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(w.queue.len(), convey.ShouldEqual, 1)
+		w.Push(context.Background())
+		series, _ := rcv.received()
+		convey.So(len(series), convey.ShouldEqual, 2)
+		convey.So(w.queue.len(), convey.ShouldEqual, 0)
+	})
+}
+
+func TestNewWriter(t *testing.T) {
+	convey.Convey("test new writer with invalid config", t, func() {
+		_, err := NewWriter(Config{URL: "ftp://127.0.0.1"}, mockGatherer())
+		convey.So(err, convey.ShouldNotBeNil)
+		_, err = NewWriter(Config{URL: "http://127.0.0.1", QueueSize: maxQueueSize + 1}, mockGatherer())
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
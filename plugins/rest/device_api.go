@@ -0,0 +1,249 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package rest for json rest api of npu devices
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/devmanager/common"
+	colcommon "github.com/professorshandian/npu-exporter/collector/common"
+	"github.com/professorshandian/npu-exporter/collector/container"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	// DevicesPath url path of the device list api
+	DevicesPath = "/api/v1/devices"
+
+	contentTypeKey  = "Content-Type"
+	contentTypeJSON = "application/json"
+	vDevKeySep      = "_"
+)
+
+// ContainerInfo container which the chip is assigned to
+type ContainerInfo struct {
+	ID        string `json:"id"`
+	Namespace string `json:"namespace"`
+	PodName   string `json:"pod_name"`
+	Name      string `json:"container_name"`
+	// Metadata the allow-listed labels and annotations of pod, keyed by label name
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// VNpuInfo virtual npu created on the chip
+type VNpuInfo struct {
+	VDevID        uint32  `json:"vdev_id"`
+	Name          string  `json:"name"`
+	Status        uint32  `json:"status"`
+	ContainerUsed bool    `json:"container_used"`
+	ContainerID   uint64  `json:"container_id"`
+	AiCore        float64 `json:"aicore"`
+	AiCoreRate    uint32  `json:"aicore_rate"`
+	TotalMemory   uint64  `json:"total_memory"`
+	UsedMemory    uint64  `json:"used_memory"`
+}
+
+// DeviceInfo identity, assignment and latest readings of a chip
+type DeviceInfo struct {
+	LogicID     int32                             `json:"logic_id"`
+	PhyID       int32                             `json:"phy_id"`
+	CardID      int32                             `json:"card_id"`
+	DeviceID    int32                             `json:"device_id"`
+	VDieID      string                            `json:"vdie_id"`
+	PCIeBusInfo string                            `json:"pcie_bus_info"`
+	MainBoardID uint32                            `json:"main_board_id"`
+	ChipInfo    *common.ChipInfo                  `json:"chip_info,omitempty"`
+	VNpus       []VNpuInfo                        `json:"vnpus,omitempty"`
+	Container   *ContainerInfo                    `json:"container,omitempty"`
+	Readings    map[string]map[string]interface{} `json:"readings"`
+}
+
+// DeviceHandler http handler of the device api
+type DeviceHandler struct {
+	collector *colcommon.NpuCollector
+}
+
+// NewDeviceHandler create an instance of DeviceHandler
+func NewDeviceHandler(collector *colcommon.NpuCollector) *DeviceHandler {
+	return &DeviceHandler{collector: collector}
+}
+
+// ServeHTTP serve /api/v1/devices and /api/v1/devices/{logicID}
+func (h *DeviceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	subPath := strings.Trim(strings.TrimPrefix(r.URL.Path, DevicesPath), "/")
+	devices := h.buildDevices()
+	if subPath == "" {
+		writeJSON(w, http.StatusOK, devices)
+		return
+	}
+	logicID, err := strconv.ParseInt(subPath, 10, 32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid logic id")
+		return
+	}
+	for _, device := range devices {
+		if device.LogicID == int32(logicID) {
+			writeJSON(w, http.StatusOK, device)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "device not found")
+}
+
+func (h *DeviceHandler) buildDevices() []DeviceInfo {
+	devices := make([]DeviceInfo, 0)
+	if h.collector == nil {
+		return devices
+	}
+	containerMap := colcommon.GetContainerNPUInfo(h.collector)
+	chips := colcommon.GetChipListWithVNPU(h.collector)
+	readings := collectReadings(h.collector, containerMap, chips)
+	for _, chip := range colcommon.GetChipList(h.collector) {
+		device := DeviceInfo{
+			LogicID:     chip.LogicID,
+			PhyID:       chip.PhyId,
+			CardID:      chip.CardId,
+			DeviceID:    chip.DeviceID,
+			VDieID:      chip.VDieID,
+			PCIeBusInfo: chip.PCIeBusInfo,
+			MainBoardID: chip.MainBoardId,
+			ChipInfo:    chip.ChipInfo,
+			VNpus:       buildVNpus(chip.VDevInfos),
+			Readings:    make(map[string]map[string]interface{}),
+		}
+		if devInfo, ok := containerMap[chip.DeviceID]; ok {
+			device.Container = buildContainerInfo(devInfo)
+		}
+		for group, fieldsMap := range readings {
+			fields := mergeChipFields(fieldsMap, chip.LogicID)
+			if len(fields) != 0 {
+				device.Readings[group] = fields
+			}
+		}
+		devices = append(devices, device)
+	}
+	return devices
+}
+
+// collectReadings get the latest readings of every metrics group, keyed by group name
+func collectReadings(n *colcommon.NpuCollector, containerMap map[int32]container.DevicesInfo,
+	chips []colcommon.HuaWeiAIChip) map[string]map[string]map[string]interface{} {
+	readings := make(map[string]map[string]map[string]interface{})
+	chains := [][]colcommon.MetricsCollector{colcommon.ChainForSingleGoroutine, colcommon.ChainForMultiGoroutine}
+	for _, chain := range chains {
+		for _, c := range chain {
+			sink := colcommon.NewSampleSink()
+			c.UpdateSamples(sink, n, containerMap, chips)
+			readings[colcommon.GetCacheKey(c)] = colcommon.ToTelegrafFields(colcommon.RelabelSamples(sink.Samples()),
+				make(map[string]map[string]interface{}))
+		}
+	}
+	return readings
+}
+
+// mergeChipFields merge fields of the chip and its vnpus, the key of vnpu is logicID_vdevID
+func mergeChipFields(fieldsMap map[string]map[string]interface{}, logicID int32) map[string]interface{} {
+	res := make(map[string]interface{})
+	chipKey := strconv.Itoa(int(logicID))
+	for devTagKey, fields := range fieldsMap {
+		if devTagKey != chipKey && !strings.HasPrefix(devTagKey, chipKey+vDevKeySep) {
+			continue
+		}
+		for k, v := range fields {
+			if devTagKey != chipKey {
+				k = devTagKey + vDevKeySep + k
+			}
+			res[k] = v
+		}
+	}
+	return res
+}
+
+func buildVNpus(vDevInfos *common.VirtualDevInfo) []VNpuInfo {
+	if vDevInfos == nil || len(vDevInfos.VDevInfo) == 0 {
+		return nil
+	}
+	activities := make(map[uint32]common.VDevActivityInfo, len(vDevInfos.VDevActivityInfo))
+	for _, activity := range vDevInfos.VDevActivityInfo {
+		activities[activity.VDevID] = activity
+	}
+	vNpus := make([]VNpuInfo, 0, len(vDevInfos.VDevInfo))
+	for _, vDev := range vDevInfos.VDevInfo {
+		vNpu := VNpuInfo{
+			VDevID:        vDev.VDevID,
+			Name:          vDev.QueryInfo.Name,
+			Status:        vDev.QueryInfo.Status,
+			ContainerUsed: vDev.QueryInfo.IsContainerUsed != 0,
+			ContainerID:   vDev.QueryInfo.ContainerID,
+		}
+		if activity, ok := activities[vDev.VDevID]; ok {
+			vNpu.AiCore = activity.VDevAiCore
+			vNpu.AiCoreRate = activity.VDevAiCoreRate
+			vNpu.TotalMemory = activity.VDevTotalMem
+			vNpu.UsedMemory = activity.VDevUsedMem
+		}
+		vNpus = append(vNpus, vNpu)
+	}
+	return vNpus
+}
+
+func buildContainerInfo(devInfo container.DevicesInfo) *ContainerInfo {
+	info := &ContainerInfo{ID: devInfo.ID}
+	// the name of container is formatted as namespace_podName_containerName
+	names := strings.Split(devInfo.Name, vDevKeySep)
+	if len(names) > colcommon.ConNameIdx {
+		info.Namespace = names[colcommon.NameSpaceIdx]
+		info.PodName = names[colcommon.PodNameIdx]
+		info.Name = names[colcommon.ConNameIdx]
+	}
+	if metadata := colcommon.MetadataValues(devInfo); len(metadata) != 0 {
+		info.Metadata = metadata
+	}
+	return info
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		logger.Errorf("marshal device info failed: %v", err)
+		writeError(w, http.StatusInternalServerError, "marshal device info failed")
+		return
+	}
+	w.Header().Set(contentTypeKey, contentTypeJSON)
+	w.WriteHeader(code)
+	if _, err = w.Write(data); err != nil {
+		logger.Errorf("write to response error: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set(contentTypeKey, contentTypeJSON)
+	w.WriteHeader(code)
+	data, err := json.Marshal(map[string]string{"error": msg})
+	if err != nil {
+		return
+	}
+	if _, err = w.Write(data); err != nil {
+		logger.Errorf("write to response error: %v", err)
+	}
+}
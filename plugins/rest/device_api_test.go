@@ -0,0 +1,163 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package rest for json rest api of npu devices
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/hwlog"
+	"github.com/professorshandian/npu-exporter/ascend-common/devmanager"
+	"github.com/professorshandian/npu-exporter/ascend-common/devmanager/common"
+	colcommon "github.com/professorshandian/npu-exporter/collector/common"
+	"github.com/professorshandian/npu-exporter/collector/container"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	num5              = 5
+	maxChipNum  int32 = 2
+	mockCntName       = "default_pod1_container1"
+	mockField         = "npu_chip_info_utilization"
+	mockVDevID        = 100
+)
+
+var mockDesc = colcommon.BuildDescWithLabel(mockField, "mock utilization", nil)
+
+type mockCollector struct {
+	colcommon.MetricsCollectorAdapter
+}
+
+// UpdateSamples mock the readings of every chip
+func (c *mockCollector) UpdateSamples(sink colcommon.SampleSink, n *colcommon.NpuCollector,
+	containerMap map[int32]container.DevicesInfo, chips []colcommon.HuaWeiAIChip) {
+	for _, chip := range chips {
+		addMockSample(sink.WithDevKey(colcommon.ChipDevKey(chip.LogicID)), float64(chip.LogicID))
+	}
+	addMockSample(sink.WithDevKey(colcommon.VDevKey(0, mockVDevID)), mockVDevID)
+}
+
+func addMockSample(sink colcommon.SampleSink, value float64) {
+	sample, err := colcommon.NewSample(mockDesc, value, time.Time{}, nil)
+	if err != nil {
+		return
+	}
+	sink.Add(sample)
+}
+
+func init() {
+	logger.HwLogConfig = &hwlog.LogConfig{
+		OnlyToStdout: true,
+	}
+	logger.InitLogger("Prometheus")
+}
+
+func mockChips() []colcommon.HuaWeiAIChip {
+	chips := make([]colcommon.HuaWeiAIChip, 0)
+	for id := int32(0); id < maxChipNum; id++ {
+		chips = append(chips, colcommon.HuaWeiAIChip{CardId: id, PhyId: id, DeviceID: id, LogicID: id,
+			ChipInfo: &common.ChipInfo{Name: "910B"}})
+	}
+	chips[0].VDevInfos = &common.VirtualDevInfo{
+		VDevInfo:         []common.CgoVDevQueryStru{{VDevID: mockVDevID}},
+		VDevActivityInfo: []common.VDevActivityInfo{{VDevID: mockVDevID, VDevAiCoreRate: num5}},
+	}
+	return chips
+}
+
+func mockHandler() (*DeviceHandler, *gomonkey.Patches) {
+	colcommon.ChainForSingleGoroutine = []colcommon.MetricsCollector{&mockCollector{}}
+	colcommon.ChainForMultiGoroutine = nil
+	n := colcommon.NewNpuCollector(time.Duration(num5), time.Duration(num5), &container.DevicesParser{},
+		&devmanager.DeviceManager{})
+	patches := gomonkey.ApplyFuncReturn(colcommon.GetChipList, mockChips()).
+		ApplyFuncReturn(colcommon.GetChipListWithVNPU, mockChips()).
+		ApplyFuncReturn(colcommon.GetContainerNPUInfo, map[int32]container.DevicesInfo{
+			0: {ID: "cid", Name: mockCntName, Devices: []int{0}}})
+	return NewDeviceHandler(n), patches
+}
+
+func doRequest(h http.Handler, method, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestDeviceList(t *testing.T) {
+	convey.Convey("test device list api", t, func() {
+		h, patches := mockHandler()
+		defer patches.Reset()
+		rec := doRequest(h, http.MethodGet, DevicesPath)
+		convey.So(rec.Code, convey.ShouldEqual, http.StatusOK)
+		var devices []DeviceInfo
+		convey.So(json.Unmarshal(rec.Body.Bytes(), &devices), convey.ShouldBeNil)
+		convey.So(len(devices), convey.ShouldEqual, maxChipNum)
+		convey.So(devices[0].Container, convey.ShouldNotBeNil)
+		convey.So(devices[0].Container.PodName, convey.ShouldEqual, "pod1")
+		convey.So(devices[1].Container, convey.ShouldBeNil)
+		convey.So(len(devices[0].VNpus), convey.ShouldEqual, 1)
+		convey.So(devices[0].VNpus[0].AiCoreRate, convey.ShouldEqual, num5)
+		readings := devices[0].Readings["mockCollector"]
+		convey.So(readings[mockField], convey.ShouldEqual, 0)
+		convey.So(readings["0_100_"+mockField], convey.ShouldEqual, mockVDevID)
+	})
+}
+
+func TestDeviceByLogicID(t *testing.T) {
+	convey.Convey("test device api by logic id", t, func() {
+		h, patches := mockHandler()
+		defer patches.Reset()
+		convey.Convey("device exists", func() {
+			rec := doRequest(h, http.MethodGet, DevicesPath+"/1")
+			convey.So(rec.Code, convey.ShouldEqual, http.StatusOK)
+			var device DeviceInfo
+			convey.So(json.Unmarshal(rec.Body.Bytes(), &device), convey.ShouldBeNil)
+			convey.So(device.LogicID, convey.ShouldEqual, 1)
+			convey.So(device.Readings["mockCollector"][mockField], convey.ShouldEqual, 1)
+		})
+		convey.Convey("device not found", func() {
+			rec := doRequest(h, http.MethodGet, DevicesPath+"/7")
+			convey.So(rec.Code, convey.ShouldEqual, http.StatusNotFound)
+		})
+		convey.Convey("invalid logic id", func() {
+			rec := doRequest(h, http.MethodGet, DevicesPath+"/abc")
+			convey.So(rec.Code, convey.ShouldEqual, http.StatusBadRequest)
+		})
+		convey.Convey("method not allowed", func() {
+			rec := doRequest(h, http.MethodPost, DevicesPath)
+			convey.So(rec.Code, convey.ShouldEqual, http.StatusMethodNotAllowed)
+		})
+	})
+}
+
+func TestSchemaMapping(t *testing.T) {
+	convey.Convey("test schema mapping api", t, func() {
+		rec := doRequest(SchemaMappingHandler(), http.MethodGet, SchemaMappingPath)
+		convey.So(rec.Code, convey.ShouldEqual, http.StatusOK)
+		var mappings []colcommon.SchemaMapping
+		convey.So(json.Unmarshal(rec.Body.Bytes(), &mappings), convey.ShouldBeNil)
+		convey.So(mappings, convey.ShouldResemble, colcommon.SchemaV2Mappings())
+
+		rec = doRequest(SchemaMappingHandler(), http.MethodPost, SchemaMappingPath)
+		convey.So(rec.Code, convey.ShouldEqual, http.StatusMethodNotAllowed)
+	})
+}
@@ -0,0 +1,36 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package rest for json rest api of npu devices
+package rest
+
+import (
+	"net/http"
+
+	colcommon "github.com/professorshandian/npu-exporter/collector/common"
+)
+
+// SchemaMappingPath url path of the v1 to v2 metric mapping table
+const SchemaMappingPath = "/api/v1/schema/v2/mapping"
+
+// SchemaMappingHandler serve the machine-readable v1 to v2 metric mapping table, for migrating recording rules
+func SchemaMappingHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeJSON(w, http.StatusOK, colcommon.SchemaV2Mappings())
+	})
+}
@@ -0,0 +1,133 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package textfile for writing metrics into the textfile collector directory of node_exporter
+package textfile
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/utils"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	// FileName name of the metrics file written into textfile collector directory
+	FileName = "npu-exporter.prom"
+	// fileMode node_exporter may run as another user of the same group, so group read is allowed
+	fileMode os.FileMode = 0640
+	// maxFileSize max size of the metrics file, unit is MB
+	maxFileSize   = 100
+	tmpFileSuffix = ".tmp"
+)
+
+// Writer write metrics of gatherer into textfile collector directory
+type Writer struct {
+	dir      string
+	interval time.Duration
+	gatherer prometheus.Gatherer
+}
+
+// NewWriter create an instance of Writer, dir must be an existing directory
+func NewWriter(dir string, interval time.Duration, gatherer prometheus.Gatherer) (*Writer, error) {
+	realDir, err := utils.RealDirChecker(dir, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("check textfile collector dir failed: %v", err)
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("textfile write interval %v is invalid", interval)
+	}
+	return &Writer{dir: realDir, interval: interval, gatherer: gatherer}, nil
+}
+
+// Start write metrics on every update cycle until ctx is done
+func (w *Writer) Start(ctx context.Context, group *sync.WaitGroup) {
+	group.Add(1)
+	go func() {
+		defer group.Done()
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			if err := w.Write(); err != nil {
+				logger.Errorf("write metrics into textfile failed: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				logger.Info("received the stop signal,stop textfile write")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Write gather metrics and write them atomically, the temp file is renamed to the metrics file after written
+func (w *Writer) Write() error {
+	families, err := w.gatherer.Gather()
+	if err != nil {
+		logger.Warnf("gather metrics for textfile with error: %v", err)
+	}
+	path := filepath.Join(w.dir, FileName)
+	tmpPath := path + tmpFileSuffix
+	if err = writeTmpFile(tmpPath, families); err != nil {
+		removeTmpFile(tmpPath)
+		return err
+	}
+	if err = utils.SafeChmod(tmpPath, maxFileSize, fileMode); err != nil {
+		removeTmpFile(tmpPath)
+		return err
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		removeTmpFile(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// writeTmpFile the stale temp file left by a crash is removed first, and the temp file is created exclusively, so
+// a symlink planted at its path is never followed
+func writeTmpFile(tmpPath string, families []*dto.MetricFamily) error {
+	removeTmpFile(tmpPath)
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, fileMode)
+	if err != nil {
+		return err
+	}
+	encoder := expfmt.NewEncoder(f, expfmt.FmtText)
+	for _, family := range families {
+		if err = encoder.Encode(family); err != nil {
+			closeErr := f.Close()
+			return fmt.Errorf("encode metrics failed: %v, close file: %v", err, closeErr)
+		}
+	}
+	if err = f.Sync(); err != nil {
+		closeErr := f.Close()
+		return fmt.Errorf("sync metrics file failed: %v, close file: %v", err, closeErr)
+	}
+	return f.Close()
+}
+
+func removeTmpFile(tmpPath string) {
+	if err := os.Remove(tmpPath); err != nil && !os.IsNotExist(err) {
+		logger.Warnf("remove temp metrics file failed: %v", err)
+	}
+}
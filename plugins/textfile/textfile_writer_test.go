@@ -0,0 +1,129 @@
+/* Copyright(C) 2025. Huawei Technologies Co.,Ltd. All rights reserved.
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package textfile for writing metrics into the textfile collector directory of node_exporter
+package textfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/smartystreets/goconvey/convey"
+
+	"github.com/professorshandian/npu-exporter/ascend-common/common-utils/hwlog"
+	"github.com/professorshandian/npu-exporter/utils/logger"
+)
+
+const (
+	mockMetricName = "npu_chip_info_temperature"
+	dirMode        = 0750
+	unsafeDirMode  = 0777
+)
+
+func init() {
+	logger.HwLogConfig = &hwlog.LogConfig{
+		OnlyToStdout: true,
+	}
+	logger.InitLogger("Prometheus")
+}
+
+func mockGatherer() (prometheus.Gatherer, prometheus.Gauge) {
+	reg := prometheus.NewRegistry()
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: mockMetricName, Help: "help"})
+	reg.MustRegister(gauge)
+	return reg, gauge
+}
+
+func mockDir(mode os.FileMode) string {
+	dir, err := os.MkdirTemp("", "textfile")
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(os.Chmod(dir, mode), convey.ShouldBeNil)
+	return dir
+}
+
+func TestWrite(t *testing.T) {
+	convey.Convey("test write metrics into textfile", t, func() {
+		dir := mockDir(dirMode)
+		defer os.RemoveAll(dir)
+		gatherer, gauge := mockGatherer()
+		w, err := NewWriter(dir, time.Second, gatherer)
+		convey.So(err, convey.ShouldBeNil)
+
+		const firstValue, secondValue = 1, 2
+		gauge.Set(firstValue)
+		convey.So(w.Write(), convey.ShouldBeNil)
+		gauge.Set(secondValue)
+		convey.So(w.Write(), convey.ShouldBeNil)
+
+		path := filepath.Join(dir, FileName)
+		data, err := os.ReadFile(path)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(strings.Contains(string(data), mockMetricName+" 2"), convey.ShouldBeTrue)
+		info, err := os.Stat(path)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(info.Mode().Perm(), convey.ShouldEqual, fileMode)
+		_, err = os.Stat(path + tmpFileSuffix)
+		convey.So(os.IsNotExist(err), convey.ShouldBeTrue)
+	})
+}
+
+// TestWriteWithPlantedSymlink test the symlink planted at the path of temp file is not followed
+func TestWriteWithPlantedSymlink(t *testing.T) {
+	convey.Convey("test write metrics with a symlink at the temp file", t, func() {
+		dir := mockDir(dirMode)
+		defer os.RemoveAll(dir)
+		victim := filepath.Join(dir, "victim")
+		const victimContent = "keep"
+		convey.So(os.WriteFile(victim, []byte(victimContent), fileMode), convey.ShouldBeNil)
+		path := filepath.Join(dir, FileName)
+		convey.So(os.Symlink(victim, path+tmpFileSuffix), convey.ShouldBeNil)
+		gatherer, _ := mockGatherer()
+		w, err := NewWriter(dir, time.Second, gatherer)
+		convey.So(err, convey.ShouldBeNil)
+
+		convey.So(w.Write(), convey.ShouldBeNil)
+		data, err := os.ReadFile(victim)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(string(data), convey.ShouldEqual, victimContent)
+		info, err := os.Lstat(path)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(info.Mode().IsRegular(), convey.ShouldBeTrue)
+	})
+}
+
+func TestNewWriter(t *testing.T) {
+	convey.Convey("test new writer with invalid dir", t, func() {
+		gatherer, _ := mockGatherer()
+		convey.Convey("dir does not exist", func() {
+			_, err := NewWriter("/not/exist/dir", time.Second, gatherer)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+		convey.Convey("dir is writable by others", func() {
+			dir := mockDir(unsafeDirMode)
+			defer os.RemoveAll(dir)
+			_, err := NewWriter(dir, time.Second, gatherer)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+		convey.Convey("interval is invalid", func() {
+			dir := mockDir(dirMode)
+			defer os.RemoveAll(dir)
+			_, err := NewWriter(dir, 0, gatherer)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+	})
+}